@@ -548,7 +548,7 @@ func (app *HeimdallApp) InitChainer(ctx sdk.Context, req abci.RequestInitChain)
 	// add to val updates else skip
 	var valUpdates []abci.ValidatorUpdate
 	for _, validator := range stakingState.Validators {
-		if validator.IsCurrentValidator(checkpointState.AckCount) {
+		if validator.IsCurrentValidatorWithGrace(checkpointState.AckCount, stakingState.Params.ValidatorGracePeriod) {
 			// convert to Validator Update
 			updateVal := abci.ValidatorUpdate{
 				Power:  int64(validator.VotingPower),
@@ -593,48 +593,12 @@ func (app *HeimdallApp) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) ab
 		app.AccountKeeper.RemoveBlockProposer(ctx)
 	}
 
-	var tmValUpdates []abci.ValidatorUpdate
-
 	// --- Start update to new validators
-	currentValidatorSet := app.StakingKeeper.GetValidatorSet(ctx)
-	allValidators := app.StakingKeeper.GetAllValidators(ctx)
-	ackCount := app.CheckpointKeeper.GetACKCount(ctx, types.RootChainTypeStake)
-
-	// get validator updates
-	setUpdates := helper.GetUpdatedValidators(
-		&currentValidatorSet, // pointer to current validator set -- UpdateValidators will modify it
-		allValidators,        // All validators
-		ackCount,             // ack count
-	)
-
-	if len(setUpdates) > 0 {
-		// create new validator set
-		if err := currentValidatorSet.UpdateWithChangeSet(setUpdates); err != nil {
-			// return with nothing
-			logger.Error("Unable to update current validator set", "Error", err)
-			return abci.ResponseEndBlock{}
-		}
-
-		// increment proposer priority
-		currentValidatorSet.IncrementProposerPriority(1)
-
-		// validator set change
-		logger.Debug("[ENDBLOCK] Updated current validator set", "proposer", currentValidatorSet.GetProposer())
-
-		// save set in store
-		if err := app.StakingKeeper.UpdateValidatorSetInStore(ctx, currentValidatorSet); err != nil {
-			// return with nothing
-			logger.Error("Unable to update current validator set in state", "Error", err)
-			return abci.ResponseEndBlock{}
-		}
-
-		// convert updates from map to array
-		for _, v := range setUpdates {
-			tmValUpdates = append(tmValUpdates, abci.ValidatorUpdate{
-				Power:  int64(v.VotingPower),
-				PubKey: v.PubKey.ABCIPubKey(),
-			})
-		}
+	tmValUpdates, err := app.StakingKeeper.GetValidatorUpdates(ctx)
+	if err != nil {
+		// return with nothing
+		logger.Error("Unable to update current validator set", "Error", err)
+		return abci.ResponseEndBlock{}
 	}
 
 	// end block
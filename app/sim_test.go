@@ -68,6 +68,7 @@ func TestFullAppSimulation(t *testing.T) {
 	err = CheckExportSimulation(app, config, simParams)
 	require.NoError(t, err)
 	require.NoError(t, simErr)
+	require.NoError(t, CheckInvariants(app))
 
 	if config.Commit {
 		PrintStats(db)
@@ -100,6 +101,7 @@ func TestAppImportExport(t *testing.T) {
 	err = CheckExportSimulation(app, config, simParams)
 	require.NoError(t, err)
 	require.NoError(t, simErr)
+	require.NoError(t, CheckInvariants(app))
 
 	if config.Commit {
 		PrintStats(db)
@@ -177,6 +179,7 @@ func TestAppSimulationAfterImport(t *testing.T) {
 	err = CheckExportSimulation(app, config, simParams)
 	require.NoError(t, err)
 	require.NoError(t, simErr)
+	require.NoError(t, CheckInvariants(app))
 
 	if config.Commit {
 		PrintStats(db)
@@ -215,6 +218,7 @@ func TestAppSimulationAfterImport(t *testing.T) {
 	)
 	require.False(t, stopEarly)
 	require.NoError(t, err)
+	require.NoError(t, CheckInvariants(newApp))
 }
 
 // TODO: Make another test for the fuzzer itself, which just has noOp txs
@@ -7,6 +7,7 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
 	dbm "github.com/tendermint/tm-db"
 
@@ -99,6 +100,38 @@ func CheckExportSimulation(
 	return nil
 }
 
+// invariantRegistry collects the invariants registered by the app modules
+type invariantRegistry struct {
+	routes []invariantRoute
+}
+
+type invariantRoute struct {
+	moduleName string
+	route      string
+	invar      sdk.Invariant
+}
+
+// RegisterRoute implements sdk.InvariantRegistry
+func (ir *invariantRegistry) RegisterRoute(moduleName, route string, invar sdk.Invariant) {
+	ir.routes = append(ir.routes, invariantRoute{moduleName: moduleName, route: route, invar: invar})
+}
+
+// CheckInvariants runs the invariants of all app modules against the last committed state.
+// The app has no crisis module, so module invariants are asserted only here, after the simulations.
+func CheckInvariants(app *HeimdallApp) error {
+	ir := &invariantRegistry{}
+	app.mm.RegisterInvariants(ir)
+
+	ctx := app.NewContext(true, abci.Header{Height: app.LastBlockHeight()})
+	for _, route := range ir.routes {
+		if msg, broken := route.invar(ctx); broken {
+			return fmt.Errorf("invariant %s/%s broken at height %d: %s", route.moduleName, route.route, ctx.BlockHeight(), msg)
+		}
+	}
+
+	return nil
+}
+
 // PrintStats prints the corresponding statistics from the app DB.
 func PrintStats(db dbm.DB) {
 	fmt.Println("\nLevelDB Stats")
@@ -2,7 +2,9 @@ package listener
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/maticnetwork/heimdall/bridge/setu/queue"
@@ -80,6 +83,11 @@ type blockHeader struct {
 	isFinalized bool          // if the block is a finalized block or not
 }
 
+// isValid reports whether the header can be processed, clients may deliver nil headers after errors
+func (h *blockHeader) isValid() bool {
+	return h != nil && h.header != nil && h.header.Number != nil && h.header.Number.Sign() > 0
+}
+
 // NewBaseListener creates a new BaseListener.
 func NewBaseListener(cdc *codec.Codec, queueConnector *queue.QueueConnector, httpClient *httpClient.HTTP, chainClient *ethclient.Client, name string, impl Listener) *BaseListener {
 
@@ -112,6 +120,40 @@ func NewBaseListener(cdc *codec.Codec, queueConnector *queue.QueueConnector, htt
 	}
 }
 
+// applyLogLevel restricts the listener logger to the level configured for this listener, if any.
+// Levels are configured as comma separated "<listener>:<level>" pairs.
+func (bl *BaseListener) applyLogLevel() {
+	level, ok := listenerLogLevel(helper.GetConfig().ListenerLogLevel, bl.name)
+	if !ok {
+		return
+	}
+
+	// log filter has no warn option, warn-only maps to the closest stricter level
+	if level == "warn" {
+		level = "error"
+	}
+
+	option, err := log.AllowLevel(level)
+	if err != nil {
+		bl.Logger.Error("Unable to parse listener log level", "level", level, "error", err)
+		return
+	}
+
+	bl.Logger = log.NewFilter(bl.Logger, option)
+}
+
+// listenerLogLevel returns the level configured for the given listener name
+func listenerLogLevel(levels string, name string) (string, bool) {
+	for _, item := range strings.Split(levels, ",") {
+		pair := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		if len(pair) == 2 && pair[0] == name {
+			return strings.TrimSpace(pair[1]), true
+		}
+	}
+
+	return "", false
+}
+
 // // Start starts new block subscription
 // func (bl *BaseListener) Start() error {
 // 	bl.Logger.Info("Starting listener", "name", bl.String())
@@ -269,3 +311,66 @@ func (bl *BaseListener) setStartListenBLock(StartBlock uint64, key string) error
 	}
 	return nil
 }
+
+// validateEvents checks that each required event name resolves to a topic in the registered ABIs
+func validateEvents(abis []*abi.ABI, eventNames []string) error {
+	var missing []string
+	for _, name := range eventNames {
+		found := false
+		for _, abiObject := range abis {
+			if event, ok := abiObject.Events[name]; ok && helper.EventByID(abiObject, event.ID.Bytes()) != nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("events not registered in listener ABIs: %v", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// detectEventCollisions checks that the topic of each handled event is defined by only one of the registered ABIs,
+// as logs are dispatched once for every ABI resolving their topic
+func detectEventCollisions(abis []*abi.ABI, eventNames []string) error {
+	var collisions []string
+	for _, name := range eventNames {
+		for index, abiObject := range abis {
+			event, ok := abiObject.Events[name]
+			if !ok {
+				continue
+			}
+
+			// report each topic once, from the first ABI defining it
+			var definedBefore bool
+			for _, other := range abis[:index] {
+				if helper.EventByID(other, event.ID.Bytes()) != nil {
+					definedBefore = true
+					break
+				}
+			}
+			if definedBefore {
+				continue
+			}
+
+			var names []string
+			for _, other := range abis[index:] {
+				if otherEvent := helper.EventByID(other, event.ID.Bytes()); otherEvent != nil {
+					names = append(names, otherEvent.Name)
+				}
+			}
+			if len(names) > 1 {
+				collisions = append(collisions, fmt.Sprintf("%v (%v)", event.ID.Hex(), strings.Join(names, ", ")))
+			}
+		}
+	}
+
+	if len(collisions) > 0 {
+		return fmt.Errorf("event topics defined by multiple listener ABIs: %v", strings.Join(collisions, "; "))
+	}
+	return nil
+}
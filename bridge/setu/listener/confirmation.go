@@ -0,0 +1,56 @@
+package listener
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ConfirmationStrategy computes the highest root chain block safe to process for a new, not finalized header
+type ConfirmationStrategy interface {
+	SafeCeiling(ctx context.Context, header *types.Header, client *ethclient.Client, confirmations uint64) (*big.Int, error)
+}
+
+// DepthConfirmationStrategy trails the new header by the required confirmations
+type DepthConfirmationStrategy struct{}
+
+// SafeCeiling implements ConfirmationStrategy
+func (DepthConfirmationStrategy) SafeCeiling(_ context.Context, header *types.Header, _ *ethclient.Client, confirmations uint64) (*big.Int, error) {
+	confirmationBlocks := new(big.Int).SetUint64(confirmations)
+	if header.Number.Cmp(confirmationBlocks) <= 0 {
+		return nil, fmt.Errorf("block number %v less than confirmations required %v", header.Number, confirmations)
+	}
+
+	return new(big.Int).Sub(header.Number, confirmationBlocks), nil
+}
+
+// FinalizedConfirmationStrategy uses the finalized block as ceiling, no confirmations required.
+// It falls back to the confirmation depth when the client does not support the finalized tag.
+type FinalizedConfirmationStrategy struct{}
+
+// SafeCeiling implements ConfirmationStrategy
+func (FinalizedConfirmationStrategy) SafeCeiling(ctx context.Context, header *types.Header, client *ethclient.Client, confirmations uint64) (*big.Int, error) {
+	finalized, err := client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil || finalized == nil {
+		return DepthConfirmationStrategy{}.SafeCeiling(ctx, header, client, confirmations)
+	}
+
+	if finalized.Number.Cmp(header.Number) < 0 {
+		return finalized.Number, nil
+	}
+
+	return new(big.Int).Set(header.Number), nil
+}
+
+// defaultConfirmationStrategy returns the strategy selected by config
+func defaultConfirmationStrategy(useFinalized bool) ConfirmationStrategy {
+	if useFinalized {
+		return FinalizedConfirmationStrategy{}
+	}
+
+	return DepthConfirmationStrategy{}
+}
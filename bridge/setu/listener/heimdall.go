@@ -40,6 +40,9 @@ func NewHeimdallListener() *HeimdallListener {
 
 // Start starts new block subscription
 func (hl *HeimdallListener) Start() error {
+	// apply configured log level for this listener
+	hl.applyLogLevel()
+
 	hl.Logger.Info("Starting")
 
 	// create cancellable context
@@ -248,7 +251,7 @@ func (hl *HeimdallListener) sendBlockTask(taskName string, eventBytes []byte, bl
 	hl.Logger.Info("Sending block level task",
 		"taskName", taskName, "eventBytes", eventBytes, "currentTime", time.Now(), "blockHeight", blockHeight)
 	// send task
-	_, err := hl.queueConnector.Server.SendTask(signature)
+	_, err := hl.queueConnector.SendTask(signature)
 	if err != nil {
 		hl.Logger.Error("Error sending block level task", "taskName", taskName, "blockHeight", blockHeight, "error", err)
 	}
@@ -501,7 +504,7 @@ func (hl *HeimdallListener) sendEventTask(taskName string,
 		"taskName", taskName, "eventBytes", eventBytes, "currentTime", time.Now())
 
 	// send task
-	_, err := hl.queueConnector.Server.SendTask(signature)
+	_, err := hl.queueConnector.SendTask(signature)
 	if err != nil {
 		hl.Logger.Error("Error sending event record task", "taskName", taskName, "error", err)
 	}
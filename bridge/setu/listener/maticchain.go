@@ -20,6 +20,9 @@ func NewMaticChainListener() *MaticChainListener {
 
 // Start starts new block subscription
 func (ml *MaticChainListener) Start() error {
+	// apply configured log level for this listener
+	ml.applyLogLevel()
+
 	ml.Logger.Info("Starting")
 
 	// create cancellable context
@@ -45,6 +48,11 @@ func (ml *MaticChainListener) Start() error {
 
 // ProcessHeader - process headerblock from maticchain
 func (ml *MaticChainListener) ProcessHeader(newBlockHeader *blockHeader) {
+	if !newBlockHeader.isValid() {
+		ml.Logger.Error("Ignoring invalid block header")
+		return
+	}
+
 	newHeader := newBlockHeader.header
 	ml.Logger.Debug("New block detected", "blockNumber", newHeader.Number)
 	// Marshall header block and publish to queue
@@ -74,7 +82,7 @@ func (ml *MaticChainListener) sendTaskWithDelay(taskName string, headerBytes []b
 	eta := time.Now().Add(delay)
 	signature.ETA = &eta
 	ml.Logger.Debug("Sending task", "taskname", taskName, "currentTime", time.Now(), "delayTime", eta)
-	_, err := ml.queueConnector.Server.SendTask(signature)
+	_, err := ml.queueConnector.SendTask(signature)
 	if err != nil {
 		ml.Logger.Error("Error sending task", "taskName", taskName, "error", err)
 	}
@@ -1,10 +1,17 @@
 package listener
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/rpc"
@@ -15,8 +22,15 @@ import (
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/maticnetwork/heimdall/bridge/setu/queue"
 	"github.com/maticnetwork/heimdall/bridge/setu/util"
 	chainmanagerTypes "github.com/maticnetwork/heimdall/chainmanager/types"
+	"github.com/maticnetwork/heimdall/contracts/stakinginfo"
 	"github.com/maticnetwork/heimdall/helper"
 )
 
@@ -36,21 +50,130 @@ type RootChainListener struct {
 	blockKey       string
 	pollInterval   time.Duration
 
-	busyLimit      int
-	maxQueryBlocks int64
+	busyLimit        int
+	maxQueryBlocks   int64
+	startListenBlock uint64
+
+	// log queries returning this many logs are bisected as possibly truncated, see filterLogsBisect
+	filterLogsCap int
+
+	// chain id of the connected root chain, see checkChainID
+	expectedChainID uint64
+	chainID         *big.Int
+
+	// batch consecutive StateSynced logs of a block into one task
+	batchEvents bool
+
+	// task payload format of logs, see util.EncodeLogPayload
+	payloadFormat string
+
+	// computes the block ceiling of not finalized headers, see ConfirmationStrategy
+	confirmationStrategy ConfirmationStrategy
+
+	// drop logs of failed transactions before dispatching
+	checkReceipts bool
+
+	// drop logs not provable under their block's receipts root, see dropUnprovenLogs
+	verifyInclusion bool
+	rpcClient       *rpc.Client
+
+	// state synced logs with larger data are dead-lettered, see deadLetterStateSynced
+	maxStateDataSize int
+
+	// window of the per validator task ETA jitter, see util.TaskETAJitter
+	etaJitter time.Duration
+
+	// task delay bounds, see util.ClampTaskDelay
+	minTaskDelay time.Duration
+	maxTaskDelay time.Duration
+
+	// operator-local minimum confirmations, can only raise the governed value
+	minConfirmations uint64
+
+	// task name overrides, keyed by event name
+	taskNames map[string]string
+
+	// task queue of this root chain, empty for the shared queue
+	routingKey string
+
+	// validator set snapshot for task delays, refreshed every snapshotInterval, see calculateTaskDelay
+	validatorSnapshot *util.ValidatorSnapshotCache
+	snapshotInterval  time.Duration
+
+	// health, see IsHealthy
+	healthStaleness time.Duration
+	lastProgress    atomic.Int64 // unix nano time a header was last processed
+	pollingAlive    atomic.Bool
+
+	// confirmed ceiling of the last processed header, see GetSyncProgress
+	chainTip atomic.Uint64
+
+	// cursor reached the confirmed ceiling, see updateCaughtUp
+	caughtUp   atomic.Bool
+	onCaughtUp func()
 
 	stateSyncedCountWithDecay uint64
+
+	// cursor checkpointing, see advanceCursor
+	checkpointBlocks   uint64
+	checkpointInterval time.Duration
+
+	cursorMu           sync.Mutex
+	cursor             *big.Int // last processed block, possibly not yet in storage
+	checkpointedCursor uint64   // last processed block written to storage
+	lastCheckpoint     time.Time
+
+	// task queue backpressure, see isQueueFull
+	maxQueuedTasks      int
+	queuedTasksLowWater int
+	queueFull           atomic.Bool
+	pendingTasks        func() (int, error)
+
+	// operator pause of event dispatch, see Pause
+	paused atomic.Bool
 }
 
 const (
 	lastEthBlockKey = "eth-last-block" // storage key
 	lastBscBlockKey = "bsc-last-block"
 
+	stakeAckNonceKeyPrefix = "stake-ack-nonce" // storage key prefix, per validator
+
+	cursorHashesKeySuffix = "-hashes" // storage key suffix, block hashes of recent cursor checkpoints
+	maxCursorHashes       = 64
+
+	rootChainIDHeader = "root_chain_id" // task header carrying the root chain id
+	blockTimeHeader   = "block_time"    // task header carrying the source block timestamp
+
+	stateSyncedDeadLetterKeyPrefix = "state-synced-dead-letter" // storage key prefix, per log
+
 	decayPerSecond = 30
 )
 
+// errors of skipped dispatches, the cursor is held and the range retried
+var (
+	errDispatchPaused = errors.New("event dispatch paused")
+	errTaskQueueFull  = errors.New("task queue full")
+)
+
+// rootChainEvents are the events dispatched by the rootchain listener
+var rootChainEvents = []string{"NewHeaderBlock", "StateSynced", "StakeAck"}
+
+// defaultRootChainTaskNames maps dispatched events to the names of their task handlers
+var defaultRootChainTaskNames = map[string]string{
+	"NewHeaderBlock": "sendCheckpointAckToHeimdall",
+	"StateSynced":    "sendStateSyncedToHeimdall",
+	"StakeAck":       "sendStakingAckToHeimdall",
+}
+
 // NewRootChainListener - constructor func
 func NewRootChainListener(rootChain string) *RootChainListener {
+	return NewRootChainListenerWithStrategy(rootChain, nil)
+}
+
+// NewRootChainListenerWithStrategy creates a root chain listener using the given confirmation strategy,
+// nil selects the configured default
+func NewRootChainListenerWithStrategy(rootChain string, strategy ConfirmationStrategy) *RootChainListener {
 	contractCaller, err := helper.NewContractCaller()
 	if err != nil {
 		panic(err)
@@ -64,28 +187,119 @@ func NewRootChainListener(rootChain string) *RootChainListener {
 		abis:           abis,
 		stakingInfoAbi: &contractCaller.StakingInfoABI,
 		rootChainType:  rootChain,
+		batchEvents:    helper.GetConfig().EnableEventBatching,
+		checkReceipts:  helper.GetConfig().CheckReceiptStatus,
+		taskNames:      parseEventTaskNames(helper.GetConfig().EventTaskNames),
+		routingKey:     queue.TaskRoutingKey(rootChain),
+
+		confirmationStrategy: defaultConfirmationStrategy(helper.GetConfig().UseFinalizedBlock),
+
+		verifyInclusion: helper.GetConfig().VerifyLogInclusion,
+
+		maxStateDataSize: helper.GetConfig().MaxStateSyncedDataSize,
+		etaJitter:        helper.GetConfig().TaskETAJitter,
+
+		minTaskDelay: helper.GetConfig().MinTaskDelay,
+		maxTaskDelay: helper.GetConfig().MaxTaskDelay,
+
+		minConfirmations: helper.GetConfig().RootChainMinConfirmations,
+
+		healthStaleness: helper.GetConfig().ListenerHealthStaleness,
+
+		checkpointBlocks:   helper.GetConfig().CursorCheckpointBlocks,
+		checkpointInterval: helper.GetConfig().CursorCheckpointInterval,
+
+		maxQueuedTasks:      helper.GetConfig().MaxQueuedTasks,
+		queuedTasksLowWater: helper.GetConfig().QueuedTasksLowWater,
+
+		filterLogsCap: helper.GetConfig().FilterLogsResultCap,
+		payloadFormat: helper.GetConfig().EventPayloadFormat,
+
+		snapshotInterval: helper.GetConfig().ValidatorSnapshotInterval,
 	}
 	switch rootChain {
 	case hmtypes.RootChainTypeEth:
 		rootChainListener.blockKey = lastEthBlockKey
+		rootChainListener.rpcClient = helper.GetMainChainRPCClient()
 		rootChainListener.pollInterval = helper.GetConfig().EthSyncerPollInterval
 		rootChainListener.busyLimit = helper.GetConfig().EthUnconfirmedTxsBusyLimit
 		rootChainListener.maxQueryBlocks = helper.GetConfig().EthMaxQueryBlocks
+		rootChainListener.expectedChainID = helper.GetConfig().EthChainID
 	case hmtypes.RootChainTypeBsc:
 		rootChainListener.blockKey = lastBscBlockKey
+		rootChainListener.rpcClient = helper.GetBscChainRPCClient()
 		rootChainListener.pollInterval = helper.GetConfig().BscSyncerPollInterval
 		rootChainListener.busyLimit = helper.GetConfig().BscUnconfirmedTxsBusyLimit
 		rootChainListener.maxQueryBlocks = helper.GetConfig().BscMaxQueryBlocks
+		rootChainListener.expectedChainID = helper.GetConfig().BscChainID
 	default:
 		panic("wrong chain type for root chain")
 	}
+	if strategy != nil {
+		rootChainListener.confirmationStrategy = strategy
+	}
 	return rootChainListener
 }
 
+// checkChainID records the chain id of the connected root chain and compares it to the expected one, if configured
+func (rl *RootChainListener) checkChainID(ctx context.Context) error {
+	chainID, err := rl.chainClient.ChainID(ctx)
+	if err != nil {
+		if rl.expectedChainID != 0 {
+			return err
+		}
+
+		rl.Logger.Error("Error while fetching root chain id", "root", rl.rootChainType, "error", err)
+		return nil
+	}
+
+	if rl.expectedChainID != 0 && (!chainID.IsUint64() || chainID.Uint64() != rl.expectedChainID) {
+		return fmt.Errorf("root chain id mismatch: expected %v, connected to %v", rl.expectedChainID, chainID)
+	}
+
+	rl.chainID = chainID
+	rl.Logger.Info("Connected to root chain", "root", rl.rootChainType, "chainID", chainID)
+
+	return nil
+}
+
 // Start starts new block subscription
 func (rl *RootChainListener) Start() error {
+	// apply configured log level for this listener
+	rl.applyLogLevel()
+
 	rl.Logger.Info("Starting", "root", rl.rootChainType)
 
+	// fail fast if any dispatched event is missing from the ABIs
+	if err := validateEvents(rl.abis, rootChainEvents); err != nil {
+		rl.Logger.Error("Error while validating listener ABIs", "root", rl.rootChainType, "error", err)
+		return err
+	}
+
+	// fail fast if a dispatched event topic is ambiguous across the ABIs
+	if err := detectEventCollisions(rl.abis, rootChainEvents); err != nil {
+		rl.Logger.Error("Error while validating listener ABIs", "root", rl.rootChainType, "error", err)
+		return err
+	}
+
+	// fail fast if tasks can't be encoded
+	if err := util.ValidateEventPayloadFormat(rl.payloadFormat); err != nil {
+		rl.Logger.Error("Error while validating event payload format", "root", rl.rootChainType, "error", err)
+		return err
+	}
+
+	// fail fast if connected to the wrong network
+	if err := rl.checkChainID(context.Background()); err != nil {
+		rl.Logger.Error("Error while checking root chain id", "root", rl.rootChainType, "error", err)
+		return err
+	}
+
+	// resume from a canonical block if the chain reorganised while stopped
+	if err := rl.rewindToCanonicalCursor(context.Background()); err != nil {
+		rl.Logger.Error("Error while verifying last processed block", "root", rl.rootChainType, "error", err)
+		return err
+	}
+
 	// create cancellable context
 	ctx, cancelSubscription := context.WithCancel(context.Background())
 	rl.cancelSubscription = cancelSubscription
@@ -94,10 +308,16 @@ func (rl *RootChainListener) Start() error {
 	headerCtx, cancelHeaderProcess := context.WithCancel(context.Background())
 	rl.cancelHeaderProcess = cancelHeaderProcess
 
+	// keep a local validator snapshot instead of querying heimdall per event
+	if rl.snapshotInterval > 0 {
+		rl.validatorSnapshot = util.NewValidatorSnapshotCache(rl.storageClient, 2*rl.snapshotInterval)
+		go rl.refreshValidatorSnapshot(ctx)
+	}
+
 	// set start listen block
-	startListenBlock := rl.contractConnector.GetStartListenBlock(rl.rootChainType)
-	if startListenBlock != 0 {
-		_ = rl.setStartListenBLock(startListenBlock, rl.blockKey)
+	rl.startListenBlock = rl.contractConnector.GetStartListenBlock(rl.rootChainType)
+	if rl.startListenBlock != 0 {
+		_ = rl.setStartListenBLock(rl.startListenBlock, rl.blockKey)
 	}
 
 	// start header process
@@ -109,9 +329,9 @@ func (rl *RootChainListener) Start() error {
 			number = big.NewInt(int64(rpc.FinalizedBlockNumber))
 		}
 
-		go rl.StartPolling(ctx, rl.pollInterval, false, number)
+		go rl.startPolling(ctx, number)
 	} else {
-		go rl.StartPolling(ctx, rl.pollInterval, false, nil)
+		go rl.startPolling(ctx, nil)
 	}
 
 	// subscribed to new head
@@ -120,11 +340,150 @@ func (rl *RootChainListener) Start() error {
 	return nil
 }
 
+// startPolling runs the polling loop, tracking whether it is alive
+func (rl *RootChainListener) startPolling(ctx context.Context, number *big.Int) {
+	rl.pollingAlive.Store(true)
+	defer rl.pollingAlive.Store(false)
+
+	rl.StartPolling(ctx, rl.pollInterval, false, number)
+}
+
+// calculateTaskDelay returns whether this node is a current validator and its task delay,
+// reading validators from the snapshot when enabled
+func (rl *RootChainListener) calculateTaskDelay() (bool, time.Duration) {
+	if rl.validatorSnapshot == nil {
+		return util.CalculateTaskDelay(rl.cliCtx)
+	}
+
+	return rl.validatorSnapshot.CalculateTaskDelay(rl.cliCtx)
+}
+
+// refreshValidatorSnapshot refreshes the validator snapshot every snapshot interval until ctx is done
+func (rl *RootChainListener) refreshValidatorSnapshot(ctx context.Context) {
+	ticker := time.NewTicker(rl.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := rl.validatorSnapshot.Refresh(rl.cliCtx); err != nil {
+			rl.Logger.Error("Error while refreshing validator snapshot", "root", rl.rootChainType, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsHealthy reports whether polling is alive and a header was processed within the staleness window
+func (rl *RootChainListener) IsHealthy() bool {
+	if !rl.pollingAlive.Load() {
+		return false
+	}
+
+	lastProgress := rl.lastProgress.Load()
+	if lastProgress == 0 {
+		return false
+	}
+
+	return rl.healthStaleness == 0 || time.Since(time.Unix(0, lastProgress)) <= rl.healthStaleness
+}
+
+// SyncProgress is the position of the listener cursor relative to the chain tip
+type SyncProgress struct {
+	Cursor          uint64  `json:"cursor"`           // last processed block
+	Tip             uint64  `json:"tip"`              // confirmed ceiling of the last processed header
+	BlocksRemaining uint64  `json:"blocks_remaining"` // blocks between cursor and tip
+	Percentage      float64 `json:"percentage"`       // cursor as percentage of tip
+}
+
+// GetSyncProgress returns how far the listener cursor is behind the chain tip, e.g. during a backfill.
+// Tip is zero until the first header is processed.
+func (rl *RootChainListener) GetSyncProgress() (SyncProgress, error) {
+	cursor, _, err := rl.getCursor()
+	if err != nil {
+		return SyncProgress{}, err
+	}
+
+	progress := SyncProgress{
+		Cursor: cursor,
+		Tip:    rl.chainTip.Load(),
+	}
+	if progress.Tip == 0 {
+		return progress, nil
+	}
+
+	if progress.Cursor >= progress.Tip {
+		progress.Percentage = 100
+		return progress, nil
+	}
+
+	progress.BlocksRemaining = progress.Tip - progress.Cursor
+	progress.Percentage = float64(progress.Cursor) * 100 / float64(progress.Tip)
+
+	return progress, nil
+}
+
+// SetCaughtUpHandler sets fn to be called each time the cursor catches up with the confirmed
+// ceiling, e.g. once a backfill is done. It must be set before Start.
+func (rl *RootChainListener) SetCaughtUpHandler(fn func()) {
+	rl.onCaughtUp = fn
+}
+
+// IsCaughtUp reports whether the cursor reached the confirmed ceiling of the last processed header
+func (rl *RootChainListener) IsCaughtUp() bool {
+	return rl.caughtUp.Load()
+}
+
+// updateCaughtUp signals once when the cursor reaches the confirmed ceiling,
+// and re-arms the signal when the cursor falls behind again
+func (rl *RootChainListener) updateCaughtUp() {
+	tip := rl.chainTip.Load()
+	if tip == 0 {
+		return
+	}
+
+	cursor, _, err := rl.getCursor()
+	if err != nil {
+		return
+	}
+
+	if cursor < tip {
+		if rl.caughtUp.Swap(false) {
+			rl.Logger.Info("Listener fell behind", "root", rl.rootChainType, "cursor", cursor, "tip", tip)
+		}
+		return
+	}
+
+	if rl.caughtUp.Swap(true) {
+		return
+	}
+
+	rl.Logger.Info("Listener caught up", "root", rl.rootChainType, "cursor", cursor, "tip", tip)
+	if rl.onCaughtUp != nil {
+		rl.onCaughtUp()
+	}
+}
+
+// markProgress records that a header was processed
+func (rl *RootChainListener) markProgress() {
+	rl.lastProgress.Store(time.Now().UnixNano())
+}
+
 // ProcessHeader - process headerblock from rootchain
 func (rl *RootChainListener) ProcessHeader(newBlockHeader *blockHeader) {
+	if !newBlockHeader.isValid() {
+		rl.Logger.Error("Ignoring invalid block header", "root", rl.rootChainType)
+		return
+	}
+
 	newHeader := newBlockHeader.header
 	rl.Logger.Debug("New block detected", "root", rl.rootChainType, "blockNumber", newHeader.Number)
 
+	// known block timestamps, keyed by block number
+	blockTimes := map[uint64]uint64{newHeader.Number.Uint64(): newHeader.Time}
+
 	// check if heimdall is busy
 	if rl.busyLimit != 0 {
 		// event decay
@@ -154,150 +513,1073 @@ func (rl *RootChainListener) ProcessHeader(newBlockHeader *blockHeader) {
 	if err != nil {
 		return
 	}
-	requiredConfirmations := rootchainContext.ChainmanagerParams.MainchainTxConfirmations
+	requiredConfirmations := rl.getRequiredConfirmations(rootchainContext.ChainmanagerParams.MainchainTxConfirmations)
 	latestNumber := newHeader.Number
 	fromBlock := latestNumber
 
 	if !newBlockHeader.isFinalized {
-		// confirmation
-		confirmationBlocks := big.NewInt(0).SetUint64(requiredConfirmations)
-
-		if latestNumber.Cmp(confirmationBlocks) <= 0 {
-			rl.Logger.Error("Block number less than Confirmations required",
-				"root", rl.rootChainType, "blockNumber", latestNumber.Uint64, "confirmationsRequired", confirmationBlocks.Uint64)
-
+		latestNumber, err = rl.getConfirmationStrategy().SafeCeiling(context.Background(), newHeader, rl.chainClient, requiredConfirmations)
+		if err != nil {
+			rl.Logger.Error("Error while computing confirmed block", "root", rl.rootChainType, "blockNumber", newHeader.Number, "error", err)
 			return
 		}
 
-		latestNumber = latestNumber.Sub(latestNumber, confirmationBlocks)
-
 		// default fromBlock
 		fromBlock = latestNumber
 	}
 
-	// get last block from storage
-	hasLastBlock, _ := rl.storageClient.Has([]byte(rl.blockKey), nil)
+	rl.chainTip.Store(latestNumber.Uint64())
+	defer rl.updateCaughtUp()
+
+	// rewind if the chain reorganised below the last checkpoint
+	if err := rl.rewindToCanonicalCursor(context.Background()); err != nil {
+		rl.Logger.Error("Error while verifying last processed block", "root", rl.rootChainType, "error", err)
+		return
+	}
+
+	// get last processed block
+	lastBlock, hasLastBlock, err := rl.getCursor()
+	if err != nil {
+		rl.Logger.Info("Error while fetching last block bytes from storage", "root", rl.rootChainType, "error", err)
+		return
+	}
 	if hasLastBlock {
-		lastBlockBytes, err := rl.storageClient.Get([]byte(rl.blockKey), nil)
-		if err != nil {
-			rl.Logger.Info("Error while fetching last block bytes from storage", "root", rl.rootChainType, "error", err)
+		if lastBlock >= latestNumber.Uint64() {
+			// already up to date with the confirmed ceiling
+			rl.markProgress()
 			return
 		}
-		rl.Logger.Debug("Got last block from bridge storage", "root", rl.rootChainType, "lastBlock", string(lastBlockBytes))
-		if result, err := strconv.ParseUint(string(lastBlockBytes), 10, 64); err == nil {
-			if result >= newHeader.Number.Uint64() {
-				return
-			}
-			if result+1 < fromBlock.Uint64() { // only start from solidity block
-				fromBlock = big.NewInt(0).SetUint64(result + 1)
-			}
+		if lastBlock+1 < fromBlock.Uint64() { // only start from solidity block
+			fromBlock = big.NewInt(0).SetUint64(lastBlock + 1)
 		}
 	}
 
 	// to block
 	toBlock := latestNumber
 
+	// never process blocks below the configured start listen block
+	if rl.startListenBlock != 0 {
+		if toBlock.Uint64() < rl.startListenBlock {
+			rl.Logger.Debug("Block below start listen block", "root", rl.rootChainType, "toBlock", toBlock, "startListenBlock", rl.startListenBlock)
+			rl.markProgress()
+			return
+		}
+		fromBlock = rl.clampFromBlock(fromBlock)
+	}
+
 	if toBlock.Cmp(fromBlock) == -1 {
 		fromBlock = toBlock
 	}
 	if rl.maxQueryBlocks != 0 && big.NewInt(0).Sub(toBlock, fromBlock).Cmp(big.NewInt(rl.maxQueryBlocks)) > 0 {
 		toBlock = toBlock.Add(fromBlock, big.NewInt(rl.maxQueryBlocks))
 	}
-	// query events
-	rl.queryAndBroadcastEvents(rootchainContext, fromBlock, toBlock)
+	// query events, a skipped range is retried on next header
+	_ = rl.queryAndBroadcastEvents(rootchainContext, fromBlock, toBlock, blockTimes, false)
 }
 
-func (rl *RootChainListener) queryAndBroadcastEvents(rootchainContext *RootChainListenerContext, fromBlock *big.Int, toBlock *big.Int) {
-	rl.Logger.Info("Query rootchain event logs", "root", rl.rootChainType, "fromBlock", fromBlock, "toBlock", toBlock)
+// getRequiredConfirmations returns the governed confirmations, raised to the operator minimum if configured
+func (rl *RootChainListener) getRequiredConfirmations(governed uint64) uint64 {
+	if rl.minConfirmations > governed {
+		rl.Logger.Info("Confirmation override active", "root", rl.rootChainType, "governed", governed, "override", rl.minConfirmations)
+		return rl.minConfirmations
+	}
 
-	// get chain params
-	chainParams := rootchainContext.ChainmanagerParams.ChainParams
+	return governed
+}
 
-	// draft a query
+// getConfirmationStrategy returns the confirmation strategy, confirmation depth if none is set
+func (rl *RootChainListener) getConfirmationStrategy() ConfirmationStrategy {
+	if rl.confirmationStrategy == nil {
+		return DepthConfirmationStrategy{}
+	}
 
-	queryAddresses := []ethCommon.Address{
-		chainParams.RootChainAddress.EthAddress(),
-		chainParams.StakingInfoAddress.EthAddress(),
-		chainParams.StateSenderAddress.EthAddress(),
+	return rl.confirmationStrategy
+}
+
+// queryAndBroadcastEvents dispatches events of the given range and advances the cursor.
+// With newestFirst, used for backfills, blocks are dispatched newest first and the cursor only advances
+// if the range continues from it, so blocks between the cursor and the range aren't skipped.
+func (rl *RootChainListener) queryAndBroadcastEvents(rootchainContext *RootChainListenerContext, fromBlock *big.Int, toBlock *big.Int, blockTimes map[uint64]uint64, newestFirst bool) error {
+	// cursor is held while paused, headers are still tracked so the listener stays healthy
+	if rl.IsPaused() {
+		rl.Logger.Debug("Event dispatch paused", "root", rl.rootChainType, "fromBlock", fromBlock, "toBlock", toBlock)
+		rl.markProgress()
+
+		return errDispatchPaused
 	}
 
-	query := ethereum.FilterQuery{FromBlock: fromBlock, ToBlock: toBlock, Addresses: queryAddresses}
-	// get logs from root chain by filter
-	logs, err := rl.chainClient.FilterLogs(context.Background(), query)
+	// cursor stays put until the task queue drains, the range is retried on next header
+	if rl.isQueueFull() {
+		return errTaskQueueFull
+	}
+
+	logs, err := rl.filterLogs(context.Background(), rootchainContext, fromBlock, toBlock)
 	if err != nil {
-		rl.Logger.Error("Error while filtering logs", "error", err)
-		return
-	} else if len(logs) > 0 {
-		rl.Logger.Debug("New logs found", "numberOfLogs", len(logs))
+		return err
 	}
 
-	// set last block to storage
-	if err := rl.storageClient.Put([]byte(rl.blockKey), []byte(toBlock.String()), nil); err != nil {
-		rl.Logger.Error("rl.storageClient.Put", "Error", err)
+	// cursor stays put if block timestamps can't be fetched, the range is retried on next header
+	if err := rl.fetchBlockTimes(context.Background(), logs, blockTimes); err != nil {
+		rl.Logger.Error("Error while fetching block timestamps", "root", rl.rootChainType, "fromBlock", fromBlock, "toBlock", toBlock, "error", err)
+		return err
 	}
 
-	// process filtered log
-	for _, vLog := range logs {
-		topic := vLog.Topics[0].Bytes()
-		for _, abiObject := range rl.abis {
-			selectedEvent := helper.EventByID(abiObject, topic)
-			logBytes, _ := json.Marshal(vLog)
-			if selectedEvent != nil {
-				rl.Logger.Debug("ReceivedEvent", "eventname", selectedEvent.Name, "root", rl.rootChainType)
-				switch selectedEvent.Name {
-				case "NewHeaderBlock":
-					if isCurrentValidator, delay := util.CalculateTaskDelay(rl.cliCtx); isCurrentValidator {
-						rl.sendTaskWithDelay("sendCheckpointAckToHeimdall", selectedEvent.Name, logBytes, delay)
-					}
+	// set last processed block
+	if !newestFirst {
+		rl.advanceCursor(toBlock)
+	} else if lastBlock, hasLastBlock, _ := rl.getCursor(); hasLastBlock && fromBlock.Uint64() <= lastBlock+1 && lastBlock < toBlock.Uint64() {
+		rl.advanceCursor(toBlock)
+	}
+	rl.markProgress()
 
-				case "StateSynced":
-					if isCurrentValidator, delay := util.CalculateTaskDelay(rl.cliCtx); isCurrentValidator {
-						rl.sendTaskWithDelay("sendStateSyncedToHeimdall", selectedEvent.Name, logBytes, delay)
-						rl.stateSyncedCountWithDecay++
-					}
-				case "StakeAck":
-					if isCurrentValidator, delay := util.CalculateTaskDelay(rl.cliCtx); isCurrentValidator {
-						rl.sendTaskWithDelay("sendStakingAckToHeimdall", selectedEvent.Name, logBytes, delay)
-					}
-				}
-			}
+	return rl.broadcastLogs(logs, blockTimes, newestFirst, false)
+}
+
+// Pause stops dispatching events without stopping the listener. Subscription and header tracking
+// keep running, the cursor is held and the skipped range is processed after Resume.
+func (rl *RootChainListener) Pause() {
+	if !rl.paused.Swap(true) {
+		rl.Logger.Info("Pausing event dispatch", "root", rl.rootChainType)
+	}
+}
+
+// Resume restarts event dispatch from the held cursor on the next header
+func (rl *RootChainListener) Resume() {
+	if rl.paused.Swap(false) {
+		rl.Logger.Info("Resuming event dispatch", "root", rl.rootChainType)
+	}
+}
+
+// IsPaused reports whether event dispatch is paused, see Pause
+func (rl *RootChainListener) IsPaused() bool {
+	return rl.paused.Load()
+}
+
+// isQueueFull reports whether dispatching is paused. It pauses once pending tasks reach
+// maxQueuedTasks, and resumes after they drain below the low-water mark, half the ceiling by default.
+func (rl *RootChainListener) isQueueFull() bool {
+	if rl.maxQueuedTasks == 0 {
+		return false
+	}
+
+	pending, err := rl.getPendingTasks()
+	if err != nil {
+		rl.Logger.Error("Error while fetching pending tasks", "root", rl.rootChainType, "error", err)
+		return false
+	}
+
+	lowWater := rl.queuedTasksLowWater
+	if lowWater <= 0 || lowWater > rl.maxQueuedTasks {
+		lowWater = rl.maxQueuedTasks / 2
+	}
+
+	if rl.queueFull.Load() {
+		if pending >= lowWater {
+			rl.Logger.Debug("Task queue still draining", "root", rl.rootChainType, "pending", pending, "lowWater", lowWater)
+			return true
 		}
+
+		rl.Logger.Info("Task queue drained, resuming", "root", rl.rootChainType, "pending", pending)
+		rl.queueFull.Store(false)
+		return false
+	}
+
+	if pending >= rl.maxQueuedTasks {
+		rl.Logger.Info("Task queue full, pausing", "root", rl.rootChainType, "pending", pending, "maxQueuedTasks", rl.maxQueuedTasks)
+		rl.queueFull.Store(true)
+		return true
 	}
+
+	return false
 }
 
-func (rl *RootChainListener) sendTaskWithDelay(taskName string, eventName string, logBytes []byte, delay time.Duration) {
-	signature := &tasks.Signature{
-		Name: taskName,
-		Args: []tasks.Arg{
-			{
-				Type:  "string",
-				Value: eventName,
-			},
-			{
-				Type:  "string",
-				Value: string(logBytes),
-			},
-			{
-				Type:  "string",
-				Value: rl.rootChainType,
-			},
-		},
+// getPendingTasks returns the number of tasks in flight on the task queue, delayed tasks included.
+// It's counted locally, so the broker queue isn't drained for inspection.
+func (rl *RootChainListener) getPendingTasks() (int, error) {
+	if rl.pendingTasks != nil {
+		return rl.pendingTasks()
 	}
-	signature.RetryCount = 3
-	signature.RetryTimeout = 3
-	// add delay for task so that multiple validators won't send same transaction at same time
-	eta := time.Now().Add(delay)
-	signature.ETA = &eta
-	rl.Logger.Info("Sending task", "root", rl.rootChainType, "taskName", taskName, "currentTime", time.Now(), "delayTime", eta)
-	_, err := rl.queueConnector.Server.SendTask(signature)
+
+	return rl.queueConnector.PendingTasks(rl.routingKey), nil
+}
+
+// Stop stops the listener and flushes the in-memory cursor to storage
+func (rl *RootChainListener) Stop() {
+	rl.BaseListener.Stop()
+	rl.flushCursor()
+}
+
+// getCursor returns the last processed block, preferring the in-memory cursor over storage
+func (rl *RootChainListener) getCursor() (uint64, bool, error) {
+	rl.cursorMu.Lock()
+	defer rl.cursorMu.Unlock()
+
+	if rl.cursor != nil {
+		return rl.cursor.Uint64(), true, nil
+	}
+
+	hasLastBlock, _ := rl.storageClient.Has([]byte(rl.blockKey), nil)
+	if !hasLastBlock {
+		return 0, false, nil
+	}
+
+	lastBlockBytes, err := rl.storageClient.Get([]byte(rl.blockKey), nil)
 	if err != nil {
-		rl.Logger.Error("Error sending task", "taskName", taskName, "error", err)
+		return 0, false, err
+	}
+	rl.Logger.Debug("Got last block from bridge storage", "root", rl.rootChainType, "lastBlock", string(lastBlockBytes))
+
+	result, err := strconv.ParseUint(string(lastBlockBytes), 10, 64)
+	if err != nil {
+		return 0, false, nil
 	}
+
+	return result, true, nil
 }
 
-//
-// utils
-//
+// advanceCursor records toBlock as the last processed block. It is written to storage
+// once checkpointBlocks blocks or checkpointInterval have passed since the last
+// checkpoint, or on every call when neither is configured. Blocks processed after the
+// last checkpoint are re-scanned after a crash; dispatched tasks are deduplicated by heimdall.
+func (rl *RootChainListener) advanceCursor(toBlock *big.Int) {
+	rl.cursorMu.Lock()
+	rl.cursor = big.NewInt(0).Set(toBlock)
+
+	due := rl.checkpointBlocks == 0 && rl.checkpointInterval == 0
+	if rl.checkpointBlocks != 0 && toBlock.Uint64() >= rl.checkpointedCursor+rl.checkpointBlocks {
+		due = true
+	}
+	if rl.checkpointInterval != 0 && time.Since(rl.lastCheckpoint) >= rl.checkpointInterval {
+		due = true
+	}
+	rl.cursorMu.Unlock()
+
+	if due {
+		rl.checkpointCursor(toBlock.Uint64())
+	}
+}
+
+// flushCursor writes the in-memory cursor to storage if it is ahead of the last checkpoint
+func (rl *RootChainListener) flushCursor() {
+	rl.cursorMu.Lock()
+	if rl.cursor == nil || rl.cursor.Uint64() == rl.checkpointedCursor {
+		rl.cursorMu.Unlock()
+		return
+	}
+	number := rl.cursor.Uint64()
+	rl.cursorMu.Unlock()
+
+	rl.checkpointCursor(number)
+}
+
+// checkpointCursor writes the in-memory cursor at number to storage along with its block hash.
+// The hash is fetched before taking cursorMu, so getCursor isn't blocked on the rpc. The checkpoint is
+// skipped if the cursor moved in the meantime, the newer cursor is written by its own advance or flush.
+func (rl *RootChainListener) checkpointCursor(number uint64) {
+	hash := rl.getBlockHash(context.Background(), number)
+
+	rl.cursorMu.Lock()
+	defer rl.cursorMu.Unlock()
+
+	if rl.cursor == nil || rl.cursor.Uint64() != number {
+		return
+	}
+
+	// set last block to storage
+	if err := rl.storageClient.Put([]byte(rl.blockKey), []byte(rl.cursor.String()), nil); err != nil {
+		rl.Logger.Error("rl.storageClient.Put", "Error", err)
+		return
+	}
+
+	rl.checkpointedCursor = number
+	rl.lastCheckpoint = time.Now()
+
+	if hash != nil {
+		rl.recordCursorHash(number, *hash)
+	}
+}
+
+// getBlockHash returns the hash of the block at number, or nil if it can't be fetched
+func (rl *RootChainListener) getBlockHash(ctx context.Context, number uint64) *ethCommon.Hash {
+	header, err := rl.chainClient.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		rl.Logger.Error("Error while fetching cursor block header", "root", rl.rootChainType, "blockNumber", number, "error", err)
+		return nil
+	}
+
+	hash := header.Hash()
+	return &hash
+}
+
+// cursorHash is the block hash of a cursor checkpoint
+type cursorHash struct {
+	Number uint64         `json:"number"`
+	Hash   ethCommon.Hash `json:"hash"`
+}
+
+// getCursorHashes returns the block hashes of recent cursor checkpoints, oldest first
+func (rl *RootChainListener) getCursorHashes() ([]cursorHash, error) {
+	key := []byte(rl.blockKey + cursorHashesKeySuffix)
+	if has, _ := rl.storageClient.Has(key, nil); !has {
+		return nil, nil
+	}
+
+	value, err := rl.storageClient.Get(key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []cursorHash
+	if err := json.Unmarshal(value, &hashes); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// recordCursorHash stores the block hash of the checkpointed cursor, dropping hashes at or above it
+func (rl *RootChainListener) recordCursorHash(number uint64, hash ethCommon.Hash) {
+	hashes, err := rl.getCursorHashes()
+	if err != nil {
+		rl.Logger.Error("Error while reading cursor block hashes", "root", rl.rootChainType, "error", err)
+	}
+
+	for len(hashes) > 0 && hashes[len(hashes)-1].Number >= number {
+		hashes = hashes[:len(hashes)-1]
+	}
+	hashes = append(hashes, cursorHash{Number: number, Hash: hash})
+	if len(hashes) > maxCursorHashes {
+		hashes = hashes[len(hashes)-maxCursorHashes:]
+	}
+
+	value, err := json.Marshal(hashes)
+	if err != nil {
+		return
+	}
+
+	if err := rl.storageClient.Put([]byte(rl.blockKey+cursorHashesKeySuffix), value, nil); err != nil {
+		rl.Logger.Error("rl.storageClient.Put", "Error", err)
+	}
+}
+
+// rewindToCanonicalCursor checks the stored cursor hash is still canonical. After a reorg it rewinds
+// the cursor to the newest checkpoint still canonical, or before the oldest known one if none is.
+// It runs on Start and before each processed header, so a reorg while running is caught as well.
+func (rl *RootChainListener) rewindToCanonicalCursor(ctx context.Context) error {
+	hashes, err := rl.getCursorHashes()
+	if err != nil || len(hashes) == 0 {
+		return err
+	}
+
+	rewindTo := uint64(0)
+	if hashes[0].Number > 0 {
+		rewindTo = hashes[0].Number - 1
+	}
+
+	for i := len(hashes) - 1; i >= 0; i-- {
+		header, err := rl.chainClient.HeaderByNumber(ctx, new(big.Int).SetUint64(hashes[i].Number))
+		if err != nil {
+			return err
+		}
+
+		if header.Hash() == hashes[i].Hash {
+			if i == len(hashes)-1 {
+				return nil
+			}
+
+			rewindTo = hashes[i].Number
+			break
+		}
+	}
+
+	if rewindTo < hashes[0].Number {
+		rl.Logger.Error("Reorg deeper than known cursor hashes", "root", rl.rootChainType, "oldestKnown", hashes[0].Number)
+	}
+	rl.Logger.Info("Root chain reorg detected, rewinding cursor", "root", rl.rootChainType, "from", hashes[len(hashes)-1].Number, "to", rewindTo)
+
+	rl.cursorMu.Lock()
+	rl.cursor = new(big.Int).SetUint64(rewindTo)
+	rl.cursorMu.Unlock()
+
+	rl.checkpointCursor(rewindTo)
+
+	return nil
+}
+
+// ReplayRange re-queries and dispatches rootchain events for the given block range.
+// Unlike the header process, it leaves the last processed block and acked staking nonces in storage untouched,
+// so already acked StakeAcks of the range are dispatched again.
+func (rl *RootChainListener) ReplayRange(ctx context.Context, fromBlock *big.Int, toBlock *big.Int) error {
+	if fromBlock == nil || toBlock == nil || fromBlock.Cmp(toBlock) > 0 {
+		return fmt.Errorf("invalid replay range: from %v, to %v", fromBlock, toBlock)
+	}
+
+	rl.Logger.Info("Replaying rootchain event logs", "root", rl.rootChainType, "fromBlock", fromBlock, "toBlock", toBlock)
+
+	// fetch context
+	rootchainContext, err := rl.getRootChainContext()
+	if err != nil {
+		return err
+	}
+
+	logs, err := rl.filterLogs(ctx, rootchainContext, fromBlock, toBlock)
+	if err != nil {
+		return err
+	}
+
+	return rl.broadcastLogs(logs, map[uint64]uint64{}, false, true)
+}
+
+// Backfill queries and dispatches rootchain events for a historical range, newest block first,
+// so the most recent events are dispatched before older ones after an outage.
+// It fails without dispatching while paused or while the task queue is full.
+func (rl *RootChainListener) Backfill(fromBlock *big.Int, toBlock *big.Int) error {
+	if fromBlock == nil || toBlock == nil || fromBlock.Cmp(toBlock) > 0 {
+		return fmt.Errorf("invalid backfill range: from %v, to %v", fromBlock, toBlock)
+	}
+
+	rl.Logger.Info("Backfilling rootchain event logs", "root", rl.rootChainType, "fromBlock", fromBlock, "toBlock", toBlock)
+
+	// fetch context
+	rootchainContext, err := rl.getRootChainContext()
+	if err != nil {
+		return err
+	}
+
+	return rl.queryAndBroadcastEvents(rootchainContext, fromBlock, toBlock, map[uint64]uint64{}, true)
+}
+
+// filterLogs fetches rootchain, staking info and state sender logs for the given range
+func (rl *RootChainListener) filterLogs(ctx context.Context, rootchainContext *RootChainListenerContext, fromBlock *big.Int, toBlock *big.Int) ([]types.Log, error) {
+	rl.Logger.Info("Query rootchain event logs", "root", rl.rootChainType, "fromBlock", fromBlock, "toBlock", toBlock)
+
+	// get chain params
+	chainParams := rootchainContext.ChainmanagerParams.ChainParams
+
+	// draft a query
+
+	queryAddresses := []ethCommon.Address{
+		chainParams.RootChainAddress.EthAddress(),
+		chainParams.StakingInfoAddress.EthAddress(),
+		chainParams.StateSenderAddress.EthAddress(),
+	}
+
+	query := ethereum.FilterQuery{FromBlock: fromBlock, ToBlock: toBlock, Addresses: queryAddresses}
+	// get logs from root chain by filter
+	logs, err := rl.filterLogsBisect(ctx, query)
+	if err != nil {
+		rl.Logger.Error("Error while filtering logs", "error", err)
+		return nil, err
+	} else if len(logs) > 0 {
+		rl.Logger.Debug("New logs found", "numberOfLogs", len(logs))
+	}
+
+	// range is retried if proofs can't be fetched
+	if rl.verifyInclusion && len(logs) > 0 {
+		return rl.dropUnprovenLogs(ctx, logs)
+	}
+
+	return logs, nil
+}
+
+// tooManyResultsErrors are substrings of provider errors for log queries exceeding their result cap
+var tooManyResultsErrors = []string{
+	"too many results",
+	"query returned more than",
+	"response size exceeded",
+}
+
+// isTooManyResultsError reports whether a log query failed on the provider result cap
+func isTooManyResultsError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, tooMany := range tooManyResultsErrors {
+		if strings.Contains(msg, tooMany) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterLogsBisect filters logs of the query range, halving the range and querying each half
+// when the provider rejects it for too many results or, if configured, returns filterLogsCap logs.
+// A single block range is not split further.
+func (rl *RootChainListener) filterLogsBisect(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	logs, err := rl.chainClient.FilterLogs(ctx, query)
+	if err != nil && !isTooManyResultsError(err) {
+		return nil, err
+	}
+
+	capped := err == nil && rl.filterLogsCap > 0 && len(logs) >= rl.filterLogsCap
+	if err == nil && !capped {
+		return logs, nil
+	}
+
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	if from >= to {
+		if capped {
+			rl.Logger.Info("Log query of a single block reached result cap", "root", rl.rootChainType, "block", from, "logs", len(logs))
+		}
+		return logs, err
+	}
+
+	mid := from + (to-from)/2
+	rl.Logger.Debug("Bisecting log query", "root", rl.rootChainType, "fromBlock", from, "toBlock", to, "error", err)
+
+	left, right := query, query
+	left.ToBlock = new(big.Int).SetUint64(mid)
+	right.FromBlock = new(big.Int).SetUint64(mid + 1)
+
+	leftLogs, err := rl.filterLogsBisect(ctx, left)
+	if err != nil {
+		return nil, err
+	}
+	rightLogs, err := rl.filterLogsBisect(ctx, right)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(leftLogs, rightLogs...), nil
+}
+
+// broadcastLogs dispatches a task for every known event in the filtered logs.
+// With newestFirst, blocks are dispatched in reverse while logs keep their order within a block.
+// With replay, StakeAcks aren't checked against or recorded as acked staking nonces.
+// Nothing is dispatched if the block timestamps of the logs can't be fetched.
+func (rl *RootChainListener) broadcastLogs(logs []types.Log, blockTimes map[uint64]uint64, newestFirst bool, replay bool) error {
+	if err := rl.fetchBlockTimes(context.Background(), logs, blockTimes); err != nil {
+		return err
+	}
+
+	// pending StateSynced logs of a single block, when batching is enabled
+	var batch []types.Log
+	var batchBlockTime uint64
+
+	// acked staking nonces are stored after dispatch when newest first,
+	// so older nonces of the range aren't skipped as already acked
+	var pendingAcks map[uint64]uint64
+	if newestFirst {
+		logs = reverseLogBlocks(logs)
+		pendingAcks = make(map[uint64]uint64)
+	}
+
+	if rl.checkReceipts {
+		logs = rl.dropFailedTxLogs(logs)
+	}
+
+	// checkpoints landing in the same block are acked in header number order
+	logs = rl.orderHeaderBlockLogs(logs)
+
+	// process filtered log
+	for _, vLog := range logs {
+		topic := vLog.Topics[0].Bytes()
+		blockTime := blockTimes[vLog.BlockNumber]
+		for _, abiObject := range rl.abis {
+			selectedEvent := helper.EventByID(abiObject, topic)
+			if selectedEvent != nil {
+				rl.Logger.Debug("ReceivedEvent", "eventname", selectedEvent.Name, "root", rl.rootChainType)
+
+				logBytes, err := util.EncodeLogPayload(rl.payloadFormat, vLog)
+				if err != nil {
+					rl.Logger.Error("Error while encoding log payload", "root", rl.rootChainType, "eventname", selectedEvent.Name, "txHash", vLog.TxHash, "logIndex", vLog.Index, "error", err)
+					break
+				}
+
+				// batch ends on a different event or block
+				if len(batch) > 0 && (selectedEvent.Name != "StateSynced" || vLog.BlockNumber != batch[0].BlockNumber) {
+					rl.sendStateSyncedBatch(batch, batchBlockTime)
+					batch = nil
+				}
+
+				switch selectedEvent.Name {
+				case "NewHeaderBlock":
+					if isCurrentValidator, delay := rl.calculateTaskDelay(); isCurrentValidator {
+						rl.sendTaskWithDelay(rl.getTaskName(selectedEvent.Name), selectedEvent.Name, logBytes, blockTime, delay)
+					}
+
+				case "StateSynced":
+					// oversized payloads can exceed broker message limits
+					if rl.maxStateDataSize > 0 && len(vLog.Data) > rl.maxStateDataSize {
+						rl.deadLetterStateSynced(vLog, fmt.Sprintf("data size %d exceeds limit %d", len(vLog.Data), rl.maxStateDataSize))
+						break
+					}
+
+					if rl.batchEvents {
+						batch = append(batch, vLog)
+						batchBlockTime = blockTime
+						break
+					}
+
+					if isCurrentValidator, delay := rl.calculateTaskDelay(); isCurrentValidator {
+						rl.sendTaskWithDelay(rl.getTaskName(selectedEvent.Name), selectedEvent.Name, logBytes, blockTime, delay)
+						rl.stateSyncedCountWithDecay++
+					}
+				case "StakeAck":
+					// skip acks for nonces already dispatched, e.g. on re-scan, replays dispatch them again
+					validatorID, nonce, isNew, decodeErr := rl.checkStakeAckNonce(&vLog)
+					if !isNew && !replay {
+						rl.Logger.Debug("Ignoring already acked staking nonce", "root", rl.rootChainType, "validatorID", validatorID, "nonce", nonce)
+						continue
+					}
+
+					if isCurrentValidator, delay := rl.calculateTaskDelay(); isCurrentValidator {
+						// nonce is only recorded once the task is queued, a failed send is retried on re-scan
+						if err := rl.sendTaskWithDelay(rl.getTaskName(selectedEvent.Name), selectedEvent.Name, logBytes, blockTime, delay); err != nil || decodeErr != nil || replay {
+							break
+						}
+
+						if !newestFirst {
+							rl.setStakeAckNonce(validatorID, nonce)
+						} else if nonce > pendingAcks[validatorID] {
+							pendingAcks[validatorID] = nonce
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if len(batch) > 0 {
+		rl.sendStateSyncedBatch(batch, batchBlockTime)
+	}
+
+	for validatorID, nonce := range pendingAcks {
+		rl.setStakeAckNonce(validatorID, nonce)
+	}
+
+	return nil
+}
+
+// dropFailedTxLogs returns logs whose transactions succeeded. Logs are kept when the receipt can't be fetched,
+// heimdall verifies the receipt again before accepting the event.
+func (rl *RootChainListener) dropFailedTxLogs(logs []types.Log) []types.Log {
+	succeeded := make(map[ethCommon.Hash]bool)
+	result := make([]types.Log, 0, len(logs))
+	for _, vLog := range logs {
+		ok, found := succeeded[vLog.TxHash]
+		if !found {
+			receipt, err := rl.chainClient.TransactionReceipt(context.Background(), vLog.TxHash)
+			if err != nil {
+				rl.Logger.Error("Error while fetching transaction receipt", "root", rl.rootChainType, "txHash", vLog.TxHash.Hex(), "error", err)
+				ok = true
+			} else {
+				ok = receipt.Status == types.ReceiptStatusSuccessful
+			}
+			succeeded[vLog.TxHash] = ok
+		}
+
+		if !ok {
+			rl.Logger.Info("Dropping log of failed transaction", "root", rl.rootChainType, "txHash", vLog.TxHash.Hex(), "logIndex", vLog.Index)
+			continue
+		}
+		result = append(result, vLog)
+	}
+
+	return result
+}
+
+// dropUnprovenLogs returns logs proven to be included under the receipts root of their block.
+// Receipts are fetched once per block, fetch errors are returned so the range can be retried.
+func (rl *RootChainListener) dropUnprovenLogs(ctx context.Context, logs []types.Log) ([]types.Log, error) {
+	if rl.rpcClient == nil {
+		return nil, fmt.Errorf("no rpc client for %v receipts", rl.rootChainType)
+	}
+
+	receiptTries := make(map[uint64]*trie.Trie)
+	receiptsRoots := make(map[uint64]ethCommon.Hash)
+	result := make([]types.Log, 0, len(logs))
+	for i := range logs {
+		vLog := &logs[i]
+		receiptTrie, ok := receiptTries[vLog.BlockNumber]
+		if !ok {
+			header, err := rl.chainClient.HeaderByNumber(ctx, new(big.Int).SetUint64(vLog.BlockNumber))
+			if err != nil {
+				rl.Logger.Error("Error while fetching block header", "root", rl.rootChainType, "block", vLog.BlockNumber, "error", err)
+				return nil, err
+			}
+
+			var receipts types.Receipts
+			if err := rl.rpcClient.CallContext(ctx, &receipts, "eth_getBlockReceipts", hexutil.EncodeUint64(vLog.BlockNumber)); err != nil {
+				rl.Logger.Error("Error while fetching block receipts", "root", rl.rootChainType, "block", vLog.BlockNumber, "error", err)
+				return nil, err
+			}
+
+			if receiptTrie, err = newReceiptTrie(receipts); err != nil {
+				rl.Logger.Error("Error while building receipts trie", "root", rl.rootChainType, "block", vLog.BlockNumber, "error", err)
+				return nil, err
+			}
+			receiptTries[vLog.BlockNumber] = receiptTrie
+			receiptsRoots[vLog.BlockNumber] = header.ReceiptHash
+		}
+
+		if err := verifyLogInclusion(receiptTrie, receiptsRoots[vLog.BlockNumber], vLog); err != nil {
+			rl.Logger.Info("Dropping unproven log", "root", rl.rootChainType, "txHash", vLog.TxHash.Hex(), "logIndex", vLog.Index, "error", err)
+			continue
+		}
+		result = append(result, *vLog)
+	}
+
+	return result, nil
+}
+
+// newReceiptTrie builds the receipts trie of a block, keyed by rlp encoded transaction index
+func newReceiptTrie(receipts types.Receipts) (*trie.Trie, error) {
+	receiptTrie, err := trie.New(ethCommon.Hash{}, trie.NewDatabase(memorydb.New()))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, receipt := range receipts {
+		key, err := rlp.EncodeToBytes(uint(i))
+		if err != nil {
+			return nil, err
+		}
+		value, err := receipt.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := receiptTrie.TryUpdate(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return receiptTrie, nil
+}
+
+// verifyLogInclusion proves the receipt of the log's transaction under receiptsRoot
+// and checks the proven receipt carries the log
+func verifyLogInclusion(receiptTrie *trie.Trie, receiptsRoot ethCommon.Hash, vLog *types.Log) error {
+	key, err := rlp.EncodeToBytes(vLog.TxIndex)
+	if err != nil {
+		return err
+	}
+
+	proof := memorydb.New()
+	if err := receiptTrie.Prove(key, 0, proof); err != nil {
+		return err
+	}
+
+	value, err := trie.VerifyProof(receiptsRoot, key, proof)
+	if err != nil {
+		return err
+	} else if value == nil {
+		return fmt.Errorf("no receipt at index %v", vLog.TxIndex)
+	}
+
+	var receipt types.Receipt
+	if err := receipt.UnmarshalBinary(value); err != nil {
+		return err
+	}
+
+	for _, receiptLog := range receipt.Logs {
+		if isSameLog(receiptLog, vLog) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("log not found in receipt of transaction %v", vLog.TxHash.Hex())
+}
+
+// isSameLog compares the consensus fields of two logs
+func isSameLog(a *types.Log, b *types.Log) bool {
+	if a.Address != b.Address || len(a.Topics) != len(b.Topics) || !bytes.Equal(a.Data, b.Data) {
+		return false
+	}
+
+	for i := range a.Topics {
+		if a.Topics[i] != b.Topics[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// stateSyncedDeadLetter is a state synced log that was not dispatched, kept in storage for operators
+type stateSyncedDeadLetter struct {
+	Reason string    `json:"reason"`
+	Log    types.Log `json:"log"`
+}
+
+func stateSyncedDeadLetterKey(vLog *types.Log) []byte {
+	return []byte(fmt.Sprintf("%s-%s-%d", stateSyncedDeadLetterKeyPrefix, vLog.TxHash.Hex(), vLog.Index))
+}
+
+// deadLetterStateSynced stores a state synced log instead of dispatching it as a task
+func (rl *RootChainListener) deadLetterStateSynced(vLog types.Log, reason string) {
+	rl.Logger.Error("Dead-lettering state synced log", "root", rl.rootChainType, "txHash", vLog.TxHash.Hex(), "logIndex", vLog.Index, "reason", reason)
+
+	entry, err := json.Marshal(stateSyncedDeadLetter{Reason: reason, Log: vLog})
+	if err != nil {
+		rl.Logger.Error("Error while marshalling dead-lettered log", "root", rl.rootChainType, "error", err)
+		return
+	}
+
+	if err := rl.storageClient.Put(stateSyncedDeadLetterKey(&vLog), entry, nil); err != nil {
+		rl.Logger.Error("rl.storageClient.Put", "Error", err)
+	}
+}
+
+// reverseLogBlocks returns logs ordered newest block first, keeping the log order within each block
+func reverseLogBlocks(logs []types.Log) []types.Log {
+	reversed := make([]types.Log, 0, len(logs))
+	for end := len(logs); end > 0; {
+		start := end - 1
+		for start > 0 && logs[start-1].BlockNumber == logs[end-1].BlockNumber {
+			start--
+		}
+		reversed = append(reversed, logs[start:end]...)
+		end = start
+	}
+
+	return reversed
+}
+
+// orderHeaderBlockLogs sorts NewHeaderBlock logs of each block by header block number.
+// Sorted logs take the positions of NewHeaderBlock logs in the block, other logs keep theirs.
+func (rl *RootChainListener) orderHeaderBlockLogs(logs []types.Log) []types.Log {
+	for start := 0; start < len(logs); {
+		end := start + 1
+		for end < len(logs) && logs[end].BlockNumber == logs[start].BlockNumber {
+			end++
+		}
+
+		var positions []int
+		var headerLogs []types.Log
+		for i := start; i < end; i++ {
+			if rl.isHeaderBlockLog(&logs[i]) {
+				positions = append(positions, i)
+				headerLogs = append(headerLogs, logs[i])
+			}
+		}
+
+		if len(headerLogs) > 1 {
+			// headerBlockId is the second indexed topic
+			sort.SliceStable(headerLogs, func(i, j int) bool {
+				return headerLogs[i].Topics[2].Big().Cmp(headerLogs[j].Topics[2].Big()) < 0
+			})
+			for i, position := range positions {
+				logs[position] = headerLogs[i]
+			}
+		}
+
+		start = end
+	}
+
+	return logs
+}
+
+// isHeaderBlockLog reports whether vLog is a NewHeaderBlock event carrying the header block number
+func (rl *RootChainListener) isHeaderBlockLog(vLog *types.Log) bool {
+	if len(vLog.Topics) < 3 {
+		return false
+	}
+
+	for _, abiObject := range rl.abis {
+		if selectedEvent := helper.EventByID(abiObject, vLog.Topics[0].Bytes()); selectedEvent != nil {
+			return selectedEvent.Name == "NewHeaderBlock"
+		}
+	}
+
+	return false
+}
+
+// sendStateSyncedBatch dispatches StateSynced logs of a block as a single task
+func (rl *RootChainListener) sendStateSyncedBatch(batch []types.Log, blockTime uint64) {
+	logBytes, err := util.EncodeLogBatchPayload(rl.payloadFormat, batch)
+	if err != nil {
+		rl.Logger.Error("Error while marshalling state synced batch", "root", rl.rootChainType, "error", err)
+		return
+	}
+
+	if isCurrentValidator, delay := rl.calculateTaskDelay(); isCurrentValidator {
+		rl.sendTaskWithDelay(rl.getTaskName("StateSynced"), "StateSynced", logBytes, blockTime, delay)
+		rl.stateSyncedCountWithDecay += uint64(len(batch))
+	}
+}
+
+// getTaskName returns the task handler name for an event, preferring configured overrides
+func (rl *RootChainListener) getTaskName(eventName string) string {
+	if taskName, ok := rl.taskNames[eventName]; ok {
+		return taskName
+	}
+
+	return defaultRootChainTaskNames[eventName]
+}
+
+// parseEventTaskNames parses task name overrides in the form "event:task,..."
+func parseEventTaskNames(routes string) map[string]string {
+	taskNames := make(map[string]string)
+	for _, item := range strings.Split(routes, ",") {
+		pair := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		if len(pair) == 2 && pair[0] != "" && strings.TrimSpace(pair[1]) != "" {
+			taskNames[pair[0]] = strings.TrimSpace(pair[1])
+		}
+	}
+
+	return taskNames
+}
+
+func (rl *RootChainListener) sendTaskWithDelay(taskName string, eventName string, logBytes []byte, blockTime uint64, delay time.Duration) error {
+	signature := rl.newTaskSignature(taskName, eventName, logBytes, blockTime)
+	if clamped, ok := util.ClampTaskDelay(delay, rl.minTaskDelay, rl.maxTaskDelay); ok {
+		rl.Logger.Info("Task delay out of bounds, clamped", "root", rl.rootChainType, "taskName", taskName, "delay", delay, "clamped", clamped)
+		delay = clamped
+	}
+	// add delay for task so that multiple validators won't send same transaction at same time
+	eta := time.Now().Add(delay + util.TaskETAJitter(helper.GetAddress(), rl.etaJitter))
+	signature.ETA = &eta
+	rl.Logger.Info("Sending task", "root", rl.rootChainType, "taskName", taskName, "currentTime", time.Now(), "delayTime", eta)
+	_, err := rl.queueConnector.SendTask(signature)
+	if err != nil {
+		rl.Logger.Error("Error sending task", "taskName", taskName, "error", err)
+	}
+
+	return err
+}
+
+// newTaskSignature builds the task envelope for an event, carrying the source block timestamp as header
+func (rl *RootChainListener) newTaskSignature(taskName string, eventName string, logBytes []byte, blockTime uint64) *tasks.Signature {
+	signature := &tasks.Signature{
+		Name: taskName,
+		Args: []tasks.Arg{
+			{
+				Type:  "string",
+				Value: eventName,
+			},
+			{
+				Type:  "string",
+				Value: string(logBytes),
+			},
+			{
+				Type:  "string",
+				Value: rl.rootChainType,
+			},
+		},
+		Headers: tasks.Headers{
+			blockTimeHeader: strconv.FormatUint(blockTime, 10),
+		},
+	}
+	signature.RetryCount = 3
+	signature.RetryTimeout = 3
+	signature.RoutingKey = rl.routingKey
+
+	// chain id the task was observed on, for handlers to cross check
+	if rl.chainID != nil {
+		signature.Headers[rootChainIDHeader] = rl.chainID.String()
+	}
+	return signature
+}
+
+//
+// utils
+//
+
+// fetchBlockTimes adds the timestamps of the blocks of logs missing from blockTimes, keyed by block number.
+// Missing blocks are fetched in a single batch, or one by one without an rpc client.
+func (rl *RootChainListener) fetchBlockTimes(ctx context.Context, logs []types.Log, blockTimes map[uint64]uint64) error {
+	var missing []uint64
+	seen := make(map[uint64]bool)
+	for _, vLog := range logs {
+		if _, ok := blockTimes[vLog.BlockNumber]; !ok && !seen[vLog.BlockNumber] {
+			seen[vLog.BlockNumber] = true
+			missing = append(missing, vLog.BlockNumber)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	headers := make([]*types.Header, len(missing))
+	if rl.rpcClient == nil {
+		for i, blockNumber := range missing {
+			header, err := rl.chainClient.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+			if err != nil {
+				return fmt.Errorf("block %d header: %w", blockNumber, err)
+			}
+			headers[i] = header
+		}
+	} else {
+		batch := make([]rpc.BatchElem, len(missing))
+		for i, blockNumber := range missing {
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getBlockByNumber",
+				Args:   []interface{}{hexutil.EncodeUint64(blockNumber), false},
+				Result: &headers[i],
+			}
+		}
+
+		if err := rl.rpcClient.BatchCallContext(ctx, batch); err != nil {
+			return err
+		}
+
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return fmt.Errorf("block %d header: %w", missing[i], elem.Error)
+			}
+		}
+	}
+
+	// nothing is added unless every block was fetched
+	for i, blockNumber := range missing {
+		if headers[i] == nil {
+			return fmt.Errorf("block %d not found", blockNumber)
+		}
+	}
+	for i, blockNumber := range missing {
+		blockTimes[blockNumber] = headers[i].Time
+	}
+
+	return nil
+}
+
+// clampFromBlock raises fromBlock to the start listen block if it falls below it
+func (rl *RootChainListener) clampFromBlock(fromBlock *big.Int) *big.Int {
+	if fromBlock.Uint64() < rl.startListenBlock {
+		rl.Logger.Info("Clamping from block to start listen block", "root", rl.rootChainType, "fromBlock", fromBlock, "startListenBlock", rl.startListenBlock)
+		return new(big.Int).SetUint64(rl.startListenBlock)
+	}
+	return fromBlock
+}
+
+// stakeAckNonceKey returns the storage key for last acked staking nonce of a validator
+func (rl *RootChainListener) stakeAckNonceKey(validatorID uint64) []byte {
+	return []byte(fmt.Sprintf("%s-%s-%d", rl.rootChainType, stakeAckNonceKeyPrefix, validatorID))
+}
+
+// checkStakeAckNonce decodes the StakeAck log and checks its nonce is newer than the last acked one.
+// A log that can't be decoded is reported as new along with the error, the processor decides on it.
+func (rl *RootChainListener) checkStakeAckNonce(vLog *types.Log) (uint64, uint64, bool, error) {
+	event := new(stakinginfo.StakinginfoStakeAck)
+	if err := helper.UnpackLog(rl.stakingInfoAbi, event, "StakeAck", vLog); err != nil {
+		rl.Logger.Error("Error while parsing event", "name", "StakeAck", "error", err)
+		return 0, 0, true, err
+	}
+
+	validatorID, nonce := event.ValidatorId.Uint64(), event.Nonce.Uint64()
+
+	lastNonceBytes, err := rl.storageClient.Get(rl.stakeAckNonceKey(validatorID), nil)
+	if err != nil {
+		// nothing acked yet for this validator
+		return validatorID, nonce, true, nil
+	}
+
+	lastNonce, err := strconv.ParseUint(string(lastNonceBytes), 10, 64)
+	if err != nil {
+		rl.Logger.Error("Error while parsing last acked staking nonce", "root", rl.rootChainType, "validatorID", validatorID, "error", err)
+		return validatorID, nonce, true, nil
+	}
+
+	return validatorID, nonce, nonce > lastNonce, nil
+}
+
+// setStakeAckNonce stores the last acked staking nonce of a validator
+func (rl *RootChainListener) setStakeAckNonce(validatorID uint64, nonce uint64) {
+	if err := rl.storageClient.Put(rl.stakeAckNonceKey(validatorID), []byte(strconv.FormatUint(nonce, 10)), nil); err != nil {
+		rl.Logger.Error("rl.storageClient.Put", "Error", err)
+	}
+}
 
 func (rl *RootChainListener) getRootChainContext() (*RootChainListenerContext, error) {
 	chainmanagerParams, err := util.GetNewChainParams(rl.cliCtx, rl.rootChainType)
@@ -0,0 +1,1290 @@
+package listener
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RichardKnop/machinery/v1"
+	"github.com/RichardKnop/machinery/v1/brokers/eager"
+	machineryConfig "github.com/RichardKnop/machinery/v1/config"
+	"github.com/RichardKnop/machinery/v1/tasks"
+	cliContext "github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/maticnetwork/heimdall/bridge/setu/queue"
+	"github.com/maticnetwork/heimdall/bridge/setu/util"
+	chainmanagerTypes "github.com/maticnetwork/heimdall/chainmanager/types"
+	"github.com/maticnetwork/heimdall/contracts/rootchain"
+	"github.com/maticnetwork/heimdall/contracts/stakinginfo"
+	"github.com/maticnetwork/heimdall/contracts/statesender"
+	"github.com/maticnetwork/heimdall/helper"
+	hmTypes "github.com/maticnetwork/heimdall/types"
+)
+
+func TestNewTaskSignatureIncludesBlockTime(t *testing.T) {
+	t.Parallel()
+
+	rl := &RootChainListener{rootChainType: hmTypes.RootChainTypeEth}
+
+	// handler args are unchanged, the timestamp travels as header
+	signature := rl.newTaskSignature("sendStateSyncedToHeimdall", "StateSynced", []byte(`{}`), 1650000000)
+	require.Len(t, signature.Args, 3)
+	require.Equal(t, "StateSynced", signature.Args[0].Value)
+	require.Equal(t, `{}`, signature.Args[1].Value)
+	require.Equal(t, hmTypes.RootChainTypeEth, signature.Args[2].Value)
+	require.Equal(t, "1650000000", signature.Headers[blockTimeHeader])
+}
+
+func TestTaskRoutingKey(t *testing.T) {
+	rl := newTestRootChainListener(t, &testEthService{})
+
+	config := helper.GetConfig()
+	config.TaskRoutingKeys = "eth:eth_tasks,tron:tron_tasks"
+	helper.SetTestConfig(config)
+	t.Cleanup(func() {
+		config.TaskRoutingKeys = ""
+		helper.SetTestConfig(config)
+	})
+
+	// shared queue when the root chain isn't routed
+	require.Empty(t, queue.TaskRoutingKey(hmTypes.RootChainTypeBsc))
+	require.Empty(t, rl.newTaskSignature("sendStateSyncedToHeimdall", "StateSynced", nil, 0).RoutingKey)
+
+	rl.routingKey = queue.TaskRoutingKey(rl.rootChainType)
+	require.Equal(t, "eth_tasks", rl.newTaskSignature("sendStateSyncedToHeimdall", "StateSynced", nil, 0).RoutingKey)
+
+	// dispatched task carries the routing key
+	var dispatched []string
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		dispatched = append(dispatched, eventName)
+		return nil
+	}))
+	signature := rl.newTaskSignature("sendStateSyncedToHeimdall", "StateSynced", []byte("{}"), 0)
+	result, err := rl.queueConnector.Server.SendTask(signature)
+	require.NoError(t, err)
+	require.Equal(t, "eth_tasks", result.Signature.RoutingKey)
+	require.Equal(t, []string{"StateSynced"}, dispatched)
+}
+
+func TestFetchBlockTimes(t *testing.T) {
+	ethService := &testEthService{missingHeaders: map[uint64]bool{}}
+	rl := newTestRootChainListener(t, ethService)
+
+	// known blocks are kept, unknown ones fetched in one batch
+	logs := []types.Log{{BlockNumber: 100}, {BlockNumber: 101}, {BlockNumber: 101}, {BlockNumber: 102}}
+	blockTimes := map[uint64]uint64{100: 42}
+	require.NoError(t, rl.fetchBlockTimes(context.Background(), logs, blockTimes))
+	require.Equal(t, map[uint64]uint64{100: 42, 101: 1650000101, 102: 1650000102}, blockTimes)
+
+	// a missing block fails the whole fetch, nothing is added
+	ethService.missingHeaders[104] = true
+	blockTimes = map[uint64]uint64{}
+	require.Error(t, rl.fetchBlockTimes(context.Background(), []types.Log{{BlockNumber: 103}, {BlockNumber: 104}}, blockTimes))
+	require.Empty(t, blockTimes)
+
+	// same without an rpc client
+	rl.rpcClient = nil
+	require.Error(t, rl.fetchBlockTimes(context.Background(), []types.Log{{BlockNumber: 103}, {BlockNumber: 104}}, blockTimes))
+	require.Empty(t, blockTimes)
+	require.NoError(t, rl.fetchBlockTimes(context.Background(), []types.Log{{BlockNumber: 103}}, blockTimes))
+	require.Equal(t, map[uint64]uint64{103: 1650000103}, blockTimes)
+}
+
+func TestValidateEvents(t *testing.T) {
+	t.Parallel()
+
+	rootchainABI, err := abi.JSON(strings.NewReader(rootchain.RootchainABI))
+	require.NoError(t, err)
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stakingInfoABI, err := abi.JSON(strings.NewReader(stakinginfo.StakinginfoABI))
+	require.NoError(t, err)
+
+	// all events registered
+	err = validateEvents([]*abi.ABI{&rootchainABI, &stateSenderABI, &stakingInfoABI}, rootChainEvents)
+	require.NoError(t, err)
+
+	// staking info ABI missing, StakeAck can't be resolved
+	err = validateEvents([]*abi.ABI{&rootchainABI, &stateSenderABI}, rootChainEvents)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "StakeAck")
+	require.NotContains(t, err.Error(), "NewHeaderBlock")
+	require.NotContains(t, err.Error(), "StateSynced")
+}
+
+func TestDetectEventCollisions(t *testing.T) {
+	t.Parallel()
+
+	rootchainABI, err := abi.JSON(strings.NewReader(rootchain.RootchainABI))
+	require.NoError(t, err)
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stakingInfoABI, err := abi.JSON(strings.NewReader(stakinginfo.StakinginfoABI))
+	require.NoError(t, err)
+
+	// registered ABIs don't collide
+	require.NoError(t, detectEventCollisions([]*abi.ABI{&rootchainABI, &stateSenderABI, &stakingInfoABI}, rootChainEvents))
+
+	// another ABI defining the StateSynced topic
+	collidingABI, err := abi.JSON(strings.NewReader(`[{"anonymous":false,"inputs":[{"indexed":true,"name":"id","type":"uint256"},{"indexed":true,"name":"contractAddress","type":"address"},{"indexed":false,"name":"data","type":"bytes"}],"name":"StateSynced","type":"event"}]`))
+	require.NoError(t, err)
+	require.Equal(t, stateSenderABI.Events["StateSynced"].ID, collidingABI.Events["StateSynced"].ID)
+
+	err = detectEventCollisions([]*abi.ABI{&rootchainABI, &stateSenderABI, &stakingInfoABI, &collidingABI}, rootChainEvents)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), stateSenderABI.Events["StateSynced"].ID.Hex())
+	require.Contains(t, err.Error(), "StateSynced, StateSynced")
+	require.NotContains(t, err.Error(), "StakeAck")
+}
+
+// testEthService serves the subset of the eth json-rpc api used by the rootchain listener
+type testEthService struct {
+	logs      []types.Log
+	queries   [][2]uint64
+	finalized *big.Int // finalized block, tag unsupported if nil
+	failedTxs map[ethCommon.Hash]bool
+	receipts  int
+
+	chainID       *big.Int                  // served by eth_chainId, unsupported if nil
+	blockReceipts map[uint64]types.Receipts // served by eth_getBlockReceipts
+	receiptsRoots map[uint64]ethCommon.Hash // receipts root of block headers
+	reorged       map[uint64]bool           // blocks replaced by a reorg, served with a different hash
+	maxLogRange   uint64                    // wider log queries fail with a result cap error, 0 is unlimited
+
+	missingHeaders map[uint64]bool // blocks served as not found
+}
+
+type testFilterArgs struct {
+	FromBlock *hexutil.Big `json:"fromBlock"`
+	ToBlock   *hexutil.Big `json:"toBlock"`
+}
+
+func (s *testEthService) GetLogs(ctx context.Context, args testFilterArgs) ([]types.Log, error) {
+	s.queries = append(s.queries, [2]uint64{args.FromBlock.ToInt().Uint64(), args.ToBlock.ToInt().Uint64()})
+	if s.maxLogRange != 0 && args.ToBlock.ToInt().Uint64()-args.FromBlock.ToInt().Uint64()+1 > s.maxLogRange {
+		return nil, errors.New("query returned more than 10000 results")
+	}
+
+	logs := make([]types.Log, 0, len(s.logs))
+	for _, vLog := range s.logs {
+		if vLog.BlockNumber >= args.FromBlock.ToInt().Uint64() && vLog.BlockNumber <= args.ToBlock.ToInt().Uint64() {
+			logs = append(logs, vLog)
+		}
+	}
+	return logs, nil
+}
+
+func (s *testEthService) GetBlockByNumber(ctx context.Context, number string, fullTx bool) (*types.Header, error) {
+	var blockNumber uint64
+	if number == "finalized" {
+		if s.finalized == nil {
+			return nil, errors.New("finalized block tag not supported")
+		}
+		blockNumber = s.finalized.Uint64()
+	} else {
+		n, err := hexutil.DecodeUint64(number)
+		if err != nil {
+			return nil, err
+		}
+		blockNumber = n
+	}
+
+	if s.missingHeaders[blockNumber] {
+		return nil, nil
+	}
+
+	header := &types.Header{
+		Number:      new(big.Int).SetUint64(blockNumber),
+		Time:        1650000000 + blockNumber,
+		Difficulty:  big.NewInt(0),
+		ReceiptHash: s.receiptsRoots[blockNumber],
+	}
+	if s.reorged[blockNumber] {
+		header.Extra = []byte("reorg")
+	}
+	return header, nil
+}
+
+func (s *testEthService) ChainId(ctx context.Context) (*hexutil.Big, error) {
+	if s.chainID == nil {
+		return nil, errors.New("chain id not supported")
+	}
+	return (*hexutil.Big)(s.chainID), nil
+}
+
+func (s *testEthService) GetBlockReceipts(ctx context.Context, number string) (types.Receipts, error) {
+	blockNumber, err := hexutil.DecodeUint64(number)
+	if err != nil {
+		return nil, err
+	}
+	return s.blockReceipts[blockNumber], nil
+}
+
+func (s *testEthService) GetTransactionReceipt(ctx context.Context, txHash ethCommon.Hash) (*types.Receipt, error) {
+	s.receipts++
+
+	status := types.ReceiptStatusSuccessful
+	if s.failedTxs[txHash] {
+		status = types.ReceiptStatusFailed
+	}
+	return &types.Receipt{Status: status, TxHash: txHash, Logs: []*types.Log{}}, nil
+}
+
+// newTestRootChainListener wires a rootchain listener to an in-proc eth rpc, a heimdall rest stub
+// reporting this node as the only proposer, an eager task queue and in-memory storage
+func newTestRootChainListener(t *testing.T, ethService *testEthService) *RootChainListener {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stakingInfoABI, err := abi.JSON(strings.NewReader(stakinginfo.StakinginfoABI))
+	require.NoError(t, err)
+
+	currentValidator := hmTypes.Validator{ID: 1, Signer: hmTypes.BytesToHeimdallAddress(helper.GetAddress())}
+	heimdallServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result interface{}
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/chainmanager/newparams/"):
+			result = chainmanagerTypes.Params{MainchainTxConfirmations: 6}
+		case strings.HasPrefix(r.URL.Path, "/staking/proposer/"):
+			result = []hmTypes.Validator{currentValidator}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resultBytes, _ := json.Marshal(result)
+		_, _ = w.Write([]byte(`{"height":"0","result":` + string(resultBytes) + `}`))
+	}))
+	t.Cleanup(heimdallServer.Close)
+
+	config := helper.GetDefaultHeimdallConfig()
+	config.DeliveryServerURL = heimdallServer.URL
+	helper.SetTestConfig(config)
+
+	rpcServer := rpc.NewServer()
+	require.NoError(t, rpcServer.RegisterName("eth", ethService))
+	t.Cleanup(rpcServer.Stop)
+
+	server, err := machinery.NewServer(&machineryConfig.Config{Broker: "eager", ResultBackend: "eager", DefaultQueue: "test"})
+	require.NoError(t, err)
+
+	storageClient, err := leveldb.Open(storage.NewMemStorage(), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storageClient.Close() })
+
+	rpcClient := rpc.DialInProc(rpcServer)
+
+	return &RootChainListener{
+		rpcClient: rpcClient,
+		BaseListener: BaseListener{
+			Logger:         util.Logger(),
+			chainClient:    ethclient.NewClient(rpcClient),
+			cliCtx:         cliContext.NewCLIContext().WithCodec(codec.New()),
+			queueConnector: &queue.QueueConnector{Server: server},
+			storageClient:  storageClient,
+		},
+		abis:           []*abi.ABI{&stateSenderABI, &stakingInfoABI},
+		stakingInfoAbi: &stakingInfoABI,
+		rootChainType:  hmTypes.RootChainTypeEth,
+		blockKey:       lastEthBlockKey,
+	}
+}
+
+func TestReplayRange(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+
+	// eth rpc with a state synced log at block 10 and another at block 30
+	ethService := &testEthService{
+		logs: []types.Log{
+			{BlockNumber: 10, Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 30, Topics: []ethCommon.Hash{stateSyncedID}},
+		},
+	}
+	rl := newTestRootChainListener(t, ethService)
+
+	var blockTimes []string
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(ctx context.Context, eventName string, logBytes string, rootChain string) error {
+		blockTimes = append(blockTimes, tasks.SignatureFromContext(ctx).Headers[blockTimeHeader].(string))
+		return nil
+	}))
+
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("50"), nil))
+
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(5), big.NewInt(20)))
+
+	// only the requested range is queried and dispatched
+	require.Equal(t, [][2]uint64{{5, 20}}, ethService.queries)
+	require.Equal(t, []string{"1650000010"}, blockTimes)
+
+	// cursor is left untouched
+	lastBlock, err := rl.storageClient.Get([]byte(lastEthBlockKey), nil)
+	require.NoError(t, err)
+	require.Equal(t, "50", string(lastBlock))
+
+	// inverted range is rejected
+	require.Error(t, rl.ReplayRange(context.Background(), big.NewInt(20), big.NewInt(5)))
+
+	// nothing is dispatched if a block timestamp can't be fetched
+	ethService.missingHeaders = map[uint64]bool{30: true}
+	require.Error(t, rl.ReplayRange(context.Background(), big.NewInt(5), big.NewInt(40)))
+	require.Equal(t, []string{"1650000010"}, blockTimes)
+}
+
+func TestStakeAckNonceTracking(t *testing.T) {
+	stakingInfoABI, err := abi.JSON(strings.NewReader(stakinginfo.StakinginfoABI))
+	require.NoError(t, err)
+	stakeAckID := stakingInfoABI.Events["StakeAck"].ID
+	stakeAckLog := func(blockNumber uint64, validatorID int64, nonce int64) types.Log {
+		return types.Log{
+			BlockNumber: blockNumber,
+			Topics: []ethCommon.Hash{
+				stakeAckID,
+				ethCommon.BigToHash(big.NewInt(validatorID)),
+				ethCommon.BigToHash(big.NewInt(nonce)),
+			},
+		}
+	}
+
+	// same nonce acked twice for validator 1, then a newer one
+	ethService := &testEthService{
+		logs: []types.Log{
+			stakeAckLog(10, 1, 5),
+			stakeAckLog(11, 1, 5),
+			stakeAckLog(12, 1, 6),
+		},
+	}
+	rl := newTestRootChainListener(t, ethService)
+
+	var dispatched []string
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStakingAckToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		var vLog types.Log
+		require.NoError(t, json.Unmarshal([]byte(logBytes), &vLog))
+		if len(vLog.Topics) < 3 {
+			dispatched = append(dispatched, "undecodable")
+			return nil
+		}
+		dispatched = append(dispatched, vLog.Topics[2].Big().String())
+		return nil
+	}))
+
+	rl.broadcastLogs(ethService.logs[:2], map[uint64]uint64{}, false, false)
+	require.Equal(t, []string{"5"}, dispatched)
+
+	// re-scan skips the acked nonce and dispatches only the newer one
+	rl.broadcastLogs(ethService.logs, map[uint64]uint64{}, false, false)
+	require.Equal(t, []string{"5", "6"}, dispatched)
+
+	// replay dispatches acked nonces again
+	dispatched = nil
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(12)))
+	require.Equal(t, []string{"5", "5", "6"}, dispatched)
+
+	// nonce isn't recorded when the task can't be queued, unassigned eager broker fails to publish
+	broker := rl.queueConnector.Server.GetBroker()
+	rl.queueConnector.Server.SetBroker(eager.New())
+	failedAck := stakeAckLog(13, 1, 7)
+	rl.broadcastLogs([]types.Log{failedAck}, map[uint64]uint64{}, false, false)
+	rl.queueConnector.Server.SetBroker(broker)
+
+	_, _, isNew, err := rl.checkStakeAckNonce(&failedAck)
+	require.NoError(t, err)
+	require.True(t, isNew)
+
+	// undecodable log is dispatched and no nonce is recorded for it
+	dispatched = nil
+	rl.broadcastLogs([]types.Log{{BlockNumber: 14, Topics: []ethCommon.Hash{stakeAckID}}}, map[uint64]uint64{}, false, false)
+	require.Equal(t, []string{"undecodable"}, dispatched)
+
+	_, err = rl.storageClient.Get(rl.stakeAckNonceKey(0), nil)
+	require.Error(t, err)
+}
+
+func TestApplyLogLevel(t *testing.T) {
+	processHeader := func(listenerLogLevel string) string {
+		rl := newTestRootChainListener(t, &testEthService{})
+		rl.name = RootChainListenerStr
+
+		var buf bytes.Buffer
+		rl.Logger = log.NewTMLogger(&buf)
+
+		config := helper.GetConfig()
+		config.ListenerLogLevel = listenerLogLevel
+		helper.SetTestConfig(config)
+
+		rl.applyLogLevel()
+		rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100), Time: 1650000000}})
+
+		return buf.String()
+	}
+
+	// debug lines are logged by default
+	require.Contains(t, processHeader(""), "New block detected")
+
+	// levels for other listeners don't apply
+	require.Contains(t, processHeader("tron:warn"), "New block detected")
+
+	// debug and info lines are suppressed at warn level
+	output := processHeader("tron:info, rootchain:warn")
+	require.NotContains(t, output, "New block detected")
+	require.NotContains(t, output, "Query rootchain event logs")
+}
+
+func TestBroadcastLogsBatchesStateSynced(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+
+	// burst of state synced logs in block 10, and one more in block 11
+	ethService := &testEthService{
+		logs: []types.Log{
+			{BlockNumber: 10, Index: 0, Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 10, Index: 1, Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 10, Index: 2, Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 11, Index: 0, Topics: []ethCommon.Hash{stateSyncedID}},
+		},
+	}
+	rl := newTestRootChainListener(t, ethService)
+	rl.batchEvents = true
+
+	var batches [][]types.Log
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		var vLogs []types.Log
+		require.NoError(t, json.Unmarshal([]byte(logBytes), &vLogs))
+		batches = append(batches, vLogs)
+		return nil
+	}))
+
+	// single block burst is sent as one task
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(10)))
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 3)
+	require.Equal(t, uint64(3), rl.stateSyncedCountWithDecay)
+
+	// batches don't span blocks
+	batches = nil
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(11)))
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], 3)
+	require.Len(t, batches[1], 1)
+	require.Equal(t, uint64(11), batches[1][0].BlockNumber)
+}
+
+func TestProcessHeaderClampsFromBlockToStartListenBlock(t *testing.T) {
+	ethService := &testEthService{}
+	rl := newTestRootChainListener(t, ethService)
+	rl.startListenBlock = 50
+
+	// cursor reset far below the start listen block
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("5"), nil))
+
+	// computed fromBlock 6 is clamped up to 50
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}, isFinalized: true})
+	require.Equal(t, [][2]uint64{{50, 100}}, ethService.queries)
+
+	// headers entirely below the start listen block are skipped
+	ethService.queries = nil
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("5"), nil))
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(40)}, isFinalized: true})
+	require.Empty(t, ethService.queries)
+}
+
+func TestProcessHeaderIgnoresInvalidHeaders(t *testing.T) {
+	ethService := &testEthService{}
+	rl := newTestRootChainListener(t, ethService)
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("5"), nil))
+
+	for _, newBlockHeader := range []*blockHeader{
+		nil,
+		{header: nil},
+		{header: &types.Header{}},
+		{header: &types.Header{Number: big.NewInt(0)}, isFinalized: true},
+	} {
+		require.NotPanics(t, func() { rl.ProcessHeader(newBlockHeader) })
+	}
+
+	// nothing queried, cursor unchanged
+	require.Empty(t, ethService.queries)
+	lastBlock, hasLastBlock, err := rl.getCursor()
+	require.NoError(t, err)
+	require.True(t, hasLastBlock)
+	require.Equal(t, uint64(5), lastBlock)
+	require.Equal(t, uint64(0), rl.chainTip.Load())
+}
+
+func TestProcessHeaderUsesFinalizedBlock(t *testing.T) {
+	ethService := &testEthService{finalized: big.NewInt(90)}
+	rl := newTestRootChainListener(t, ethService)
+	rl.confirmationStrategy = FinalizedConfirmationStrategy{}
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("80"), nil))
+
+	// toBlock is capped by the finalized block, not latest minus confirmations
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.Equal(t, [][2]uint64{{81, 90}}, ethService.queries)
+
+	// falls back to confirmations when the tag is unsupported
+	ethService.queries = nil
+	ethService.finalized = nil
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(110)}})
+	require.Equal(t, [][2]uint64{{91, 104}}, ethService.queries)
+}
+
+// fixedConfirmationStrategy always returns the same ceiling
+type fixedConfirmationStrategy struct {
+	ceiling *big.Int
+}
+
+func (s fixedConfirmationStrategy) SafeCeiling(context.Context, *types.Header, *ethclient.Client, uint64) (*big.Int, error) {
+	return s.ceiling, nil
+}
+
+func TestConfirmationStrategies(t *testing.T) {
+	ethService := &testEthService{finalized: big.NewInt(90)}
+	rl := newTestRootChainListener(t, ethService)
+	header := &types.Header{Number: big.NewInt(100)}
+
+	// same header, different ceilings
+	ceiling, err := DepthConfirmationStrategy{}.SafeCeiling(context.Background(), header, rl.chainClient, 6)
+	require.NoError(t, err)
+	require.Equal(t, uint64(94), ceiling.Uint64())
+
+	ceiling, err = FinalizedConfirmationStrategy{}.SafeCeiling(context.Background(), header, rl.chainClient, 6)
+	require.NoError(t, err)
+	require.Equal(t, uint64(90), ceiling.Uint64())
+
+	_, err = DepthConfirmationStrategy{}.SafeCeiling(context.Background(), &types.Header{Number: big.NewInt(6)}, rl.chainClient, 6)
+	require.Error(t, err)
+
+	// the listener dispatches up to the ceiling of its strategy
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("80"), nil))
+	rl.confirmationStrategy = fixedConfirmationStrategy{ceiling: big.NewInt(85)}
+	rl.ProcessHeader(&blockHeader{header: header})
+	require.Equal(t, [][2]uint64{{81, 85}}, ethService.queries)
+}
+
+func TestRewindToCanonicalCursor(t *testing.T) {
+	ethService := &testEthService{}
+	rl := newTestRootChainListener(t, ethService)
+
+	storedCursor := func() string {
+		value, err := rl.storageClient.Get([]byte(lastEthBlockKey), nil)
+		require.NoError(t, err)
+		return string(value)
+	}
+	restart := func() {
+		rl.cursorMu.Lock()
+		rl.cursor = nil
+		rl.cursorMu.Unlock()
+		require.NoError(t, rl.rewindToCanonicalCursor(context.Background()))
+	}
+
+	// no hashes recorded yet
+	require.NoError(t, rl.rewindToCanonicalCursor(context.Background()))
+
+	for _, number := range []int64{100, 110, 120} {
+		rl.advanceCursor(big.NewInt(number))
+	}
+
+	// canonical cursor is kept
+	restart()
+	require.Equal(t, "120", storedCursor())
+
+	// reorg at the stored height rewinds to the last matching checkpoint
+	ethService.reorged = map[uint64]bool{110: true, 120: true}
+	restart()
+	require.Equal(t, "100", storedCursor())
+	lastBlock, ok, err := rl.getCursor()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(100), lastBlock)
+
+	hashes, err := rl.getCursorHashes()
+	require.NoError(t, err)
+	require.Len(t, hashes, 1)
+	require.Equal(t, uint64(100), hashes[0].Number)
+
+	// reorg deeper than the known hashes rewinds before the oldest one
+	rl.advanceCursor(big.NewInt(130))
+	ethService.reorged = map[uint64]bool{100: true, 130: true}
+	restart()
+	require.Equal(t, "99", storedCursor())
+
+	// reorg while running is caught on the next header
+	rl.advanceCursor(big.NewInt(140))
+	ethService.reorged = map[uint64]bool{140: true}
+	ethService.queries = nil
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(150)}, isFinalized: true})
+	require.Equal(t, [][2]uint64{{100, 150}}, ethService.queries)
+	require.Equal(t, "150", storedCursor())
+}
+
+func TestCursorCheckpointInterval(t *testing.T) {
+	rl := newTestRootChainListener(t, &testEthService{})
+	rl.cancelHeaderProcess = func() {}
+
+	storedCursor := func() string {
+		value, err := rl.storageClient.Get([]byte(lastEthBlockKey), nil)
+		require.NoError(t, err)
+		return string(value)
+	}
+
+	// checkpoint every 3 blocks
+	rl.checkpointBlocks = 3
+	rl.checkpointInterval = time.Hour
+
+	rl.advanceCursor(big.NewInt(100))
+	require.Equal(t, "100", storedCursor())
+
+	rl.advanceCursor(big.NewInt(101))
+	rl.advanceCursor(big.NewInt(102))
+	require.Equal(t, "100", storedCursor())
+
+	// in-memory cursor is used between checkpoints
+	lastBlock, ok, err := rl.getCursor()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(102), lastBlock)
+
+	rl.advanceCursor(big.NewInt(103))
+	require.Equal(t, "103", storedCursor())
+
+	// checkpoint once the interval has passed
+	rl.advanceCursor(big.NewInt(104))
+	require.Equal(t, "103", storedCursor())
+	rl.lastCheckpoint = time.Now().Add(-2 * time.Hour)
+	rl.advanceCursor(big.NewInt(105))
+	require.Equal(t, "105", storedCursor())
+
+	// stop flushes the in-memory cursor
+	rl.advanceCursor(big.NewInt(106))
+	require.Equal(t, "105", storedCursor())
+	rl.Stop()
+	require.Equal(t, "106", storedCursor())
+}
+
+func TestEventTaskNameRouting(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+
+	ethService := &testEthService{
+		logs: []types.Log{{BlockNumber: 10, Topics: []ethCommon.Hash{stateSenderABI.Events["StateSynced"].ID}}},
+	}
+	rl := newTestRootChainListener(t, ethService)
+	rl.taskNames = parseEventTaskNames(" StateSynced:customStateSynced, Invalid, StakeAck:")
+	require.Equal(t, map[string]string{"StateSynced": "customStateSynced"}, rl.taskNames)
+
+	// unset events keep their default task names
+	require.Equal(t, "customStateSynced", rl.getTaskName("StateSynced"))
+	require.Equal(t, "sendStakingAckToHeimdall", rl.getTaskName("StakeAck"))
+
+	var dispatched []string
+	for _, taskName := range []string{"customStateSynced", "sendStateSyncedToHeimdall"} {
+		taskName := taskName
+		require.NoError(t, rl.queueConnector.Server.RegisterTask(taskName, func(eventName string, logBytes string, rootChain string) error {
+			dispatched = append(dispatched, taskName)
+			return nil
+		}))
+	}
+
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(10)))
+	require.Equal(t, []string{"customStateSynced"}, dispatched)
+}
+
+func TestBroadcastLogsSkipsUnencodableLogs(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+
+	ethService := &testEthService{
+		logs: []types.Log{{BlockNumber: 10, Topics: []ethCommon.Hash{stateSenderABI.Events["StateSynced"].ID}}},
+	}
+	rl := newTestRootChainListener(t, ethService)
+
+	dispatched := 0
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		dispatched++
+		return nil
+	}))
+
+	// payload can't be encoded, log is skipped
+	rl.payloadFormat = "invalid"
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(10)))
+	require.Zero(t, dispatched)
+
+	rl.payloadFormat = ""
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(10)))
+	require.Equal(t, 1, dispatched)
+}
+
+func TestFilterLogsBisect(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+
+	// provider rejects ranges wider than 2 blocks
+	ethService := &testEthService{
+		logs: []types.Log{
+			{BlockNumber: 10, Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 13, Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 17, Topics: []ethCommon.Hash{stateSyncedID}},
+		},
+		maxLogRange: 2,
+	}
+	rl := newTestRootChainListener(t, ethService)
+
+	logs, err := rl.filterLogsBisect(context.Background(), ethereum.FilterQuery{FromBlock: big.NewInt(10), ToBlock: big.NewInt(17)})
+	require.NoError(t, err)
+	require.Len(t, logs, 3)
+	for i, blockNumber := range []uint64{10, 13, 17} {
+		require.Equal(t, blockNumber, logs[i].BlockNumber)
+	}
+
+	// halves are queried until they fit
+	require.Equal(t, [][2]uint64{
+		{10, 17},
+		{10, 13}, {10, 11}, {12, 13},
+		{14, 17}, {14, 15}, {16, 17},
+	}, ethService.queries)
+
+	require.True(t, isTooManyResultsError(errors.New("Log response size exceeded.")))
+	require.False(t, isTooManyResultsError(errors.New("connection refused")))
+	ethService.maxLogRange = 0
+
+	// configured cap bisects full responses down to single blocks
+	ethService.queries = nil
+	rl.filterLogsCap = 1
+	logs, err = rl.filterLogsBisect(context.Background(), ethereum.FilterQuery{FromBlock: big.NewInt(12), ToBlock: big.NewInt(13)})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, [][2]uint64{{12, 13}, {12, 12}, {13, 13}}, ethService.queries)
+}
+
+func TestGetSyncProgress(t *testing.T) {
+	rl := newTestRootChainListener(t, &testEthService{})
+
+	// nothing processed
+	progress, err := rl.GetSyncProgress()
+	require.NoError(t, err)
+	require.Equal(t, SyncProgress{}, progress)
+
+	// cursor at 250 and tip at 1000
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("250"), nil))
+	rl.chainTip.Store(1000)
+
+	progress, err = rl.GetSyncProgress()
+	require.NoError(t, err)
+	require.Equal(t, SyncProgress{Cursor: 250, Tip: 1000, BlocksRemaining: 750, Percentage: 25}, progress)
+
+	// in-memory cursor is preferred over storage
+	rl.advanceCursor(big.NewInt(900))
+	progress, err = rl.GetSyncProgress()
+	require.NoError(t, err)
+	require.Equal(t, uint64(900), progress.Cursor)
+	require.Equal(t, uint64(100), progress.BlocksRemaining)
+	require.InDelta(t, 90, progress.Percentage, 0.001)
+
+	// processed header moves the tip and caught up cursor reports complete
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(900)}, isFinalized: true})
+	progress, err = rl.GetSyncProgress()
+	require.NoError(t, err)
+	require.Equal(t, SyncProgress{Cursor: 900, Tip: 900, Percentage: 100}, progress)
+}
+
+func TestIsHealthy(t *testing.T) {
+	rl := newTestRootChainListener(t, &testEthService{})
+	rl.healthStaleness = time.Minute
+
+	// polling not running
+	require.False(t, rl.IsHealthy())
+
+	// polling alive, but nothing processed yet
+	ctx, cancel := context.WithCancel(context.Background())
+	rl.pollInterval = time.Hour
+	done := make(chan struct{})
+	go func() {
+		rl.startPolling(ctx, nil)
+		close(done)
+	}()
+	require.Eventually(t, rl.pollingAlive.Load, time.Second, time.Millisecond)
+	require.False(t, rl.IsHealthy())
+
+	// processed header
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}, isFinalized: true})
+	require.True(t, rl.IsHealthy())
+
+	// processed time falls out of the staleness window
+	rl.lastProgress.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	require.False(t, rl.IsHealthy())
+
+	rl.markProgress()
+	require.True(t, rl.IsHealthy())
+
+	// stopped polling
+	cancel()
+	<-done
+	require.False(t, rl.IsHealthy())
+}
+
+func TestConfirmationOverride(t *testing.T) {
+	ethService := &testEthService{}
+	rl := newTestRootChainListener(t, ethService)
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("80"), nil))
+
+	// override raises the governed 6 confirmations
+	rl.minConfirmations = 10
+	require.Equal(t, uint64(10), rl.getRequiredConfirmations(6))
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.Equal(t, [][2]uint64{{81, 90}}, ethService.queries)
+
+	// override below the governed value is ignored
+	ethService.queries = nil
+	rl.minConfirmations = 3
+	require.Equal(t, uint64(6), rl.getRequiredConfirmations(6))
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(110)}})
+	require.Equal(t, [][2]uint64{{91, 104}}, ethService.queries)
+}
+
+func TestBackfillDispatchesNewestFirst(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+	stakingInfoABI, err := abi.JSON(strings.NewReader(stakinginfo.StakinginfoABI))
+	require.NoError(t, err)
+	stakeAckID := stakingInfoABI.Events["StakeAck"].ID
+	stakeAckLog := func(blockNumber uint64, nonce int64) types.Log {
+		return types.Log{
+			BlockNumber: blockNumber,
+			Topics:      []ethCommon.Hash{stakeAckID, ethCommon.BigToHash(big.NewInt(1)), ethCommon.BigToHash(big.NewInt(nonce))},
+		}
+	}
+
+	ethService := &testEthService{
+		logs: []types.Log{
+			{BlockNumber: 10, Topics: []ethCommon.Hash{stateSyncedID}},
+			stakeAckLog(11, 5),
+			{BlockNumber: 12, Index: 0, Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 12, Index: 1, Topics: []ethCommon.Hash{stateSyncedID}},
+			stakeAckLog(13, 6),
+		},
+	}
+	rl := newTestRootChainListener(t, ethService)
+
+	var dispatched []string
+	recordTask := func(eventName string, logBytes string, rootChain string) error {
+		var vLog types.Log
+		require.NoError(t, json.Unmarshal([]byte(logBytes), &vLog))
+		dispatched = append(dispatched, fmt.Sprintf("%s:%d:%d", eventName, vLog.BlockNumber, vLog.Index))
+		return nil
+	}
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", recordTask))
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStakingAckToHeimdall", recordTask))
+
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("50"), nil))
+
+	// blocks are reversed, logs within a block keep their order
+	require.NoError(t, rl.Backfill(big.NewInt(10), big.NewInt(13)))
+	require.Equal(t, []string{
+		"StakeAck:13:0",
+		"StateSynced:12:0",
+		"StateSynced:12:1",
+		"StakeAck:11:0",
+		"StateSynced:10:0",
+	}, dispatched)
+
+	// cursor ahead of the range isn't moved back
+	lastBlock, err := rl.storageClient.Get([]byte(lastEthBlockKey), nil)
+	require.NoError(t, err)
+	require.Equal(t, "50", string(lastBlock))
+
+	// older nonce wasn't skipped, and both nonces are deduplicated on re-scan
+	dispatched = nil
+	require.NoError(t, rl.Backfill(big.NewInt(11), big.NewInt(13)))
+	require.Equal(t, []string{"StateSynced:12:0", "StateSynced:12:1"}, dispatched)
+
+	// inverted range is rejected
+	require.Error(t, rl.Backfill(big.NewInt(13), big.NewInt(10)))
+}
+
+func TestBackfillCursor(t *testing.T) {
+	rl := newTestRootChainListener(t, &testEthService{})
+	cursor := func() string {
+		lastBlock, err := rl.storageClient.Get([]byte(lastEthBlockKey), nil)
+		require.NoError(t, err)
+		return string(lastBlock)
+	}
+
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("100"), nil))
+
+	// range past the cursor leaves the gap to the header process
+	require.NoError(t, rl.Backfill(big.NewInt(200), big.NewInt(300)))
+	require.Equal(t, "100", cursor())
+
+	// range continuing from the cursor advances it
+	require.NoError(t, rl.Backfill(big.NewInt(90), big.NewInt(150)))
+	require.Equal(t, "150", cursor())
+
+	// skipped dispatch is reported
+	rl.Pause()
+	require.ErrorIs(t, rl.Backfill(big.NewInt(150), big.NewInt(200)), errDispatchPaused)
+	require.Equal(t, "150", cursor())
+}
+
+func TestBroadcastLogsOrdersHeaderBlocks(t *testing.T) {
+	rootChainABI, err := abi.JSON(strings.NewReader(rootchain.RootchainABI))
+	require.NoError(t, err)
+	newHeaderBlockID := rootChainABI.Events["NewHeaderBlock"].ID
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+	headerBlockLog := func(blockNumber uint64, index uint, headerBlockID int64) types.Log {
+		return types.Log{
+			BlockNumber: blockNumber,
+			Index:       index,
+			Topics:      []ethCommon.Hash{newHeaderBlockID, {}, ethCommon.BigToHash(big.NewInt(headerBlockID)), {}},
+		}
+	}
+
+	// two checkpoints land in block 10, logged out of header order
+	ethService := &testEthService{
+		logs: []types.Log{
+			headerBlockLog(10, 0, 20000),
+			{BlockNumber: 10, Index: 1, Topics: []ethCommon.Hash{stateSyncedID}},
+			headerBlockLog(10, 2, 10000),
+			headerBlockLog(11, 0, 30000),
+		},
+	}
+	rl := newTestRootChainListener(t, ethService)
+	rl.abis = append(rl.abis, &rootChainABI)
+
+	var dispatched []string
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendCheckpointAckToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		var vLog types.Log
+		require.NoError(t, json.Unmarshal([]byte(logBytes), &vLog))
+		dispatched = append(dispatched, fmt.Sprintf("%s:%d:%v", eventName, vLog.BlockNumber, vLog.Topics[2].Big()))
+		return nil
+	}))
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		var vLog types.Log
+		require.NoError(t, json.Unmarshal([]byte(logBytes), &vLog))
+		dispatched = append(dispatched, fmt.Sprintf("%s:%d:%d", eventName, vLog.BlockNumber, vLog.Index))
+		return nil
+	}))
+
+	// each header block gets its own ack task, ascending within the block
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(11)))
+	require.Equal(t, []string{
+		"NewHeaderBlock:10:10000",
+		"StateSynced:10:1",
+		"NewHeaderBlock:10:20000",
+		"NewHeaderBlock:11:30000",
+	}, dispatched)
+}
+
+func TestQueueBackpressureStallsCursor(t *testing.T) {
+	ethService := &testEthService{}
+	rl := newTestRootChainListener(t, ethService)
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("80"), nil))
+
+	pending := 10
+	rl.maxQueuedTasks = 10
+	rl.queuedTasksLowWater = 4
+	rl.pendingTasks = func() (int, error) { return pending, nil }
+
+	cursor := func() string {
+		lastBlock, err := rl.storageClient.Get([]byte(lastEthBlockKey), nil)
+		require.NoError(t, err)
+		return string(lastBlock)
+	}
+
+	// full queue, nothing is queried and the cursor stalls
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.Empty(t, ethService.queries)
+	require.Equal(t, "80", cursor())
+
+	// below the ceiling but above the low-water mark, still paused
+	pending = 5
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.Empty(t, ethService.queries)
+	require.Equal(t, "80", cursor())
+
+	// drained below the low-water mark, the stalled range is processed
+	pending = 3
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.Equal(t, [][2]uint64{{81, 94}}, ethService.queries)
+	require.Equal(t, "94", cursor())
+}
+
+func TestPauseHoldsDispatch(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+
+	ethService := &testEthService{
+		logs: []types.Log{{BlockNumber: 90, Topics: []ethCommon.Hash{stateSyncedID}}},
+	}
+	rl := newTestRootChainListener(t, ethService)
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("80"), nil))
+
+	var dispatched int
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		dispatched++
+		return nil
+	}))
+
+	cursor := func() string {
+		lastBlock, err := rl.storageClient.Get([]byte(lastEthBlockKey), nil)
+		require.NoError(t, err)
+		return string(lastBlock)
+	}
+
+	// paused, nothing is queried or dispatched and the cursor is held, the header is still tracked
+	rl.Pause()
+	require.True(t, rl.IsPaused())
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.Empty(t, ethService.queries)
+	require.Zero(t, dispatched)
+	require.Equal(t, "80", cursor())
+	require.Equal(t, uint64(94), rl.chainTip.Load())
+
+	// resumed, the held range is processed on the next header
+	rl.Resume()
+	require.False(t, rl.IsPaused())
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.Equal(t, [][2]uint64{{81, 94}}, ethService.queries)
+	require.Equal(t, 1, dispatched)
+	require.Equal(t, "94", cursor())
+}
+
+func TestCaughtUpSignal(t *testing.T) {
+	ethService := &testEthService{}
+	rl := newTestRootChainListener(t, ethService)
+	require.NoError(t, rl.storageClient.Put([]byte(lastEthBlockKey), []byte("80"), nil))
+	rl.maxQueryBlocks = 5
+
+	var signals int
+	rl.SetCaughtUpHandler(func() { signals++ })
+
+	// backfilling in steps of the max query range towards the ceiling of 94
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.False(t, rl.IsCaughtUp())
+	require.Zero(t, signals)
+
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	require.True(t, rl.IsCaughtUp())
+	require.Equal(t, 1, signals)
+
+	// following the tip signals only once
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(100)}})
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(101)}})
+	require.True(t, rl.IsCaughtUp())
+	require.Equal(t, 1, signals)
+
+	// falling behind re-arms the signal
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(110)}})
+	require.False(t, rl.IsCaughtUp())
+	rl.ProcessHeader(&blockHeader{header: &types.Header{Number: big.NewInt(110)}})
+	require.True(t, rl.IsCaughtUp())
+	require.Equal(t, 2, signals)
+}
+
+func TestCheckReceiptsDropsFailedTxLogs(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+
+	failedTx := ethCommon.HexToHash("0x02")
+	ethService := &testEthService{
+		logs: []types.Log{
+			{BlockNumber: 10, TxHash: ethCommon.HexToHash("0x01"), Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 11, TxHash: failedTx, Index: 0, Topics: []ethCommon.Hash{stateSyncedID}},
+			{BlockNumber: 11, TxHash: failedTx, Index: 1, Topics: []ethCommon.Hash{stateSyncedID}},
+		},
+		failedTxs: map[ethCommon.Hash]bool{failedTx: true},
+	}
+	rl := newTestRootChainListener(t, ethService)
+
+	var dispatched []ethCommon.Hash
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		var vLog types.Log
+		require.NoError(t, json.Unmarshal([]byte(logBytes), &vLog))
+		dispatched = append(dispatched, vLog.TxHash)
+		return nil
+	}))
+
+	// off by default, no receipts are fetched
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(11)))
+	require.Len(t, dispatched, 3)
+	require.Zero(t, ethService.receipts)
+
+	// logs of the failed transaction are dropped, one receipt per transaction
+	dispatched = nil
+	rl.checkReceipts = true
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(11)))
+	require.Equal(t, []ethCommon.Hash{ethCommon.HexToHash("0x01")}, dispatched)
+	require.Equal(t, 2, ethService.receipts)
+}
+
+func TestOversizedStateSyncedIsDeadLettered(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+
+	ethService := &testEthService{
+		logs: []types.Log{
+			{BlockNumber: 10, Index: 0, TxHash: ethCommon.HexToHash("0x01"), Topics: []ethCommon.Hash{stateSyncedID}, Data: make([]byte, 64)},
+			{BlockNumber: 10, Index: 1, TxHash: ethCommon.HexToHash("0x02"), Topics: []ethCommon.Hash{stateSyncedID}, Data: make([]byte, 65)},
+		},
+	}
+	rl := newTestRootChainListener(t, ethService)
+	rl.maxStateDataSize = 64
+
+	var dispatched []types.Log
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		var vLog types.Log
+		require.NoError(t, json.Unmarshal([]byte(logBytes), &vLog))
+		dispatched = append(dispatched, vLog)
+		return nil
+	}))
+
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(10)))
+
+	// only the log within the limit is sent as a task
+	require.Len(t, dispatched, 1)
+	require.Equal(t, uint(0), dispatched[0].Index)
+	require.Equal(t, uint64(1), rl.stateSyncedCountWithDecay)
+
+	// oversized log is kept in storage with its reason
+	oversized := ethService.logs[1]
+	entryBytes, err := rl.storageClient.Get(stateSyncedDeadLetterKey(&oversized), nil)
+	require.NoError(t, err)
+
+	var entry stateSyncedDeadLetter
+	require.NoError(t, json.Unmarshal(entryBytes, &entry))
+	require.Contains(t, entry.Reason, "data size 65 exceeds limit 64")
+	require.Equal(t, oversized.TxHash, entry.Log.TxHash)
+
+	has, err := rl.storageClient.Has(stateSyncedDeadLetterKey(&ethService.logs[0]), nil)
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
+func TestVerifyInclusionDropsUnprovenLogs(t *testing.T) {
+	stateSenderABI, err := abi.JSON(strings.NewReader(statesender.StatesenderABI))
+	require.NoError(t, err)
+	stateSyncedID := stateSenderABI.Events["StateSynced"].ID
+
+	newLog := func(block uint64, txIndex uint, data byte) types.Log {
+		return types.Log{
+			BlockNumber: block,
+			TxIndex:     txIndex,
+			Index:       txIndex,
+			TxHash:      ethCommon.BytesToHash([]byte{byte(block), byte(txIndex)}),
+			Topics:      []ethCommon.Hash{stateSyncedID},
+			Data:        []byte{data},
+		}
+	}
+	newReceipts := func(logs ...types.Log) types.Receipts {
+		receipts := make(types.Receipts, 0, len(logs))
+		for i := range logs {
+			receipt := &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				CumulativeGasUsed: uint64(21000 * (i + 1)),
+				TxHash:            logs[i].TxHash,
+				Logs:              []*types.Log{&logs[i]},
+			}
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			receipts = append(receipts, receipt)
+		}
+		return receipts
+	}
+
+	// block 10 is served honestly, block 11 receipts are tampered after the header was sealed
+	block10 := []types.Log{newLog(10, 0, 0x01), newLog(10, 1, 0x02)}
+	block11 := []types.Log{newLog(11, 0, 0x03)}
+	tampered := []types.Log{newLog(11, 0, 0x04)}
+
+	ethService := &testEthService{
+		logs: append(append([]types.Log{}, block10...), tampered...),
+		blockReceipts: map[uint64]types.Receipts{
+			10: newReceipts(block10...),
+			11: newReceipts(tampered...),
+		},
+		receiptsRoots: map[uint64]ethCommon.Hash{
+			10: types.DeriveSha(newReceipts(block10...), trie.NewStackTrie(nil)),
+			11: types.DeriveSha(newReceipts(block11...), trie.NewStackTrie(nil)),
+		},
+	}
+	rl := newTestRootChainListener(t, ethService)
+	rl.verifyInclusion = true
+
+	var dispatched []types.Log
+	require.NoError(t, rl.queueConnector.Server.RegisterTask("sendStateSyncedToHeimdall", func(eventName string, logBytes string, rootChain string) error {
+		var vLog types.Log
+		require.NoError(t, json.Unmarshal([]byte(logBytes), &vLog))
+		dispatched = append(dispatched, vLog)
+		return nil
+	}))
+
+	// only logs proven under the header's receipts root are dispatched
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(11)))
+	require.Len(t, dispatched, 2)
+	require.Equal(t, block10[0].TxHash, dispatched[0].TxHash)
+	require.Equal(t, block10[1].TxHash, dispatched[1].TxHash)
+
+	// filtered log missing from an honest receipt is dropped too
+	dispatched = nil
+	ethService.logs = []types.Log{newLog(10, 0, 0x05)}
+	require.NoError(t, rl.ReplayRange(context.Background(), big.NewInt(10), big.NewInt(10)))
+	require.Empty(t, dispatched)
+}
+
+func TestCheckChainID(t *testing.T) {
+	rootchainABI, err := abi.JSON(strings.NewReader(rootchain.RootchainABI))
+	require.NoError(t, err)
+
+	ethService := &testEthService{chainID: big.NewInt(5)}
+	rl := newTestRootChainListener(t, ethService)
+	rl.abis = append(rl.abis, &rootchainABI)
+
+	// mismatch fails start
+	rl.expectedChainID = 1
+	err = rl.Start()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected 1, connected to 5")
+	require.Nil(t, rl.chainID)
+
+	// matching id is recorded and sent with tasks
+	rl.expectedChainID = 5
+	require.NoError(t, rl.checkChainID(context.Background()))
+	require.Equal(t, big.NewInt(5), rl.chainID)
+	signature := rl.newTaskSignature("sendStateSyncedToHeimdall", "StateSynced", []byte(`{}`), 0)
+	require.Equal(t, "5", signature.Headers[rootChainIDHeader])
+
+	// unknown id is only enforced when expected
+	ethService.chainID = nil
+	rl.chainID = nil
+	require.Error(t, rl.checkChainID(context.Background()))
+	rl.expectedChainID = 0
+	require.NoError(t, rl.checkChainID(context.Background()))
+	require.NotContains(t, rl.newTaskSignature("sendStateSyncedToHeimdall", "StateSynced", []byte(`{}`), 0).Headers, rootChainIDHeader)
+}
@@ -3,7 +3,7 @@ package listener
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"fmt"
 	"math/big"
 	"strconv"
 	"sync"
@@ -12,8 +12,10 @@ import (
 	"github.com/RichardKnop/machinery/v1/tasks"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/maticnetwork/heimdall/bridge/setu/queue"
 	"github.com/maticnetwork/heimdall/bridge/setu/util"
 	chainmanagerTypes "github.com/maticnetwork/heimdall/chainmanager/types"
+	"github.com/maticnetwork/heimdall/contracts/rootchain"
 	"github.com/maticnetwork/heimdall/contracts/stakinginfo"
 	"github.com/maticnetwork/heimdall/helper"
 	"github.com/maticnetwork/heimdall/types"
@@ -30,6 +32,28 @@ type TronListener struct {
 	// ABIs
 	abis           []*abi.ABI
 	stakingInfoAbi *abi.ABI
+
+	// task payload format of logs, see util.EncodeLogPayload
+	payloadFormat string
+
+	// task queue of tron, empty for the shared queue
+	routingKey string
+
+	// window of the per validator task ETA jitter, see util.TaskETAJitter
+	etaJitter time.Duration
+
+	// task delay bounds, see util.ClampTaskDelay
+	minTaskDelay time.Duration
+	maxTaskDelay time.Duration
+
+	// header blocks heimdall acked before start are not acked again, see initConfirmedHeaderBlock
+	skipConfirmedHeaders bool
+	confirmedEndBlock    uint64
+}
+
+// tronHeaderBlockReader reads the checkpoint position of the tron root chain contract
+type tronHeaderBlockReader interface {
+	GetLastChildBlock(contractAddress string) (uint64, error)
 }
 
 // NewTronListener - constructor func
@@ -46,6 +70,13 @@ func NewTronListener() *TronListener {
 			&contractCaller.StakingInfoABI,
 		},
 		stakingInfoAbi: &contractCaller.StakingInfoABI,
+		payloadFormat:  helper.GetConfig().EventPayloadFormat,
+		routingKey:     queue.TaskRoutingKey(types.RootChainTypeTron),
+		etaJitter:      helper.GetConfig().TaskETAJitter,
+		minTaskDelay:   helper.GetConfig().MinTaskDelay,
+		maxTaskDelay:   helper.GetConfig().MaxTaskDelay,
+
+		skipConfirmedHeaders: helper.GetConfig().TronSkipConfirmedHeaders,
 	}
 
 	return TronListener
@@ -53,8 +84,17 @@ func NewTronListener() *TronListener {
 
 // Start starts new block subscription
 func (tl *TronListener) Start() error {
+	// apply configured log level for this listener
+	tl.applyLogLevel()
+
 	tl.Logger.Info("Starting")
 
+	// fail fast if tasks can't be encoded
+	if err := util.ValidateEventPayloadFormat(tl.payloadFormat); err != nil {
+		tl.Logger.Error("Error while validating event payload format", "error", err)
+		return err
+	}
+
 	// create cancellable context
 	headerCtx, cancelHeaderProcess := context.WithCancel(context.Background())
 	tl.cancelHeaderProcess = cancelHeaderProcess
@@ -64,6 +104,12 @@ func (tl *TronListener) Start() error {
 	if startListenBlock != 0 {
 		_ = tl.setStartListenBLock(startListenBlock, tronLastBlockKey)
 	}
+
+	// skip header blocks already acked on heimdall
+	if tl.skipConfirmedHeaders {
+		tl.skipConfirmedHeaderBlocks()
+	}
+
 	// start header process
 	go tl.StartHeaderProcess(headerCtx)
 
@@ -122,6 +168,11 @@ func (tl *TronListener) StartPolling(ctx context.Context, pollInterval time.Dura
 
 // ProcessHeader - process headerblock from rootchain
 func (tl *TronListener) ProcessHeader(newBlockHeader *blockHeader) {
+	if !newBlockHeader.isValid() {
+		tl.Logger.Error("Ignoring invalid block header")
+		return
+	}
+
 	newHeader := newBlockHeader.header
 	tl.Logger.Debug("New block detected", "blockNumber", newHeader.Number)
 
@@ -218,11 +269,18 @@ func (tl *TronListener) queryAndBroadcastEvents(chainManagerParams *chainmanager
 		topic := vLog.Topics[0].Bytes()
 		for _, abiObject := range tl.abis {
 			selectedEvent := helper.EventByID(abiObject, topic)
-			logBytes, _ := json.Marshal(vLog)
+			logBytes, _ := util.EncodeLogPayload(tl.payloadFormat, vLog)
 			if selectedEvent != nil {
 				tl.Logger.Debug("ReceivedTronEvent", "eventname", selectedEvent.Name)
 				switch selectedEvent.Name {
 				case "NewHeaderBlock":
+					if tl.skipConfirmedHeaders {
+						event := new(rootchain.RootchainNewHeaderBlock)
+						if err := helper.UnpackLog(abiObject, event, selectedEvent.Name, &vLog); err == nil && tl.isConfirmedHeaderBlock(event.End) {
+							tl.Logger.Debug("Skipping header block acked before start", "headerBlockId", event.HeaderBlockId, "end", event.End, "confirmedEndBlock", tl.confirmedEndBlock)
+							break
+						}
+					}
 					if isCurrentValidator, delay := util.CalculateTaskDelay(tl.cliCtx); isCurrentValidator {
 						tl.sendTaskWithDelay("sendCheckpointAckToHeimdall", selectedEvent.Name, logBytes, delay)
 					}
@@ -340,11 +398,16 @@ func (tl *TronListener) sendTaskWithDelay(taskName string, eventName string, eve
 	}
 	signature.RetryCount = 3
 	signature.RetryTimeout = 3
+	signature.RoutingKey = tl.routingKey
+	if clamped, ok := util.ClampTaskDelay(delay, tl.minTaskDelay, tl.maxTaskDelay); ok {
+		tl.Logger.Info("Tron task delay out of bounds, clamped", "taskName", taskName, "delay", delay, "clamped", clamped)
+		delay = clamped
+	}
 	// add delay for task so that multiple validators won't send same transaction at same time
-	eta := time.Now().Add(delay)
+	eta := time.Now().Add(delay + util.TaskETAJitter(helper.GetAddress(), tl.etaJitter))
 	signature.ETA = &eta
 	tl.Logger.Info("Sending tron task", "taskName", taskName, "currentTime", time.Now(), "delayTime", eta)
-	_, err := tl.queueConnector.Server.SendTask(signature)
+	_, err := tl.queueConnector.SendTask(signature)
 	if err != nil {
 		tl.Logger.Error("Error sending tron task", "taskName", taskName, "error", err)
 	}
@@ -354,6 +417,53 @@ func (tl *TronListener) sendTaskWithDelay(taskName string, eventName string, eve
 // utils
 //
 
+// skipConfirmedHeaderBlocks sets the starting point from the last tron checkpoint acked on heimdall
+func (tl *TronListener) skipConfirmedHeaderBlocks() {
+	chainManagerParams, err := tl.getChainManagerParams()
+	if err != nil {
+		return
+	}
+
+	checkpoint, err := util.GetlastestCheckpoint(tl.cliCtx, types.RootChainTypeTron)
+	if err != nil {
+		tl.Logger.Info("No acked tron checkpoint, not skipping", "error", err)
+		return
+	}
+
+	if err := tl.initConfirmedHeaderBlock(tl.contractConnector.TronChainRPC, chainManagerParams.ChainParams.TronChainAddress, checkpoint.EndBlock); err != nil {
+		tl.Logger.Error("Error while checking acked tron checkpoint, not skipping", "error", err)
+	}
+}
+
+// initConfirmedHeaderBlock records the end block of the last checkpoint acked on heimdall as starting point,
+// header blocks ending at or before it are skipped as the checkpoint processor would drop their acks.
+// The last child block on tron is read once as sanity check, tron behind heimdall is an inconsistent read
+// and leaves the starting point unset.
+func (tl *TronListener) initConfirmedHeaderBlock(reader tronHeaderBlockReader, contractAddress string, ackedEndBlock uint64) error {
+	childBlock, err := reader.GetLastChildBlock(contractAddress)
+	if err != nil {
+		return err
+	}
+
+	if childBlock < ackedEndBlock {
+		return fmt.Errorf("last child block %d on tron is behind acked end block %d", childBlock, ackedEndBlock)
+	}
+
+	tl.confirmedEndBlock = ackedEndBlock
+	tl.Logger.Info("Skipping tron header blocks acked before start", "ackedEndBlock", ackedEndBlock, "lastChildBlock", childBlock)
+
+	return nil
+}
+
+// isConfirmedHeaderBlock returns whether a header block ending at end was acked on heimdall before start
+func (tl *TronListener) isConfirmedHeaderBlock(end *big.Int) bool {
+	if !tl.skipConfirmedHeaders || tl.confirmedEndBlock == 0 || end == nil {
+		return false
+	}
+
+	return end.Uint64() <= tl.confirmedEndBlock
+}
+
 func (tl *TronListener) getChainManagerParams() (*chainmanagerTypes.Params, error) {
 	chainmanagerParams, err := util.GetChainmanagerParams(tl.cliCtx)
 	if err != nil {
@@ -0,0 +1,59 @@
+package listener
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/maticnetwork/heimdall/bridge/setu/util"
+)
+
+// testHeaderBlockReader serves a fixed tron checkpoint position
+type testHeaderBlockReader struct {
+	childBlock uint64
+	err        error
+	calls      int
+}
+
+func (r *testHeaderBlockReader) GetLastChildBlock(_ string) (uint64, error) {
+	r.calls++
+	return r.childBlock, r.err
+}
+
+func TestInitConfirmedHeaderBlock(t *testing.T) {
+	tl := &TronListener{BaseListener: BaseListener{Logger: util.Logger()}, skipConfirmedHeaders: true}
+
+	// nothing skipped before the starting point is read
+	require.False(t, tl.isConfirmedHeaderBlock(big.NewInt(1)))
+
+	// read errors leave the starting point unset
+	require.Error(t, tl.initConfirmedHeaderBlock(&testHeaderBlockReader{err: errors.New("unavailable")}, "41aa", 1279))
+	require.False(t, tl.isConfirmedHeaderBlock(big.NewInt(1)))
+
+	// tron can't be behind the checkpoint acked on heimdall
+	require.Error(t, tl.initConfirmedHeaderBlock(&testHeaderBlockReader{childBlock: 1023}, "41aa", 1279))
+	require.False(t, tl.isConfirmedHeaderBlock(big.NewInt(1)))
+
+	// starting point advanced to the acked checkpoint with one read, not to the tron header block
+	reader := &testHeaderBlockReader{childBlock: 2047}
+	require.NoError(t, tl.initConfirmedHeaderBlock(reader, "41aa", 1279))
+	require.Equal(t, 1, reader.calls)
+	require.Equal(t, uint64(1279), tl.confirmedEndBlock)
+
+	require.True(t, tl.isConfirmedHeaderBlock(big.NewInt(1023)))
+	require.True(t, tl.isConfirmedHeaderBlock(big.NewInt(1279)))
+
+	// confirmed on tron but not acked on heimdall yet is still acked
+	require.False(t, tl.isConfirmedHeaderBlock(big.NewInt(2047)))
+
+	// no acked checkpoint skips nothing
+	require.NoError(t, tl.initConfirmedHeaderBlock(&testHeaderBlockReader{}, "41aa", 0))
+	require.False(t, tl.isConfirmedHeaderBlock(big.NewInt(1023)))
+
+	// disabled by config
+	require.NoError(t, tl.initConfirmedHeaderBlock(reader, "41aa", 1279))
+	tl.skipConfirmedHeaders = false
+	require.False(t, tl.isConfirmedHeaderBlock(big.NewInt(1023)))
+}
@@ -332,8 +332,8 @@ func (cp *CheckpointProcessor) sendCheckpointAckToHeimdall(eventName string, che
 		return err
 	}
 
-	var log = types.Log{}
-	if err := json.Unmarshal([]byte(checkpointAckStr), &log); err != nil {
+	log, err := util.DecodeLogPayload(checkpointAckStr)
+	if err != nil {
 		cp.Logger.Error("Error while unmarshalling event from rootchain", "error", err)
 		return err
 	}
@@ -924,8 +924,8 @@ func (cp *CheckpointProcessor) shouldSendCheckpoint(checkpointContext *Checkpoin
 func (cp *CheckpointProcessor) sendAddNewChainToHeimdall(eventName string, newChainStr string, _ string) error {
 	cp.Logger.Info("Received sendAddNewChainToHeimdall request", "newChainStr", newChainStr)
 
-	var log = types.Log{}
-	if err := json.Unmarshal([]byte(newChainStr), &log); err != nil {
+	log, err := util.DecodeLogPayload(newChainStr)
+	if err != nil {
 		cp.Logger.Error("Error while unmarshalling new chain event from tron", "error", err)
 		return err
 	}
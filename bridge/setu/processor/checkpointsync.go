@@ -8,7 +8,6 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	authTypes "github.com/maticnetwork/heimdall/auth/types"
 	"github.com/maticnetwork/heimdall/bridge/setu/util"
 	checkpointTypes "github.com/maticnetwork/heimdall/checkpoint/types"
@@ -164,8 +163,8 @@ func (cp *CheckpointProcessor) sendCheckpointSyncAckToHeimdall(eventName string,
 	}
 	checkpointParams := checkpointContext.CheckpointParams
 
-	var log = types.Log{}
-	if err := json.Unmarshal([]byte(checkpointSyncAckStr), &log); err != nil {
+	log, err := util.DecodeLogPayload(checkpointSyncAckStr)
+	if err != nil {
 		cp.Logger.Error("Error while unmarshalling event from stake chain", "error", err)
 		return err
 	}
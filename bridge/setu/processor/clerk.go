@@ -52,12 +52,33 @@ func (cp *ClerkProcessor) RegisterTasks() {
 // 1. check if this deposit event has to be broadcasted to heimdall
 // 2. create and broadcast  record transaction to heimdall
 func (cp *ClerkProcessor) sendStateSyncedToHeimdall(eventName string, logBytes string, rootChainType string) error {
-	var vLog = types.Log{}
-	if err := json.Unmarshal([]byte(logBytes), &vLog); err != nil {
+	// batched task carries an array of logs from a single block
+	if util.IsLogBatchPayload(logBytes) {
+		vLogs, err := util.DecodeLogBatchPayload(logBytes)
+		if err != nil {
+			cp.Logger.Error("Error while unmarshalling events from rootchain", "error", err)
+			return err
+		}
+
+		for _, vLog := range vLogs {
+			if err := cp.sendStateSyncedLogToHeimdall(eventName, vLog, rootChainType); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	vLog, err := util.DecodeLogPayload(logBytes)
+	if err != nil {
 		cp.Logger.Error("Error while unmarshalling event from rootchain", "error", err)
 		return err
 	}
 
+	return cp.sendStateSyncedLogToHeimdall(eventName, vLog, rootChainType)
+}
+
+// sendStateSyncedLogToHeimdall broadcasts the event record for a single state synced log
+func (cp *ClerkProcessor) sendStateSyncedLogToHeimdall(eventName string, vLog types.Log, rootChainType string) error {
 	clerkContext, err := cp.getClerkContext()
 	if err != nil {
 		return err
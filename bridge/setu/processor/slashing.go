@@ -10,7 +10,6 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	authTypes "github.com/maticnetwork/heimdall/auth/types"
 	"github.com/maticnetwork/heimdall/bridge/setu/util"
 	chainmanagerTypes "github.com/maticnetwork/heimdall/chainmanager/types"
@@ -175,8 +174,8 @@ sendTickAckToHeimdall - sends tick ack msg to heimdall
 */
 //todo: add rootChainType param
 func (sp *SlashingProcessor) sendTickAckToHeimdall(eventName string, logBytes string) error {
-	var vLog = types.Log{}
-	if err := json.Unmarshal([]byte(logBytes), &vLog); err != nil {
+	vLog, err := util.DecodeLogPayload(logBytes)
+	if err != nil {
 		sp.Logger.Error("Error while unmarshalling event from rootchain", "error", err)
 		return err
 	}
@@ -225,8 +224,8 @@ func (sp *SlashingProcessor) sendTickAckToHeimdall(eventName string, logBytes st
 sendUnjailToHeimdall - sends unjail msg to heimdall
 */
 func (sp *SlashingProcessor) sendUnjailToHeimdall(eventName string, logBytes string) error {
-	var vLog = types.Log{}
-	if err := json.Unmarshal([]byte(logBytes), &vLog); err != nil {
+	vLog, err := util.DecodeLogPayload(logBytes)
+	if err != nil {
 		sp.Logger.Error("Error while unmarshalling event from rootchain", "error", err)
 		return err
 	}
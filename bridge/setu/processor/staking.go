@@ -14,7 +14,6 @@ import (
 	cliContext "github.com/cosmos/cosmos-sdk/client/context"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/maticnetwork/heimdall/bridge/setu/util"
 	chainmanagerTypes "github.com/maticnetwork/heimdall/chainmanager/types"
 	"github.com/maticnetwork/heimdall/contracts/stakinginfo"
@@ -98,8 +97,8 @@ func (sp *StakingProcessor) sendValidatorJoinToHeimdall(eventName string, logByt
 		return nil
 	}
 
-	var vLog = types.Log{}
-	if err := json.Unmarshal([]byte(logBytes), &vLog); err != nil {
+	vLog, err := util.DecodeLogPayload(logBytes)
+	if err != nil {
 		sp.Logger.Error("Error while unmarshalling event from rootchain", "error", err)
 		return err
 	}
@@ -180,8 +179,8 @@ func (sp *StakingProcessor) sendUnstakeInitToHeimdall(eventName string, logBytes
 		return nil
 	}
 
-	var vLog = types.Log{}
-	if err := json.Unmarshal([]byte(logBytes), &vLog); err != nil {
+	vLog, err := util.DecodeLogPayload(logBytes)
+	if err != nil {
 		sp.Logger.Error("Error while unmarshalling event from rootchain", "error", err)
 		return err
 	}
@@ -314,8 +313,8 @@ func (sp *StakingProcessor) sendSignerChangeToHeimdall(eventName string, logByte
 		return nil
 	}
 
-	var vLog = types.Log{}
-	if err := json.Unmarshal([]byte(logBytes), &vLog); err != nil {
+	vLog, err := util.DecodeLogPayload(logBytes)
+	if err != nil {
 		sp.Logger.Error("Error while unmarshalling event from rootchain", "error", err)
 		return err
 	}
@@ -434,7 +433,7 @@ func (sp *StakingProcessor) startPolling(ctx context.Context, interval time.Dura
 }
 
 // checkStakingSyncAck - Staking Ack handler
-// 1. Fetch latest validator nonce from rootchain
+// 1. Fetch latest validator nonce from local sync cursor or rootchain
 // 2. check if nonce == queue_nonce.
 // 3. Send Ack to heimdall if required.
 // 4. start next staking sync task.
@@ -463,8 +462,9 @@ func (sp *StakingProcessor) checkStakingSyncAck() {
 		if err != nil || res.Nonce == 0 {
 			continue
 		}
-		currentNonce := sp.getNonceFromRootChain(stakingContext, rootChain, res.ValidatorID.Uint64())
-		if res.Nonce == currentNonce {
+		// a nonce already synced before restart needs no root chain query, only the ack
+		if sp.isStakingNonceSynced(rootChain, res.ValidatorID.Uint64(), res.Nonce) ||
+			res.Nonce == sp.getNonceFromRootChain(stakingContext, rootChain, res.ValidatorID.Uint64()) {
 			// create msg staking ack message
 			msg := stakingTypes.NewMsgStakingSyncAck(
 				helper.GetFromAddress(sp.cliCtx),
@@ -476,6 +476,9 @@ func (sp *StakingProcessor) checkStakingSyncAck() {
 			// return broadcast to heimdall
 			if err := sp.txBroadcaster.BroadcastToHeimdall(msg); err != nil {
 				sp.Logger.Error("Error while broadcasting staking-ack to heimdall", "error", err)
+			} else if err := util.NewStakingSyncCursor(sp.storageClient).SetLastSyncedNonce(rootChain, res.ValidatorID.Uint64(), res.Nonce); err != nil {
+				// node-local recovery hint only
+				sp.Logger.Error("Error while storing last synced staking nonce", "root", rootChain, "validatorID", res.ValidatorID, "error", err)
 			}
 		}
 		sp.checkAndSendStakingSync(rootChain, false)
@@ -528,12 +531,28 @@ func (sp *StakingProcessor) getNonceFromRootChain(stakingContext *StakingContext
 	return 0
 }
 
+// isStakingNonceSynced checks the node-local staking sync cursor for nonce of validator on rootChain.
+// The cursor is only written once the root chain reported the nonce, so it never runs ahead of it.
+func (sp *StakingProcessor) isStakingNonceSynced(rootChain string, validatorID uint64, nonce uint64) bool {
+	lastSynced, found, err := util.NewStakingSyncCursor(sp.storageClient).GetLastSyncedNonce(rootChain, validatorID)
+	if err != nil {
+		sp.Logger.Error("Error while fetching last synced staking nonce", "root", rootChain, "validatorID", validatorID, "error", err)
+		return false
+	}
+
+	return found && nonce <= lastSynced
+}
+
 func (sp *StakingProcessor) shouldSendStakingSync(stakingContext *StakingContext, rootChain string) (*stakingTypes.StakingRecord, bool) {
 	// fetch next staking record from queue
 	res, err := util.GetNextStakingRecord(sp.cliCtx, rootChain)
 	if err != nil || res.Nonce == 0 {
 		return nil, false
 	}
+	// already synced to root chain, waiting for the ack
+	if sp.isStakingNonceSynced(rootChain, res.ValidatorID.Uint64(), res.Nonce) {
+		return nil, false
+	}
 	currentNonce := sp.getNonceFromRootChain(stakingContext, rootChain, res.ValidatorID.Uint64())
 	if res.Nonce > currentNonce {
 		return res, true
@@ -660,8 +679,8 @@ func (sp *StakingProcessor) sendStakingAckToHeimdall(eventName string, StakingAc
 		return nil
 	}
 
-	var log = types.Log{}
-	if err := json.Unmarshal([]byte(StakingAckStr), &log); err != nil {
+	log, err := util.DecodeLogPayload(StakingAckStr)
+	if err != nil {
 		sp.Logger.Error("Error while unmarshalling event from rootchain", "error", err)
 		return err
 	}
@@ -1,18 +1,30 @@
 package queue
 
 import (
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/streadway/amqp"
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/RichardKnop/machinery/v1"
+	"github.com/RichardKnop/machinery/v1/backends/result"
 	"github.com/RichardKnop/machinery/v1/config"
+	"github.com/RichardKnop/machinery/v1/tasks"
 
 	"github.com/maticnetwork/heimdall/bridge/setu/util"
+	"github.com/maticnetwork/heimdall/helper"
 )
 
 type QueueConnector struct {
 	logger log.Logger
 	Server *machinery.Server
+
+	// tasks published and not yet processed by the workers of this process, by consumed queue
+	inFlightMu sync.Mutex
+	inFlight   map[string]int
+	consumed   map[string]bool
 }
 
 const (
@@ -44,19 +56,156 @@ func NewQueueConnector(dialer string) *QueueConnector {
 	}
 
 	// queue connector
-	connector := QueueConnector{
+	connector := &QueueConnector{
 		logger: util.Logger().With("module", "QueueConnector"),
 		Server: server,
 	}
-
 	// connector
-	return &connector
+	return connector
 }
 
-// StartWorker - starts worker to process registered tasks
+// StartWorker - starts workers to process registered tasks, one per consumed queue
 func (qc *QueueConnector) StartWorker() {
-	worker := qc.Server.NewWorker("invoke-processor", 10)
-	qc.logger.Info("Starting machinery worker")
-	errors := make(chan error)
-	worker.LaunchAsync(errors)
+	queues := WorkerQueues(helper.GetConfig().TaskRoutingKeys, helper.GetConfig().TaskWorkerQueues)
+	qc.consume(queues...)
+
+	for _, queue := range queues {
+		var worker *machinery.Worker
+		if queue == QueueName {
+			worker = qc.Server.NewWorker("invoke-processor", 10)
+		} else {
+			worker = qc.Server.NewCustomQueueWorker("invoke-processor-"+queue, 10, queue)
+		}
+
+		worker.SetPostTaskHandler(qc.taskProcessed)
+
+		qc.logger.Info("Starting machinery worker", "queue", queue)
+		errors := make(chan error)
+		worker.LaunchAsync(errors)
+	}
+}
+
+// SendTask publishes a task and counts it as pending once the broker accepted it
+func (qc *QueueConnector) SendTask(signature *tasks.Signature) (*result.AsyncResult, error) {
+	asyncResult, err := qc.Server.SendTask(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	qc.taskPublished(signature)
+	return asyncResult, nil
+}
+
+// PendingTasks returns the number of tasks published to the queue, the shared queue if empty, and not yet
+// processed by the workers of this process. Tasks waiting for their ETA are included, tasks published before
+// the process started, republished on retry or sent to a queue this process doesn't consume are not.
+func (qc *QueueConnector) PendingTasks(queue string) int {
+	qc.inFlightMu.Lock()
+	defer qc.inFlightMu.Unlock()
+
+	return qc.inFlight[qc.queueOf(queue)]
+}
+
+// consume marks queues as consumed by the workers of this process, only their tasks are counted
+func (qc *QueueConnector) consume(queues ...string) {
+	qc.inFlightMu.Lock()
+	defer qc.inFlightMu.Unlock()
+
+	if qc.consumed == nil {
+		qc.consumed = make(map[string]bool)
+	}
+	for _, queue := range queues {
+		qc.consumed[qc.queueOf(queue)] = true
+	}
+}
+
+// taskPublished counts a task published to a consumed queue, no worker here would uncount the others
+func (qc *QueueConnector) taskPublished(signature *tasks.Signature) {
+	qc.inFlightMu.Lock()
+	defer qc.inFlightMu.Unlock()
+
+	queue := qc.queueOf(signature.RoutingKey)
+	if !qc.consumed[queue] {
+		return
+	}
+
+	if qc.inFlight == nil {
+		qc.inFlight = make(map[string]int)
+	}
+	qc.inFlight[queue]++
+}
+
+// taskProcessed uncounts a task once a worker ran it
+func (qc *QueueConnector) taskProcessed(signature *tasks.Signature) {
+	qc.inFlightMu.Lock()
+	defer qc.inFlightMu.Unlock()
+
+	queue := qc.queueOf(signature.RoutingKey)
+	if qc.inFlight[queue] > 0 {
+		qc.inFlight[queue]--
+	}
+}
+
+// queueOf returns the queue of a routing key, the broker routes empty and binding keys to the shared queue
+func (qc *QueueConnector) queueOf(routingKey string) string {
+	if routingKey == "" || routingKey == QueueName {
+		return QueueName
+	}
+
+	if cnf := qc.Server.GetConfig(); cnf != nil && cnf.AMQP != nil && routingKey == cnf.AMQP.BindingKey {
+		return QueueName
+	}
+
+	return routingKey
+}
+
+// TaskRoutingKey returns the configured task queue of a root chain, empty for the shared queue
+func TaskRoutingKey(rootChainType string) string {
+	return ParseRoutingKeys(helper.GetConfig().TaskRoutingKeys)[rootChainType]
+}
+
+// ParseRoutingKeys parses per root chain task queues in the form "root:queue,..."
+func ParseRoutingKeys(routes string) map[string]string {
+	routingKeys := make(map[string]string)
+	for _, item := range strings.Split(routes, ",") {
+		pair := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		if len(pair) == 2 && pair[0] != "" && strings.TrimSpace(pair[1]) != "" {
+			routingKeys[pair[0]] = strings.TrimSpace(pair[1])
+		}
+	}
+
+	return routingKeys
+}
+
+// WorkerQueues returns the queues to consume, the comma separated workerQueues if set,
+// otherwise the shared queue and every routed queue
+func WorkerQueues(routes string, workerQueues string) []string {
+	var queues []string
+	seen := make(map[string]bool)
+	add := func(queue string) {
+		if queue != "" && !seen[queue] {
+			seen[queue] = true
+			queues = append(queues, queue)
+		}
+	}
+
+	if strings.TrimSpace(workerQueues) != "" {
+		for _, queue := range strings.Split(workerQueues, ",") {
+			add(strings.TrimSpace(queue))
+		}
+		return queues
+	}
+
+	routed := make([]string, 0)
+	for _, queue := range ParseRoutingKeys(routes) {
+		routed = append(routed, queue)
+	}
+	sort.Strings(routed)
+
+	add(QueueName)
+	for _, queue := range routed {
+		add(queue)
+	}
+
+	return queues
 }
@@ -0,0 +1,62 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/RichardKnop/machinery/v1"
+	"github.com/RichardKnop/machinery/v1/brokers/eager"
+	"github.com/RichardKnop/machinery/v1/config"
+	"github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerQueues(t *testing.T) {
+	routes := "tron:tron_tasks, eth:eth_tasks,bsc:eth_tasks,invalid"
+	require.Equal(t, map[string]string{"tron": "tron_tasks", "eth": "eth_tasks", "bsc": "eth_tasks"}, ParseRoutingKeys(routes))
+
+	// shared queue and each routed queue once by default
+	require.Equal(t, []string{QueueName}, WorkerQueues("", ""))
+	require.Equal(t, []string{QueueName, "eth_tasks", "tron_tasks"}, WorkerQueues(routes, ""))
+
+	// selected queues only
+	require.Equal(t, []string{"tron_tasks"}, WorkerQueues(routes, " tron_tasks,tron_tasks"))
+}
+
+func TestPendingTasks(t *testing.T) {
+	server, err := machinery.NewServer(&config.Config{
+		Broker:        "eager",
+		ResultBackend: "eager",
+		DefaultQueue:  QueueName,
+		AMQP:          &config.AMQPConfig{BindingKey: "machinery_task"},
+	})
+	require.NoError(t, err)
+
+	qc := &QueueConnector{Server: server}
+	qc.consume(QueueName, "tron_tasks")
+	require.NoError(t, server.RegisterTask("task", func() error { return nil }))
+
+	// published tasks are counted by consumed queue, binding key routes to the shared queue
+	for _, routingKey := range []string{"", "machinery_task", "tron_tasks", "eth_tasks"} {
+		_, err := qc.SendTask(&tasks.Signature{Name: "task", RoutingKey: routingKey})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, qc.PendingTasks(""))
+	require.Equal(t, 1, qc.PendingTasks("tron_tasks"))
+
+	// queues consumed by other processes are never counted
+	require.Equal(t, 0, qc.PendingTasks("eth_tasks"))
+
+	// failed publish is not counted
+	broker := server.GetBroker()
+	server.SetBroker(eager.New())
+	_, err = qc.SendTask(&tasks.Signature{Name: "task", RoutingKey: "tron_tasks"})
+	require.Error(t, err)
+	server.SetBroker(broker)
+	require.Equal(t, 1, qc.PendingTasks("tron_tasks"))
+
+	// processed tasks are uncounted, never below zero
+	qc.taskProcessed(&tasks.Signature{RoutingKey: "tron_tasks"})
+	qc.taskProcessed(&tasks.Signature{RoutingKey: "tron_tasks"})
+	require.Equal(t, 0, qc.PendingTasks("tron_tasks"))
+	require.Equal(t, 2, qc.PendingTasks(QueueName))
+}
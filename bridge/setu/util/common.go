@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"net/url"
 	"os"
 	"strconv"
@@ -211,8 +212,6 @@ func CalculateTaskDelay(cliCtx cliContext.CLIContext) (bool, time.Duration) {
 // It solves for multiple validators sending same transaction.
 // with offset
 func CalculateTaskDelayWithOffset(cliCtx cliContext.CLIContext, offset int) (bool, time.Duration) {
-	// calculate validator position
-	valPosition := 0
 	isCurrentValidator := false
 
 	proposersURL := fmt.Sprintf(ProposersURL, ProposersURLSizeLimit)
@@ -230,17 +229,35 @@ func CalculateTaskDelayWithOffset(cliCtx cliContext.CLIContext, offset int) (boo
 	}
 
 	logger.Info("Fetched proposers ", "currentValidatorsCount", len(proposers))
-	for i, validator := range proposers {
-		if bytes.Equal(validator.Signer.Bytes(), helper.GetAddress()) {
-			valPosition = i + offset
-			isCurrentValidator = true
-			break
-		}
+	return taskDelayByPosition(proposers, offset)
+}
+
+// ClampTaskDelay bounds delay to [minDelay, maxDelay], a zero bound is not applied and maxDelay
+// wins if the bounds cross. It reports whether delay was changed.
+func ClampTaskDelay(delay time.Duration, minDelay time.Duration, maxDelay time.Duration) (time.Duration, bool) {
+	clamped := delay
+	if minDelay > 0 && clamped < minDelay {
+		clamped = minDelay
+	}
+	if maxDelay > 0 && clamped > maxDelay {
+		clamped = maxDelay
 	}
 
-	// calculate delay
-	taskDelay := time.Duration(valPosition) * TaskDelayBetweenEachVal
-	return isCurrentValidator, taskDelay
+	return clamped, clamped != delay
+}
+
+// TaskETAJitter returns a jitter in [0, window) for task ETAs of the given validator.
+// It is derived from the validator address, so a validator's own tasks keep their relative order
+// while validators sharing a delay bucket are spread within the window.
+func TaskETAJitter(validatorAddress []byte, window time.Duration) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write(validatorAddress)
+
+	return time.Duration(h.Sum64() % uint64(window))
 }
 
 // IsCurrentProposer checks if we are current proposer
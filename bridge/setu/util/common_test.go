@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskETAJitter(t *testing.T) {
+	window := 10 * time.Second
+	validatorA := []byte{0x01, 0x02, 0x03}
+	validatorB := []byte{0x04, 0x05, 0x06}
+
+	// stable across runs for the same validator
+	jitterA := TaskETAJitter(validatorA, window)
+	require.Equal(t, jitterA, TaskETAJitter(validatorA, window))
+	require.True(t, jitterA >= 0 && jitterA < window)
+
+	// differs between validators
+	jitterB := TaskETAJitter(validatorB, window)
+	require.True(t, jitterB >= 0 && jitterB < window)
+	require.NotEqual(t, jitterA, jitterB)
+
+	// disabled without a window
+	require.Equal(t, time.Duration(0), TaskETAJitter(validatorA, 0))
+}
+
+func TestClampTaskDelay(t *testing.T) {
+	minDelay, maxDelay := 2*time.Second, 30*time.Second
+
+	// within bounds
+	delay, clamped := ClampTaskDelay(10*time.Second, minDelay, maxDelay)
+	require.False(t, clamped)
+	require.Equal(t, 10*time.Second, delay)
+
+	// zero delay is raised to the floor
+	delay, clamped = ClampTaskDelay(0, minDelay, maxDelay)
+	require.True(t, clamped)
+	require.Equal(t, minDelay, delay)
+
+	// miscomputed large delay is capped
+	delay, clamped = ClampTaskDelay(time.Hour, minDelay, maxDelay)
+	require.True(t, clamped)
+	require.Equal(t, maxDelay, delay)
+
+	// bounds disabled
+	delay, clamped = ClampTaskDelay(time.Hour, 0, 0)
+	require.False(t, clamped)
+	require.Equal(t, time.Hour, delay)
+}
@@ -0,0 +1,93 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Event payload formats of listener tasks
+const (
+	EventPayloadFormatJSON  = "json"  // json log, readable in the broker
+	EventPayloadFormatAmino = "amino" // base64 amino log, smaller and cheaper to encode
+)
+
+// amino payloads are prefixed so consumers can tell them from json, which starts with { or [
+const (
+	aminoLogPrefix      = "amino:log:"
+	aminoLogBatchPrefix = "amino:logs:"
+)
+
+var payloadCdc = codec.New()
+
+// ValidateEventPayloadFormat checks format is a known event payload format, empty format is json
+func ValidateEventPayloadFormat(format string) error {
+	switch format {
+	case "", EventPayloadFormatJSON, EventPayloadFormatAmino:
+		return nil
+	default:
+		return fmt.Errorf("unknown event payload format %q", format)
+	}
+}
+
+// EncodeLogPayload encodes a log as a task payload in the given format, empty format is json
+func EncodeLogPayload(format string, vLog types.Log) ([]byte, error) {
+	return encodePayload(format, aminoLogPrefix, vLog)
+}
+
+// EncodeLogBatchPayload encodes logs of a batched task in the given format, empty format is json
+func EncodeLogBatchPayload(format string, vLogs []types.Log) ([]byte, error) {
+	return encodePayload(format, aminoLogBatchPrefix, vLogs)
+}
+
+func encodePayload(format string, prefix string, v interface{}) ([]byte, error) {
+	if err := ValidateEventPayloadFormat(format); err != nil {
+		return nil, err
+	}
+
+	if format != EventPayloadFormatAmino {
+		return json.Marshal(v)
+	}
+
+	bz, err := payloadCdc.MarshalBinaryBare(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// task args are strings, binary is kept printable
+	return []byte(prefix + base64.StdEncoding.EncodeToString(bz)), nil
+}
+
+// IsLogBatchPayload reports whether payload carries an array of logs
+func IsLogBatchPayload(payload string) bool {
+	return strings.HasPrefix(payload, aminoLogBatchPrefix) || strings.HasPrefix(strings.TrimSpace(payload), "[")
+}
+
+// DecodeLogPayload decodes a log task payload of any format
+func DecodeLogPayload(payload string) (vLog types.Log, err error) {
+	err = decodePayload(payload, aminoLogPrefix, &vLog)
+	return vLog, err
+}
+
+// DecodeLogBatchPayload decodes a batched logs task payload of any format
+func DecodeLogBatchPayload(payload string) (vLogs []types.Log, err error) {
+	err = decodePayload(payload, aminoLogBatchPrefix, &vLogs)
+	return vLogs, err
+}
+
+func decodePayload(payload string, prefix string, v interface{}) error {
+	if !strings.HasPrefix(payload, prefix) {
+		return json.Unmarshal([]byte(payload), v)
+	}
+
+	bz, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(payload, prefix))
+	if err != nil {
+		return err
+	}
+
+	return payloadCdc.UnmarshalBinaryBare(bz, v)
+}
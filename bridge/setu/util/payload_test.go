@@ -0,0 +1,60 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogPayloadFormats(t *testing.T) {
+	vLog := types.Log{
+		Address:     ethCommon.HexToAddress("0x28e4F3a7f651294B9564800b2D01f35189A5bFbE"),
+		Topics:      []ethCommon.Hash{ethCommon.HexToHash("0x103fed9db65eac19c4d870f49ab7520fe03b99f1838e5996caf47e9e43308392"), ethCommon.BigToHash(big.NewInt(7))},
+		Data:        make([]byte, 128),
+		BlockNumber: 15000000,
+		TxHash:      ethCommon.HexToHash("0xab"),
+		TxIndex:     3,
+		BlockHash:   ethCommon.HexToHash("0xcd"),
+		Index:       11,
+	}
+
+	jsonPayload, err := EncodeLogPayload(EventPayloadFormatJSON, vLog)
+	require.NoError(t, err)
+	aminoPayload, err := EncodeLogPayload(EventPayloadFormatAmino, vLog)
+	require.NoError(t, err)
+
+	// compact form round-trips and is smaller
+	decoded, err := DecodeLogPayload(string(aminoPayload))
+	require.NoError(t, err)
+	require.Equal(t, vLog, decoded)
+	require.Less(t, len(aminoPayload), len(jsonPayload))
+
+	// json is the default, consumers read both
+	defaultPayload, err := EncodeLogPayload("", vLog)
+	require.NoError(t, err)
+	require.Equal(t, jsonPayload, defaultPayload)
+	decoded, err = DecodeLogPayload(string(jsonPayload))
+	require.NoError(t, err)
+	require.Equal(t, vLog, decoded)
+	require.False(t, IsLogBatchPayload(string(jsonPayload)))
+	require.False(t, IsLogBatchPayload(string(aminoPayload)))
+
+	// batches are told apart in both formats
+	for _, format := range []string{EventPayloadFormatJSON, EventPayloadFormatAmino} {
+		payload, err := EncodeLogBatchPayload(format, []types.Log{vLog, vLog})
+		require.NoError(t, err)
+		require.True(t, IsLogBatchPayload(string(payload)))
+
+		vLogs, err := DecodeLogBatchPayload(string(payload))
+		require.NoError(t, err)
+		require.Equal(t, []types.Log{vLog, vLog}, vLogs)
+	}
+
+	// unknown format
+	require.Error(t, ValidateEventPayloadFormat("rlp"))
+	_, err = EncodeLogPayload("rlp", vLog)
+	require.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// StakingSyncNoncePrefixKey prefixes the last synced staking nonce keys in bridge storage
+const StakingSyncNoncePrefixKey = "staking-sync-nonce"
+
+// StakingSyncCursor tracks the last synced staking nonce per root chain and validator.
+// It lives in node-local bridge storage and is never part of consensus state,
+// so it is only a recovery hint and may differ between nodes.
+type StakingSyncCursor struct {
+	storageClient *leveldb.DB
+}
+
+// NewStakingSyncCursor creates a staking sync cursor backed by the given bridge storage
+func NewStakingSyncCursor(storageClient *leveldb.DB) *StakingSyncCursor {
+	return &StakingSyncCursor{storageClient: storageClient}
+}
+
+func stakingSyncNonceKey(rootChain string, validatorID uint64) []byte {
+	return []byte(fmt.Sprintf("%s-%s-%d", StakingSyncNoncePrefixKey, rootChain, validatorID))
+}
+
+// GetLastSyncedNonce returns the last synced staking nonce of a validator, and whether one is recorded
+func (c *StakingSyncCursor) GetLastSyncedNonce(rootChain string, validatorID uint64) (uint64, bool, error) {
+	nonceBytes, err := c.storageClient.Get(stakingSyncNonceKey(rootChain, validatorID), nil)
+	if err == leveldb.ErrNotFound {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	nonce, err := strconv.ParseUint(string(nonceBytes), DigitBase, DigitBitSize)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return nonce, true, nil
+}
+
+// SetLastSyncedNonce records the last synced staking nonce of a validator
+func (c *StakingSyncCursor) SetLastSyncedNonce(rootChain string, validatorID uint64, nonce uint64) error {
+	return c.storageClient.Put(
+		stakingSyncNonceKey(rootChain, validatorID),
+		[]byte(strconv.FormatUint(nonce, DigitBase)),
+		nil)
+}
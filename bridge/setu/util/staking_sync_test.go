@@ -0,0 +1,55 @@
+package util
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+
+	hmTypes "github.com/maticnetwork/heimdall/types"
+)
+
+func TestStakingSyncCursor(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "bridge-db")
+	storageClient, err := leveldb.OpenFile(dbPath, nil)
+	require.NoError(t, err)
+
+	cursor := NewStakingSyncCursor(storageClient)
+
+	// nothing synced yet
+	_, found, err := cursor.GetLastSyncedNonce(hmTypes.RootChainTypeEth, 1)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, cursor.SetLastSyncedNonce(hmTypes.RootChainTypeEth, 1, 5))
+	require.NoError(t, cursor.SetLastSyncedNonce(hmTypes.RootChainTypeTron, 1, 2))
+
+	// persists across reopen
+	require.NoError(t, storageClient.Close())
+	storageClient, err = leveldb.OpenFile(dbPath, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storageClient.Close() })
+	cursor = NewStakingSyncCursor(storageClient)
+
+	nonce, found, err := cursor.GetLastSyncedNonce(hmTypes.RootChainTypeEth, 1)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint64(5), nonce)
+
+	// advances, tracked per root chain and validator
+	require.NoError(t, cursor.SetLastSyncedNonce(hmTypes.RootChainTypeEth, 1, 6))
+	nonce, _, err = cursor.GetLastSyncedNonce(hmTypes.RootChainTypeEth, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), nonce)
+
+	nonce, _, err = cursor.GetLastSyncedNonce(hmTypes.RootChainTypeTron, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), nonce)
+
+	_, found, err = cursor.GetLastSyncedNonce(hmTypes.RootChainTypeEth, 2)
+	require.NoError(t, err)
+	require.False(t, found)
+}
@@ -0,0 +1,156 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	cliContext "github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/maticnetwork/heimdall/helper"
+	hmtypes "github.com/maticnetwork/heimdall/types"
+)
+
+// ValidatorSnapshotKey is the bridge storage key of the validator set snapshot
+const ValidatorSnapshotKey = "validator-set-snapshot"
+
+// ValidatorSnapshot is the proposer ordered current validator set of an epoch, as served by heimdall
+type ValidatorSnapshot struct {
+	Epoch      uint64              `json:"epoch"`
+	Validators []hmtypes.Validator `json:"validators"`
+	FetchedAt  int64               `json:"fetched_at"` // unix time
+}
+
+// ValidatorSnapshotCache keeps a validator set snapshot in node-local bridge storage, so task delays
+// are computed without querying heimdall per event. Refresh is meant to run on a schedule,
+// readers fall back to querying heimdall once the snapshot is older than maxAge.
+type ValidatorSnapshotCache struct {
+	storageClient *leveldb.DB
+	maxAge        time.Duration
+}
+
+// NewValidatorSnapshotCache creates a validator snapshot cache backed by the given bridge storage
+func NewValidatorSnapshotCache(storageClient *leveldb.DB, maxAge time.Duration) *ValidatorSnapshotCache {
+	return &ValidatorSnapshotCache{storageClient: storageClient, maxAge: maxAge}
+}
+
+// Get returns the stored snapshot, and whether one is stored and not older than maxAge
+func (c *ValidatorSnapshotCache) Get() (*ValidatorSnapshot, bool, error) {
+	snapshotBytes, err := c.storageClient.Get([]byte(ValidatorSnapshotKey), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	var snapshot ValidatorSnapshot
+	if err := json.Unmarshal(snapshotBytes, &snapshot); err != nil {
+		return nil, false, err
+	}
+
+	if c.maxAge > 0 && time.Since(time.Unix(snapshot.FetchedAt, 0)) > c.maxAge {
+		return &snapshot, false, nil
+	}
+
+	return &snapshot, true, nil
+}
+
+// Refresh stores a fresh snapshot fetched from heimdall. A snapshot of a previous epoch is dropped
+// before the validators are fetched, so it isn't used if fetching fails.
+func (c *ValidatorSnapshotCache) Refresh(cliCtx cliContext.CLIContext) (*ValidatorSnapshot, error) {
+	epoch, err := fetchCurrentEpoch(cliCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot, _, err := c.Get(); err == nil && snapshot != nil && snapshot.Epoch != epoch {
+		Logger().Info("Epoch changed, invalidating validator snapshot", "epoch", epoch, "snapshotEpoch", snapshot.Epoch)
+		if err := c.storageClient.Delete([]byte(ValidatorSnapshotKey), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	validators, err := fetchProposers(cliCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &ValidatorSnapshot{
+		Epoch:      epoch,
+		Validators: validators,
+		FetchedAt:  time.Now().Unix(),
+	}
+	snapshotBytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.storageClient.Put([]byte(ValidatorSnapshotKey), snapshotBytes, nil); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// CalculateTaskDelay is CalculateTaskDelay reading validators from the snapshot,
+// it queries heimdall if no fresh snapshot is stored
+func (c *ValidatorSnapshotCache) CalculateTaskDelay(cliCtx cliContext.CLIContext) (bool, time.Duration) {
+	return c.CalculateTaskDelayWithOffset(cliCtx, 0)
+}
+
+// CalculateTaskDelayWithOffset is CalculateTaskDelayWithOffset reading validators from the snapshot,
+// it queries heimdall if no fresh snapshot is stored
+func (c *ValidatorSnapshotCache) CalculateTaskDelayWithOffset(cliCtx cliContext.CLIContext, offset int) (bool, time.Duration) {
+	snapshot, ok, err := c.Get()
+	if err != nil || !ok {
+		return CalculateTaskDelayWithOffset(cliCtx, offset)
+	}
+
+	return taskDelayByPosition(snapshot.Validators, offset)
+}
+
+// taskDelayByPosition returns whether this node is one of the validators, and its delay by position
+func taskDelayByPosition(validators []hmtypes.Validator, offset int) (bool, time.Duration) {
+	for i, validator := range validators {
+		if bytes.Equal(validator.Signer.Bytes(), helper.GetAddress()) {
+			return true, time.Duration(i+offset) * TaskDelayBetweenEachVal
+		}
+	}
+
+	return false, 0
+}
+
+// fetchProposers returns the proposer ordered current validators
+func fetchProposers(cliCtx cliContext.CLIContext) ([]hmtypes.Validator, error) {
+	proposersURL := fmt.Sprintf(ProposersURL, ProposersURLSizeLimit)
+	proposersResponse, err := helper.FetchFromAPI(cliCtx, helper.GetHeimdallServerEndpoint(proposersURL))
+	if err != nil {
+		return nil, err
+	}
+
+	var proposers []hmtypes.Validator
+	if err := json.Unmarshal(proposersResponse.Result, &proposers); err != nil {
+		return nil, err
+	}
+
+	return proposers, nil
+}
+
+// fetchCurrentEpoch returns the current checkpoint epoch
+func fetchCurrentEpoch(cliCtx cliContext.CLIContext) (uint64, error) {
+	response, err := helper.FetchFromAPI(cliCtx, helper.GetHeimdallServerEndpoint(CurrentEpochURL))
+	if err != nil {
+		return 0, err
+	}
+
+	var epochObject struct {
+		Result uint64 `json:"result"`
+	}
+	if err := json.Unmarshal(response.Result, &epochObject); err != nil {
+		return 0, err
+	}
+
+	return epochObject.Result, nil
+}
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cliContext "github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+
+	"github.com/maticnetwork/heimdall/helper"
+	hmtypes "github.com/maticnetwork/heimdall/types"
+)
+
+func TestValidatorSnapshotCache(t *testing.T) {
+	// fallback queries log through the package logger
+	_ = Logger()
+
+	self := hmtypes.Validator{ID: 1, Signer: hmtypes.BytesToHeimdallAddress(helper.GetAddress())}
+	other := hmtypes.Validator{ID: 2, Signer: hmtypes.HexToHeimdallAddress("0x02")}
+
+	var epoch atomic.Uint64
+	var proposerQueries atomic.Int64
+	var proposersDown atomic.Bool
+	var proposers atomic.Value
+	proposers.Store([]hmtypes.Validator{other, self})
+	epoch.Store(5)
+
+	heimdallServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result interface{}
+		switch {
+		case r.URL.Path == CurrentEpochURL:
+			result = map[string]uint64{"result": epoch.Load()}
+		case strings.HasPrefix(r.URL.Path, "/staking/proposer/") && !proposersDown.Load():
+			proposerQueries.Add(1)
+			result = proposers.Load()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resultBytes, _ := json.Marshal(result)
+		_, _ = w.Write([]byte(`{"height":"0","result":` + string(resultBytes) + `}`))
+	}))
+	defer heimdallServer.Close()
+
+	config := helper.GetDefaultHeimdallConfig()
+	config.DeliveryServerURL = heimdallServer.URL
+	helper.SetTestConfig(config)
+
+	storageClient, err := leveldb.Open(storage.NewMemStorage(), nil)
+	require.NoError(t, err)
+	defer storageClient.Close()
+
+	cliCtx := cliContext.NewCLIContext().WithCodec(codec.New())
+	cache := NewValidatorSnapshotCache(storageClient, time.Minute)
+
+	// no snapshot yet, heimdall is queried
+	isCurrentValidator, delay := cache.CalculateTaskDelay(cliCtx)
+	require.True(t, isCurrentValidator)
+	require.Equal(t, TaskDelayBetweenEachVal, delay)
+	require.Equal(t, int64(1), proposerQueries.Load())
+
+	// refreshed snapshot is used without further queries
+	snapshot, err := cache.Refresh(cliCtx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), snapshot.Epoch)
+	require.Equal(t, int64(2), proposerQueries.Load())
+	for i := 0; i < 3; i++ {
+		isCurrentValidator, delay = cache.CalculateTaskDelayWithOffset(cliCtx, 1)
+		require.True(t, isCurrentValidator)
+		require.Equal(t, 2*TaskDelayBetweenEachVal, delay)
+	}
+	require.Equal(t, int64(2), proposerQueries.Load())
+
+	// next refresh picks up the new order
+	proposers.Store([]hmtypes.Validator{self, other})
+	epoch.Store(6)
+	snapshot, err = cache.Refresh(cliCtx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), snapshot.Epoch)
+	isCurrentValidator, delay = cache.CalculateTaskDelay(cliCtx)
+	require.True(t, isCurrentValidator)
+	require.Equal(t, time.Duration(0), delay)
+
+	// epoch change invalidates the snapshot even if validators can't be fetched
+	epoch.Store(7)
+	proposersDown.Store(true)
+	_, err = cache.Refresh(cliCtx)
+	require.Error(t, err)
+	_, ok, err := cache.Get()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// snapshot older than max age isn't used
+	proposersDown.Store(false)
+	_, err = cache.Refresh(cliCtx)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, ok, err = NewValidatorSnapshotCache(storageClient, time.Nanosecond).Get()
+	require.NoError(t, err)
+	require.False(t, ok)
+}
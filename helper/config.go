@@ -100,6 +100,8 @@ const (
 	DefaultBscMaxQueryBlocks  = 5
 	DefaultTronMaxQueryBlocks = 5
 
+	DefaultListenerHealthStaleness = 10 * time.Minute
+
 	DefaultBttcChainID string = "15001"
 
 	DefaultLogsType = "json"
@@ -165,6 +167,46 @@ type Configuration struct {
 	EthMaxQueryBlocks  int64 `mapstructure:"eth_max_query_blocks"`  // eth max number of blocks in one query logs
 	BscMaxQueryBlocks  int64 `mapstructure:"bsc_max_query_blocks"`  // bsc max number of blocks in one query logs
 	TronMaxQueryBlocks int64 `mapstructure:"tron_max_query_blocks"` // tron max number of blocks in one query logs
+
+	FilterLogsResultCap int `mapstructure:"filter_logs_result_cap"` // rootchain log queries returning this many logs are split in half, 0 disables
+
+	EthChainID uint64 `mapstructure:"eth_chain_id"` // expected eth chain id, checked by the listener on start, 0 skips the check
+	BscChainID uint64 `mapstructure:"bsc_chain_id"` // expected bsc chain id, checked by the listener on start, 0 skips the check
+
+	ListenerLogLevel string `mapstructure:"listener_log_level"` // per listener log level, eg. "rootchain:warn,tron:info"
+
+	EnableEventBatching bool `mapstructure:"enable_event_batching"` // batch state synced logs of a block into one task
+	UseFinalizedBlock   bool `mapstructure:"use_finalized_block"`   // process rootchain logs up to finalized block instead of confirmation depth
+	CheckReceiptStatus  bool `mapstructure:"check_receipt_status"`  // drop rootchain logs of failed transactions, costs a receipt query per transaction
+	VerifyLogInclusion  bool `mapstructure:"verify_log_inclusion"`  // prove rootchain logs against the block receipts root, costs a block receipts query per block
+
+	EventPayloadFormat string `mapstructure:"event_payload_format"` // listener task payload format of logs, "json" (default) or "amino"
+
+	ValidatorSnapshotInterval time.Duration `mapstructure:"validator_snapshot_interval"` // rootchain listener reads validators from a local snapshot refreshed at this interval, 0 queries heimdall per event
+
+	TaskRoutingKeys  string `mapstructure:"task_routing_keys"`  // per root chain task queue, eg. "tron:tron_tasks", other root chains use the shared queue
+	TaskWorkerQueues string `mapstructure:"task_worker_queues"` // queues consumed by the bridge worker, eg. "tron_tasks", empty consumes the shared and all routed queues
+
+	RootChainMinConfirmations uint64 `mapstructure:"rootchain_min_confirmations"` // raises rootchain tx confirmations above the governed value, never lowers it
+
+	EventTaskNames string `mapstructure:"event_task_names"` // rootchain event task name overrides, eg. "StateSynced:sendStateSyncedToHeimdall"
+
+	ListenerHealthStaleness time.Duration `mapstructure:"listener_health_staleness"` // rootchain listener is unhealthy if no header is processed within this window
+
+	CursorCheckpointBlocks   uint64        `mapstructure:"cursor_checkpoint_blocks"`   // write rootchain listener cursor to storage every n blocks
+	CursorCheckpointInterval time.Duration `mapstructure:"cursor_checkpoint_interval"` // write rootchain listener cursor to storage every interval
+
+	MaxQueuedTasks      int `mapstructure:"max_queued_tasks"`       // rootchain listener pauses when this many tasks are pending
+	QueuedTasksLowWater int `mapstructure:"queued_tasks_low_water"` // rootchain listener resumes below this many pending tasks
+
+	MaxStateSyncedDataSize int `mapstructure:"max_state_synced_data_size"` // state synced logs with larger data are dead-lettered instead of sent as tasks
+
+	TaskETAJitter time.Duration `mapstructure:"task_eta_jitter"` // listener task ETAs are spread by a per validator jitter within this window
+
+	MinTaskDelay time.Duration `mapstructure:"min_task_delay"` // listener task delays are raised to at least this, 0 disables
+	MaxTaskDelay time.Duration `mapstructure:"max_task_delay"` // listener task delays are capped at this, 0 disables
+
+	TronSkipConfirmedHeaders bool `mapstructure:"tron_skip_confirmed_headers"` // tron listener skips header blocks acked on heimdall before start
 }
 
 var conf Configuration
@@ -325,6 +367,8 @@ func GetDefaultHeimdallConfig() Configuration {
 		EthMaxQueryBlocks:  DefaultEthMaxQueryBlocks,
 		BscMaxQueryBlocks:  DefaultBscMaxQueryBlocks,
 		TronMaxQueryBlocks: DefaultTronMaxQueryBlocks,
+
+		ListenerHealthStaleness: DefaultListenerHealthStaleness,
 	}
 }
 
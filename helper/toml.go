@@ -65,6 +65,68 @@ eth_max_query_blocks = "{{ .EthMaxQueryBlocks }}"
 bsc_max_query_blocks = "{{ .BscMaxQueryBlocks }}"
 tron_max_query_blocks = "{{ .TronMaxQueryBlocks }}"
 
+#### rootchain log queries returning this many logs are split in half, 0 disables ####
+filter_logs_result_cap = "{{ .FilterLogsResultCap }}"
+
+#### expected rootchain chain ids, 0 skips the check ####
+eth_chain_id = "{{ .EthChainID }}"
+bsc_chain_id = "{{ .BscChainID }}"
+
+#### listener log levels, eg. "rootchain:warn,tron:info" ####
+listener_log_level = "{{ .ListenerLogLevel }}"
+
+#### batch state synced logs of a block into one task ####
+enable_event_batching = "{{ .EnableEventBatching }}"
+
+#### listener task payload format of logs, "json" or the smaller "amino" ####
+event_payload_format = "{{ .EventPayloadFormat }}"
+
+#### rootchain listener validator snapshot refresh interval, 0 queries heimdall per event ####
+validator_snapshot_interval = "{{ .ValidatorSnapshotInterval }}"
+
+#### per root chain task queues, eg. "tron:tron_tasks", and queues consumed by this worker, empty consumes all ####
+task_routing_keys = "{{ .TaskRoutingKeys }}"
+task_worker_queues = "{{ .TaskWorkerQueues }}"
+
+#### process rootchain logs up to finalized block, falls back to confirmations ####
+use_finalized_block = "{{ .UseFinalizedBlock }}"
+
+#### drop rootchain logs of failed transactions, costs a receipt query per transaction ####
+check_receipt_status = "{{ .CheckReceiptStatus }}"
+
+#### prove rootchain logs against the block receipts root, costs a block receipts query per block ####
+verify_log_inclusion = "{{ .VerifyLogInclusion }}"
+
+#### raise rootchain tx confirmations above the governed value, 0 disables ####
+rootchain_min_confirmations = "{{ .RootChainMinConfirmations }}"
+
+#### rootchain event task name overrides, eg. "StateSynced:sendStateSyncedToHeimdall" ####
+event_task_names = "{{ .EventTaskNames }}"
+
+#### rootchain listener is unhealthy if no header is processed within this window ####
+listener_health_staleness = "{{ .ListenerHealthStaleness }}"
+
+#### rootchain listener cursor checkpoint, 0 writes on every processed range ####
+cursor_checkpoint_blocks = "{{ .CursorCheckpointBlocks }}"
+cursor_checkpoint_interval = "{{ .CursorCheckpointInterval }}"
+
+#### rootchain listener task queue backpressure, 0 disables, low water defaults to half ####
+max_queued_tasks = "{{ .MaxQueuedTasks }}"
+queued_tasks_low_water = "{{ .QueuedTasksLowWater }}"
+
+#### state synced logs with larger data (bytes) are dead-lettered, 0 disables ####
+max_state_synced_data_size = "{{ .MaxStateSyncedDataSize }}"
+
+#### window of the per validator jitter added to listener task ETAs, 0 disables ####
+task_eta_jitter = "{{ .TaskETAJitter }}"
+
+#### bounds of listener task delays, 0 disables ####
+min_task_delay = "{{ .MinTaskDelay }}"
+max_task_delay = "{{ .MaxTaskDelay }}"
+
+#### tron listener skips header blocks acked on heimdall before start ####
+tron_skip_confirmed_headers = "{{ .TronSkipConfirmedHeaders }}"
+
 ##### Timeout Config #####
 no_ack_wait_time = "{{ .NoACKWaitTime }}"
 
@@ -222,36 +222,23 @@ func (c *ContractCaller) ApproveTokens(amount *big.Int, stakeManager common.Addr
 	return nil
 }
 
-// SendMainStakingSync sends staking sync to rootchain contract
+// SendTronCheckpoint sends checkpoint to tron rootchain contract
 func (c *ContractCaller) SendTronCheckpoint(signedData []byte, sigs [][3]*big.Int, rootChainAddress string) error {
-	data, err := c.RootChainABI.Pack("submitCheckpoint", signedData, sigs)
-	if err != nil {
-		return err
-	}
 	privateKey := GetPrivKey()
-	// trigger
-	trx, err := c.TronChainRPC.TriggerContract(privateKey.PubKey().Address().String(), rootChainAddress, data)
-	if err != nil {
-		return err
-	}
-	trx.RawData.FeeLimit = int64(GetConfig().TronchainFeeLimit)
-	rawData, _ := proto.Marshal(trx.GetRawData())
-	hash, err := Hash(rawData)
-	if err != nil {
-		return err
-	}
-
-	signature, err := ethCrypto.Sign(hash, privateKey[:])
+	txHash, err := c.TronChainRPC.SubmitCheckpoint(
+		context.Background(),
+		privateKey.PubKey().Address().String(),
+		rootChainAddress,
+		signedData,
+		sigs,
+		int64(GetConfig().TronchainFeeLimit),
+		privateKey[:],
+	)
 	if err != nil {
 		return err
 	}
 
-	trx.Signature = append(trx.GetSignature(), signature)
-
-	err = c.TronChainRPC.BroadcastTransaction(context.Background(), trx)
-	if err != nil {
-		return err
-	}
+	Logger.Info("Submitted new checkpoint to tron rootchain successfully", "txHash", txHash)
 	return nil
 }
 
@@ -95,6 +95,16 @@ func GetUpdatedValidators(
 	currentSet *hmTypes.ValidatorSet,
 	validators []*hmTypes.Validator,
 	ackCount uint64,
+) []*hmTypes.Validator {
+	return GetUpdatedValidatorsWithGrace(currentSet, validators, ackCount, 0)
+}
+
+// GetUpdatedValidatorsWithGrace updates validators in validator set, keeping validators grace epochs after EndEpoch
+func GetUpdatedValidatorsWithGrace(
+	currentSet *hmTypes.ValidatorSet,
+	validators []*hmTypes.Validator,
+	ackCount uint64,
+	grace uint64,
 ) []*hmTypes.Validator {
 	updates := make([]*hmTypes.Validator, 0)
 	for _, v := range validators {
@@ -103,11 +113,11 @@ func GetUpdatedValidators(
 
 		address := validator.Signer.Bytes()
 		_, val := currentSet.GetByAddress(address)
-		if val != nil && !validator.IsCurrentValidator(ackCount) {
+		if val != nil && !validator.IsCurrentValidatorWithGrace(ackCount, grace) {
 			// remove validator
 			validator.VotingPower = 0
 			updates = append(updates, validator)
-		} else if val == nil && validator.IsCurrentValidator(ackCount) {
+		} else if val == nil && validator.IsCurrentValidatorWithGrace(ackCount, grace) {
 			// add validator
 			updates = append(updates, validator)
 		} else if val != nil && validator.VotingPower != val.VotingPower {
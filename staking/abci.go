@@ -0,0 +1,39 @@
+package staking
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// BeginBlocker records the last signed height of validators who signed the previous block, rewriting
+// it once every LastSignedInterval blocks
+func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k Keeper) {
+	for _, voteInfo := range req.LastCommitInfo.GetVotes() {
+		if !voteInfo.SignedLastBlock {
+			continue
+		}
+
+		validator, err := k.GetValidatorInfo(ctx, voteInfo.Validator.Address)
+		if err != nil {
+			k.Logger(ctx).Debug("Signing validator not found", "address", voteInfo.Validator.Address, "error", err)
+			continue
+		}
+
+		k.UpdateLastSigned(ctx, validator.ID, ctx.BlockHeight()-1)
+	}
+}
+
+// EndBlocker prunes validators past their EndEpoch grace period when pruning is enabled,
+// and recomputes validator powers after a power divisor change
+func EndBlocker(ctx sdk.Context, k Keeper) {
+	if pruned := k.PruneExpiredValidators(ctx); len(pruned) > 0 {
+		k.Logger(ctx).Info("Pruned expired validators", "count", len(pruned))
+	}
+
+	updated, err := k.ApplyPowerDivisorChange(ctx)
+	if err != nil {
+		k.Logger(ctx).Error("Unable to recompute validator powers", "error", err)
+	} else if len(updated) > 0 {
+		k.Logger(ctx).Info("Recomputed validator powers", "divisor", k.GetPowerDivisor(ctx), "count", len(updated))
+	}
+}
@@ -0,0 +1,42 @@
+package staking
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	stakingTypes "github.com/maticnetwork/heimdall/staking/types"
+	hmTypes "github.com/maticnetwork/heimdall/types"
+)
+
+// validator set gauges, exposed to external tests
+var (
+	ValidatorSetSize       = validatorSetSize
+	ValidatorSetTotalPower = validatorSetTotalPower
+)
+
+// GetStakingQueueKey exposes getStakingQueueKey to external tests
+var GetStakingQueueKey = getStakingQueueKey
+
+// RemoveStakingRecordByTxHash exposes removeStakingRecordByTxHash to external tests
+func (k *Keeper) RemoveStakingRecordByTxHash(ctx sdk.Context, rootID byte, txHash hmTypes.HeimdallHash) bool {
+	return k.removeStakingRecordByTxHash(ctx, rootID, txHash)
+}
+
+// RemoveStakingRecordFromQueue exposes removeStakingRecordFromQueue to external tests
+func (k *Keeper) RemoveStakingRecordFromQueue(ctx sdk.Context, rootID byte, validatorID hmTypes.ValidatorID, nonce uint64) {
+	k.removeStakingRecordFromQueue(ctx, rootID, validatorID, nonce)
+}
+
+// EncodeStakingQueue exposes encodeStakingQueue to external tests
+func (k *Keeper) EncodeStakingQueue(records []stakingTypes.StakingRecord) ([]byte, error) {
+	return k.encodeStakingQueue(records)
+}
+
+// DecodeStakingQueue exposes decodeStakingQueue to external tests
+func (k *Keeper) DecodeStakingQueue(bz []byte) ([]stakingTypes.StakingRecord, error) {
+	return k.decodeStakingQueue(bz)
+}
+
+// GetValidatorSetUncached decodes the stored validator set, bypassing the keeper cache
+func (k *Keeper) GetValidatorSetUncached(ctx sdk.Context) hmTypes.ValidatorSet {
+	return k.decodeValidatorSet(ctx, ctx.KVStore(k.storeKey).Get(CurrentValidatorSetKey))
+}
@@ -17,6 +17,11 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data types.GenesisState) {
 	}
 
 	if len(vals) != 0 {
+		// guard total power before the set sums it
+		if err := types.ValidateTotalVotingPower(vals); err != nil {
+			panic(err)
+		}
+
 		resultValSet := hmTypes.NewValidatorSet(vals)
 
 		// add validators in store
@@ -38,20 +43,45 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data types.GenesisState) {
 		}
 	}
 
+	// archived after current validators, an ID already mapped to a current signer keeps it
+	for _, validator := range data.ArchivedValidators {
+		if err := keeper.AddArchivedValidator(ctx, *validator); err != nil {
+			panic(err)
+		}
+	}
+
 	for _, sequence := range data.StakingSequences {
 		keeper.SetStakingSequence(ctx, sequence)
 	}
 
 	keeper.SetParams(ctx, data.Params)
+
+	// record the genesis divisor, genesis powers are kept as given
+	if _, err := keeper.ApplyPowerDivisorChange(ctx); err != nil {
+		panic(err)
+	}
+
+	// carry freshness of the exported set, a new chain recomputes it on the first end block
+	if data.ValidatorSetAckCount != nil {
+		keeper.markValidatorSetFresh(ctx, *data.ValidatorSetAckCount)
+	}
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper.
 func ExportGenesis(ctx sdk.Context, keeper Keeper) types.GenesisState {
 	// return new genesis state
-	return types.NewGenesisState(
+	genesisState := types.NewGenesisState(
 		keeper.GetParams(ctx),
 		keeper.GetAllValidators(ctx),
 		keeper.GetValidatorSet(ctx),
 		keeper.GetStakingSequences(ctx),
 	)
+
+	genesisState.ArchivedValidators = keeper.GetArchivedValidators(ctx)
+
+	if ackCount := keeper.moduleCommunicator.GetACKCount(ctx); keeper.IsValidatorSetFresh(ctx, ackCount) {
+		genesisState.ValidatorSetAckCount = &ackCount
+	}
+
+	return genesisState
 }
@@ -1,6 +1,7 @@
 package staking_test
 
 import (
+	"math"
 	"math/rand"
 	"strconv"
 	"testing"
@@ -75,3 +76,136 @@ func (suite *GenesisTestSuite) TestInitExportGenesis() {
 	require.NotNil(t, actualParams)
 	require.LessOrEqual(t, 5, len(actualParams.Validators))
 }
+
+// TestInitGenesisTotalPowerOverflow rejects a set whose total power would overflow
+func (suite *GenesisTestSuite) TestInitGenesisTotalPowerOverflow() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	r1 := rand.New(rand.NewSource(time.Now().UnixNano()))
+	accounts := simulation.RandomAccounts(r1, 3)
+
+	validators := make([]*hmTypes.Validator, len(accounts))
+	for i := range validators {
+		validators[i] = hmTypes.NewValidator(
+			hmTypes.NewValidatorID(uint64(i+1)),
+			0,
+			0,
+			1,
+			math.MaxInt64/2, // power, sum overflows int64
+			hmTypes.NewPubKey(accounts[i].PubKey.Bytes()),
+			accounts[i].Address,
+		)
+	}
+
+	require.Error(t, types.ValidateTotalVotingPower(validators))
+
+	genesisState := types.NewGenesisState(types.DefaultParams(), validators, hmTypes.ValidatorSet{}, nil)
+	require.Error(t, types.ValidateGenesis(genesisState))
+	require.Panics(t, func() { staking.InitGenesis(ctx, app.StakingKeeper, genesisState) })
+	require.Empty(t, app.StakingKeeper.GetAllValidators(ctx))
+
+	// within bound, with headroom left for accum arithmetic
+	validators[0].VotingPower = hmTypes.MaxTotalVotingPower - 2
+	validators[1].VotingPower = 1
+	validators[2].VotingPower = 1
+	require.NoError(t, types.ValidateTotalVotingPower(validators))
+
+	validators[2].VotingPower = 2
+	require.Error(t, types.ValidateTotalVotingPower(validators))
+}
+
+// TestExportGenesisValidatorSetFreshness carries the freshness of the current validator set through export and import
+func (suite *GenesisTestSuite) TestExportGenesisValidatorSetFreshness() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	r1 := rand.New(rand.NewSource(time.Now().UnixNano()))
+	accounts := simulation.RandomAccounts(r1, 4)
+
+	validators := make([]*hmTypes.Validator, len(accounts)-1)
+	for i := range validators {
+		validators[i] = hmTypes.NewValidator(
+			hmTypes.NewValidatorID(uint64(i+1)),
+			0,
+			0,
+			1,
+			10,
+			hmTypes.NewPubKey(accounts[i].PubKey.Bytes()),
+			accounts[i].Address,
+		)
+	}
+
+	// a new chain recomputes the set on the first end block
+	staking.InitGenesis(ctx, app.StakingKeeper, types.NewGenesisState(types.DefaultParams(), validators, hmTypes.ValidatorSet{}, nil))
+	require.Nil(t, staking.ExportGenesis(ctx, app.StakingKeeper).ValidatorSetAckCount)
+
+	app.CheckpointKeeper.UpdateACKCountWithValue(ctx, 2, hmTypes.RootChainTypeStake)
+	_, err := app.StakingKeeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+
+	exported := staking.ExportGenesis(ctx, app.StakingKeeper)
+	require.NotNil(t, exported.ValidatorSetAckCount)
+	require.Equal(t, uint64(2), *exported.ValidatorSetAckCount)
+
+	// imported set is fresh once checkpoint genesis restores the ack count
+	newApp, newCtx, _ := createTestApp(true)
+	staking.InitGenesis(newCtx, newApp.StakingKeeper, exported)
+	require.True(t, newApp.StakingKeeper.IsValidatorSetFresh(newCtx, 2))
+	require.False(t, newApp.StakingKeeper.IsValidatorSetFresh(newCtx, 0))
+
+	// pending updates are not exported as fresh
+	extra := hmTypes.NewValidator(hmTypes.NewValidatorID(10), 0, 0, 1, 10, hmTypes.NewPubKey(accounts[3].PubKey.Bytes()), accounts[3].Address)
+	require.NoError(t, app.StakingKeeper.AddValidator(ctx, *extra))
+	require.Nil(t, staking.ExportGenesis(ctx, app.StakingKeeper).ValidatorSetAckCount)
+}
+
+// TestExportGenesisArchivedValidators carries pruned validators and their ID map entries through export and import
+func (suite *GenesisTestSuite) TestExportGenesisArchivedValidators() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	r1 := rand.New(rand.NewSource(time.Now().UnixNano()))
+	accounts := simulation.RandomAccounts(r1, 3)
+
+	// signers match pubkeys, so a join is only rejected as existing
+	validators := make([]*hmTypes.Validator, len(accounts))
+	for i := range validators {
+		pubKey := hmTypes.NewPubKey(accounts[i].PubKey.Bytes())
+		validators[i] = hmTypes.NewValidator(
+			hmTypes.NewValidatorID(uint64(i+1)),
+			0,
+			0,
+			1,
+			10,
+			pubKey,
+			hmTypes.BytesToHeimdallAddress(pubKey.Address().Bytes()),
+		)
+	}
+
+	staking.InitGenesis(ctx, app.StakingKeeper, types.NewGenesisState(types.DefaultParams(), validators[:2], hmTypes.ValidatorSet{}, nil))
+
+	// deactivated validator outside the current set is pruned
+	validators[2].EndEpoch = 1
+	require.NoError(t, app.StakingKeeper.AddValidator(ctx, *validators[2]))
+
+	params := app.StakingKeeper.GetParams(ctx)
+	params.EnableValidatorPruning = true
+	params.ValidatorPruneGracePeriod = 5
+	app.StakingKeeper.SetParams(ctx, params)
+	app.CheckpointKeeper.UpdateACKCountWithValue(ctx, 10, hmTypes.RootChainTypeStake)
+	require.Len(t, app.StakingKeeper.PruneExpiredValidators(ctx), 1)
+
+	exported := staking.ExportGenesis(ctx, app.StakingKeeper)
+	require.Len(t, exported.Validators, 2)
+	require.Len(t, exported.ArchivedValidators, 1)
+	require.Equal(t, validators[2].ID, exported.ArchivedValidators[0].ID)
+	require.NoError(t, types.ValidateGenesis(exported))
+
+	// imported archive keeps lookups by ID and rejects joining again
+	newApp, newCtx, _ := createTestApp(true)
+	staking.InitGenesis(newCtx, newApp.StakingKeeper, exported)
+
+	signer := validators[2].Signer.Bytes()
+	require.False(t, newApp.StakingKeeper.HasValidator(newCtx, signer))
+	require.True(t, newApp.StakingKeeper.IsArchivedValidator(newCtx, signer))
+	byID, ok := newApp.StakingKeeper.GetValidatorFromValID(newCtx, validators[2].ID)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), byID.EndEpoch)
+	require.ErrorIs(t, newApp.StakingKeeper.ValidateOnboarding(newCtx, *validators[2]), staking.ErrValidatorExists)
+	require.Equal(t, exported.ArchivedValidators, staking.ExportGenesis(newCtx, newApp.StakingKeeper).ArchivedValidators)
+}
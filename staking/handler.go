@@ -2,6 +2,7 @@ package staking
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -56,20 +57,18 @@ func HandleMsgValidatorJoin(ctx sdk.Context, msg types.MsgValidatorJoin, k Keepe
 	pubkey := msg.SignerPubKey
 	signer := pubkey.Address()
 
-	// Check if validator has been validator before
-	if _, ok := k.GetSignerFromValidatorID(ctx, msg.ID); ok {
-		k.Logger(ctx).Error("Validator has been validator before, cannot join with same ID", "validatorId", msg.ID)
-		return hmCommon.ErrValidatorAlreadyJoined(k.Codespace()).Result()
-	}
-
-	// get validator by signer
-	checkVal, err := k.GetValidatorInfo(ctx, signer.Bytes())
-	if err == nil || bytes.Equal(checkVal.Signer.Bytes(), signer.Bytes()) {
-		return hmCommon.ErrValidatorAlreadyJoined(k.Codespace()).Result()
+	// Check validator can be onboarded, i.e. it has not been validator before
+	candidate := hmTypes.Validator{ID: msg.ID, PubKey: pubkey, Signer: hmTypes.BytesToHeimdallAddress(signer.Bytes())}
+	if err := k.ValidateOnboarding(ctx, candidate); err != nil {
+		k.Logger(ctx).Error("Validator can't join", "validatorId", msg.ID, "error", err)
+		if errors.Is(err, ErrValidatorExists) {
+			return hmCommon.ErrValidatorAlreadyJoined(k.Codespace()).Result()
+		}
+		return hmCommon.ErrInvalidMsg(k.Codespace(), err.Error()).Result()
 	}
 
 	// validate voting power
-	_, err = helper.GetPowerFromAmount(msg.Amount.BigInt())
+	_, err := helper.GetPowerFromAmount(msg.Amount.BigInt())
 	if err != nil {
 		return hmCommon.ErrInvalidMsg(k.Codespace(), fmt.Sprintf("Invalid amount %v for validator %v", msg.Amount, msg.ID)).Result()
 	}
@@ -0,0 +1,63 @@
+package staking
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/maticnetwork/heimdall/staking/types"
+)
+
+// RegisterInvariants registers all staking invariants
+// The app has no crisis module, the simulations run them through app.CheckInvariants.
+func RegisterInvariants(ir sdk.InvariantRegistry, keeper Keeper) {
+	ir.RegisterRoute(types.ModuleName, "proposer-priority-bounds", ProposerPriorityBoundsInvariant(keeper))
+	ir.RegisterRoute(types.ModuleName, "power-concentration", PowerConcentrationInvariant(keeper))
+	ir.RegisterRoute(types.ModuleName, "signer-uniqueness", SignerUniquenessInvariant(keeper))
+}
+
+// ProposerPriorityBoundsInvariant checks that proposer priorities of the current
+// validator set stay within bounds relative to total voting power
+func ProposerPriorityBoundsInvariant(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		err := keeper.CheckProposerPriorityBounds(ctx)
+		broken := err != nil
+
+		msg := "\tall proposer priorities within bounds\n"
+		if broken {
+			msg = "\t" + err.Error() + "\n"
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "proposer-priority-bounds", msg), broken
+	}
+}
+
+// PowerConcentrationInvariant checks that no validator of the current validator
+// set holds more than the configured share of total voting power
+func PowerConcentrationInvariant(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		err := keeper.CheckPowerConcentration(ctx)
+		broken := err != nil
+
+		msg := "\tno validator exceeds the power share cap\n"
+		if broken {
+			msg = "\t" + err.Error() + "\n"
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "power-concentration", msg), broken
+	}
+}
+
+// SignerUniquenessInvariant checks that every signer address of the validator ID map
+// is referenced by a single validator ID
+func SignerUniquenessInvariant(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		err := keeper.CheckSignerUniqueness(ctx)
+		broken := err != nil
+
+		msg := "\tall signers mapped to a single validator ID\n"
+		if broken {
+			msg = "\t" + err.Error() + "\n"
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "signer-uniqueness", msg), broken
+	}
+}
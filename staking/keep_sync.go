@@ -5,6 +5,11 @@ package staking
 //
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
 	hmTypes "github.com/maticnetwork/heimdall/types"
 
 	stakingTypes "github.com/maticnetwork/heimdall/staking/types"
@@ -15,26 +20,98 @@ import (
 //
 // staking queue
 //
+// Queue methods read, modify and write back the whole queue value of a root without locking.
+// Handlers and ABCI hooks of the state machine run one at a time on the block's store,
+// which orders the writes, so concurrent mutations of the same queue can't happen.
+//
 
 func getStakingQueueKey(rootID byte) []byte {
 	return append(stakingSendingQueueKey, rootID)
 }
 
+// stakingQueueVersion prefixes versioned staking queue values.
+// Legacy values are bare amino slices, which always start with a field tag (0x0a), never with this byte.
+const stakingQueueVersion byte = 0x01
+
+// encodeStakingQueue marshals staking records with the current queue version
+func (k *Keeper) encodeStakingQueue(records []stakingTypes.StakingRecord) ([]byte, error) {
+	out, err := k.cdc.MarshalBinaryBare(records)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{stakingQueueVersion}, out...), nil
+}
+
+// decodeStakingQueue unmarshals staking records from either versioned or legacy unversioned queue values
+func (k *Keeper) decodeStakingQueue(bz []byte) ([]stakingTypes.StakingRecord, error) {
+	var records []stakingTypes.StakingRecord
+	if len(bz) > 0 && bz[0] == stakingQueueVersion {
+		bz = bz[1:]
+	}
+
+	if err := k.cdc.UnmarshalBinaryBare(bz, &records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// stakingQueueElementTag is the amino field key of a slice element, field 1 with length-prefixed wire type
+const stakingQueueElementTag byte = 0x0a
+
+// decodeStakingQueueHead unmarshals only the first record of versioned or legacy queue values.
+// Amino encodes every slice element as the element tag and a uvarint length followed by the record,
+// so the head is read without decoding the rest.
+func (k *Keeper) decodeStakingQueueHead(bz []byte) (*stakingTypes.StakingRecord, error) {
+	if len(bz) > 0 && bz[0] == stakingQueueVersion {
+		bz = bz[1:]
+	}
+	if len(bz) == 0 {
+		return nil, nil
+	}
+
+	if bz[0] != stakingQueueElementTag {
+		return nil, fmt.Errorf("invalid staking queue element tag %#x", bz[0])
+	}
+	length, n := binary.Uvarint(bz[1:])
+	if n <= 0 {
+		return nil, errors.New("invalid staking queue element length")
+	}
+	start := 1 + n
+	if length > uint64(len(bz)-start) {
+		return nil, fmt.Errorf("staking queue element length %d exceeds value", length)
+	}
+
+	var record stakingTypes.StakingRecord
+	if err := k.cdc.UnmarshalBinaryBare(bz[start:start+int(length)], &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
 // AddStakingRecordToQueue adds staking record to root cueue
 func (k *Keeper) AddStakingRecordToQueue(ctx sdk.Context, rootID byte, stakingRecord stakingTypes.StakingRecord) {
+	if stakingRecord.IsPowerUpdate() && stakingRecord.Power < 0 {
+		k.Logger(ctx).Error("Invalid power in staking queue record", "record", stakingRecord.String())
+		return
+	}
+
 	key := getStakingQueueKey(rootID)
 	store := ctx.KVStore(k.storeKey)
 
 	var records []stakingTypes.StakingRecord
 	if store.Has(key) {
-		err := k.cdc.UnmarshalBinaryBare(store.Get(key), &records)
+		var err error
+		records, err = k.decodeStakingQueue(store.Get(key))
 		if err != nil {
 			k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
 			return
 		}
 	}
 	records = append(records, stakingRecord)
-	out, err := k.cdc.MarshalBinaryBare(records)
+	out, err := k.encodeStakingQueue(records)
 	if err != nil {
 		k.Logger(ctx).Error("Error marshalling staking queue record", "error", err)
 		return
@@ -44,29 +121,80 @@ func (k *Keeper) AddStakingRecordToQueue(ctx sdk.Context, rootID byte, stakingRe
 
 // GetNextStakingRecordFromQueue
 func (k *Keeper) GetNextStakingRecordFromQueue(ctx sdk.Context, rootID byte) (*stakingTypes.StakingRecord, error) {
+	return k.GetNextStakingRecordFromQueueWithOrder(ctx, rootID, stakingTypes.StakingQueueOrderInsertion, 0)
+}
+
+// GetNextStakingRecordFromQueueWithOrder returns the next staking record using the given ordering.
+// Lowest-nonce order picks among the records of validatorID and returns nil if it has none queued,
+// insertion order ignores validatorID.
+func (k *Keeper) GetNextStakingRecordFromQueueWithOrder(ctx sdk.Context, rootID byte, order stakingTypes.StakingQueueOrder, validatorID hmTypes.ValidatorID) (*stakingTypes.StakingRecord, error) {
 	key := getStakingQueueKey(rootID)
 	store := ctx.KVStore(k.storeKey)
 
-	var records []stakingTypes.StakingRecord
+	// insertion order only needs the head record
+	if order == stakingTypes.StakingQueueOrderInsertion {
+		bz := store.Get(key)
+		if bz == nil {
+			return nil, nil
+		}
+		record, err := k.decodeStakingQueueHead(bz)
+		if err != nil {
+			k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
+			return nil, err
+		}
+		return record, nil
+	}
+
 	if store.Has(key) {
-		err := k.cdc.UnmarshalBinaryBare(store.Get(key), &records)
+		records, err := k.decodeStakingQueue(store.Get(key))
 		if err != nil {
 			k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
 			return nil, err
 		}
-		return &records[0], nil
+
+		// pick lowest nonce among records of the given validator
+		var next *stakingTypes.StakingRecord
+		for i := range records {
+			if records[i].ValidatorID != validatorID {
+				continue
+			}
+			if next == nil || records[i].Nonce < next.Nonce {
+				next = &records[i]
+			}
+		}
+		return next, nil
 	}
 	return nil, nil
 }
 
+// GetStakingRecordAfter returns the queued record of validatorID with the lowest nonce above nonce, or nil if none.
+// The given record needn't be queued anymore, so iteration can continue after it was drained.
+func (k *Keeper) GetStakingRecordAfter(ctx sdk.Context, rootID byte, validatorID hmTypes.ValidatorID, nonce uint64) (*stakingTypes.StakingRecord, error) {
+	records, err := k.GetStakingQueue(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	var next *stakingTypes.StakingRecord
+	for i := range records {
+		if records[i].ValidatorID != validatorID || records[i].Nonce <= nonce {
+			continue
+		}
+		if next == nil || records[i].Nonce < next.Nonce {
+			next = &records[i]
+		}
+	}
+
+	return next, nil
+}
+
 // GetStakingQueue
 func (k *Keeper) GetStakingQueue(ctx sdk.Context, rootID byte) ([]stakingTypes.StakingRecord, error) {
 	key := getStakingQueueKey(rootID)
 	store := ctx.KVStore(k.storeKey)
 
-	var records []stakingTypes.StakingRecord
 	if store.Has(key) {
-		err := k.cdc.UnmarshalBinaryBare(store.Get(key), &records)
+		records, err := k.decodeStakingQueue(store.Get(key))
 		if err != nil {
 			k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
 			return nil, err
@@ -76,16 +204,156 @@ func (k *Keeper) GetStakingQueue(ctx sdk.Context, rootID byte) ([]stakingTypes.S
 	return nil, nil
 }
 
+// DrainStakingQueue hands up to batchSize head records of the root queue, in order, to process.
+// The batch is removed with a single store write only when process returns nil, so a failed batch stays queued.
+// process must not modify the queue of the same root, it is overwritten with the remaining records.
+func (k *Keeper) DrainStakingQueue(ctx sdk.Context, rootID byte, batchSize int, process func(records []stakingTypes.StakingRecord) error) ([]stakingTypes.StakingRecord, error) {
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("invalid staking queue batch size %d", batchSize)
+	}
+
+	key := getStakingQueueKey(rootID)
+	store := ctx.KVStore(k.storeKey)
+
+	if !store.Has(key) {
+		return nil, nil
+	}
+	records, err := k.decodeStakingQueue(store.Get(key))
+	if err != nil {
+		k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	if batchSize > len(records) {
+		batchSize = len(records)
+	}
+	batch := records[:batchSize]
+
+	if err := process(batch); err != nil {
+		return nil, err
+	}
+
+	if batchSize == len(records) {
+		store.Delete(key)
+		return batch, nil
+	}
+	out, err := k.encodeStakingQueue(records[batchSize:])
+	if err != nil {
+		k.Logger(ctx).Error("Error marshalling staking queue record", "error", err)
+		return nil, err
+	}
+	store.Set(key, out)
+	return batch, nil
+}
+
+// ExportStakingQueues returns the ordered staking queue of every root chain, keyed by root ID
+func (k *Keeper) ExportStakingQueues(ctx sdk.Context) (map[byte][]stakingTypes.StakingRecord, error) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, stakingSendingQueueKey)
+	defer iterator.Close()
+
+	queues := make(map[byte][]stakingTypes.StakingRecord)
+	for ; iterator.Valid(); iterator.Next() {
+		key := iterator.Key()
+		if len(key) != len(stakingSendingQueueKey)+1 {
+			continue
+		}
+
+		rootID := key[len(stakingSendingQueueKey)]
+		records, err := k.decodeStakingQueue(iterator.Value())
+		if err != nil {
+			k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
+			return nil, err
+		}
+		if records == nil {
+			records = []stakingTypes.StakingRecord{}
+		}
+		queues[rootID] = records
+	}
+	return queues, nil
+}
+
+// GetAllQueuedStakingRecords returns the queued staking records of every root chain grouped by root ID,
+// along with the total number of queued records across roots
+func (k *Keeper) GetAllQueuedStakingRecords(ctx sdk.Context) (queues map[byte][]stakingTypes.StakingRecord, total int, err error) {
+	queues, err = k.ExportStakingQueues(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, records := range queues {
+		total += len(records)
+	}
+
+	return queues, total, nil
+}
+
+// CompactStakingQueue rewrites the root queue in the current encoding, keeping order but dropping
+// duplicate records (same validator and nonce) and invalid power updates. An empty queue value is removed.
+// It returns the number of dropped records, the queue is only written if its stored value changes.
+func (k *Keeper) CompactStakingQueue(ctx sdk.Context, rootID byte) (int, error) {
+	key := getStakingQueueKey(rootID)
+	store := ctx.KVStore(k.storeKey)
+
+	if !store.Has(key) {
+		return 0, nil
+	}
+	bz := store.Get(key)
+	records, err := k.decodeStakingQueue(bz)
+	if err != nil {
+		k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
+		return 0, err
+	}
+
+	type recordID struct {
+		validatorID hmTypes.ValidatorID
+		nonce       uint64
+	}
+	seen := make(map[recordID]bool, len(records))
+	compacted := make([]stakingTypes.StakingRecord, 0, len(records))
+	for _, record := range records {
+		id := recordID{record.ValidatorID, record.Nonce}
+		if seen[id] || (record.IsPowerUpdate() && record.Power < 0) {
+			continue
+		}
+		seen[id] = true
+		compacted = append(compacted, record)
+	}
+	dropped := len(records) - len(compacted)
+
+	if len(compacted) == 0 {
+		store.Delete(key)
+		return dropped, nil
+	}
+
+	out, err := k.encodeStakingQueue(compacted)
+	if err != nil {
+		k.Logger(ctx).Error("Error marshalling staking queue record", "error", err)
+		return 0, err
+	}
+	if !bytes.Equal(out, bz) {
+		store.Set(key, out)
+	}
+
+	if dropped > 0 {
+		k.Logger(ctx).Info("Compacted staking queue", "root", rootID, "dropped", dropped, "remaining", len(compacted))
+	}
+
+	return dropped, nil
+}
+
 // removeStakingRecordFromQueue
 func (k *Keeper) removeStakingRecordFromQueue(ctx sdk.Context, rootID byte, validatorID hmTypes.ValidatorID, nonce uint64) {
 	key := getStakingQueueKey(rootID)
 	store := ctx.KVStore(k.storeKey)
 
-	var records []stakingTypes.StakingRecord
 	if !store.Has(key) {
 		return
 	}
-	err := k.cdc.UnmarshalBinaryBare(store.Get(key), &records)
+	records, err := k.decodeStakingQueue(store.Get(key))
 	if err != nil {
 		k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
 		return
@@ -98,7 +366,7 @@ func (k *Keeper) removeStakingRecordFromQueue(ctx sdk.Context, rootID byte, vali
 				return
 			}
 			results := records[index+1:]
-			out, err := k.cdc.MarshalBinaryBare(results)
+			out, err := k.encodeStakingQueue(results)
 			if err != nil {
 				k.Logger(ctx).Error("Error marshalling staking queue record", "error", err)
 				return
@@ -109,16 +377,49 @@ func (k *Keeper) removeStakingRecordFromQueue(ctx sdk.Context, rootID byte, vali
 	}
 }
 
+// removeStakingRecordByTxHash removes the staking record matching txHash from the queue.
+// It returns whether a record was removed.
+func (k *Keeper) removeStakingRecordByTxHash(ctx sdk.Context, rootID byte, txHash hmTypes.HeimdallHash) bool {
+	key := getStakingQueueKey(rootID)
+	store := ctx.KVStore(k.storeKey)
+
+	if !store.Has(key) {
+		return false
+	}
+	records, err := k.decodeStakingQueue(store.Get(key))
+	if err != nil {
+		k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
+		return false
+	}
+
+	for index, record := range records {
+		if record.TxHash.Equals(txHash) {
+			if len(records) == 1 {
+				store.Delete(key)
+				return true
+			}
+			results := append(records[:index:index], records[index+1:]...)
+			out, err := k.encodeStakingQueue(results)
+			if err != nil {
+				k.Logger(ctx).Error("Error marshalling staking queue record", "error", err)
+				return false
+			}
+			store.Set(key, out)
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateStakingRecordTimestamp update staking record timestamp
 func (k *Keeper) UpdateStakingRecordTimestamp(ctx sdk.Context, rootID byte, validatorID hmTypes.ValidatorID, nonce uint64, timestamp uint64) {
 	key := getStakingQueueKey(rootID)
 	store := ctx.KVStore(k.storeKey)
 
-	var records []stakingTypes.StakingRecord
 	if !store.Has(key) {
 		return
 	}
-	err := k.cdc.UnmarshalBinaryBare(store.Get(key), &records)
+	records, err := k.decodeStakingQueue(store.Get(key))
 	if err != nil {
 		k.Logger(ctx).Error("Error unmarshalling staking queue record", "root", rootID, "error", err)
 		return
@@ -127,7 +428,7 @@ func (k *Keeper) UpdateStakingRecordTimestamp(ctx sdk.Context, rootID byte, vali
 	for index, record := range records {
 		if record.ValidatorID == validatorID && record.Nonce == nonce {
 			records[index].TimeStamp = timestamp
-			out, err := k.cdc.MarshalBinaryBare(records)
+			out, err := k.encodeStakingQueue(records)
 			if err != nil {
 				k.Logger(ctx).Error("Error marshalling staking queue record", "error", err)
 				return
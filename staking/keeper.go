@@ -1,12 +1,21 @@
 package staking
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/common"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto/secp256k1"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/maticnetwork/heimdall/chainmanager"
@@ -23,6 +32,13 @@ var (
 	ValidatorMapKey        = []byte{0x22} // prefix for each key for validator map
 	CurrentValidatorSetKey = []byte{0x23} // Key to store current validator set
 	StakingSequenceKey     = []byte{0x24} // prefix for each key for staking sequence map
+	ArchivedValidatorsKey  = []byte{0x25} // prefix for each key to a pruned validator
+	ValidatorSetAckKey     = []byte{0x26} // Key to store ack count and membership params at which current validator set is in sync with validators
+
+	HistoricalValidatorSetKey = []byte{0x27} // prefix for each key to a validator set by height
+	LastSignedKey             = []byte{0x28} // prefix for each key to a validator's last signed height
+	ValidatorDescriptionKey   = []byte{0x29} // prefix for each key to a validator's description
+	AppliedPowerDivisorKey    = []byte{0x2a} // Key to store power divisor validator powers were last derived with
 
 	stakingSendingQueueKey = []byte{0x31} // prefix key for when storing staking sending queue
 
@@ -32,6 +48,25 @@ var (
 	//LastNoACKKey        = []byte{0x14} // key to store last no-ack
 )
 
+var (
+	// ErrValidatorReactivation is returned when clearing the end epoch of a deactivated validator
+	ErrValidatorReactivation = errors.New("deactivated validator can't be re-activated")
+	// ErrValidatorNotFound is returned when no validator is stored for the signer address
+	ErrValidatorNotFound = errors.New("Validator not found")
+	// ErrValidatorDecode is returned when a stored validator can't be unmarshalled
+	ErrValidatorDecode = errors.New("Validator decode failed")
+	// ErrPubKeyInUse is returned when rotating to a pubkey whose consensus address belongs to another validator
+	ErrPubKeyInUse = errors.New("Validator pubkey already in use")
+	// ErrValidatorExists is returned when onboarding a validator whose ID or signer is already known
+	ErrValidatorExists = errors.New("Validator already exists")
+	// ErrValidatorSigningInfo is returned when the signing info of an onboarded validator can't be saved
+	ErrValidatorSigningInfo = errors.New("Validator signing info save failed")
+)
+
+// LastSignedInterval is the resolution of last signed heights in blocks, a recorded height is only
+// rewritten once it's that old, so signing doesn't write to store every block
+const LastSignedInterval int64 = 100
+
 // ModuleCommunicator manages different module interaction
 type ModuleCommunicator interface {
 	GetACKCount(ctx sdk.Context) uint64
@@ -54,6 +89,8 @@ type Keeper struct {
 	chainKeeper chainmanager.Keeper
 	// module communicator
 	moduleCommunicator ModuleCommunicator
+	// decoded current validator set, shared by keeper copies
+	validatorSetCache *validatorSetCache
 }
 
 // NewKeeper create new keeper
@@ -72,6 +109,7 @@ func NewKeeper(
 		codespace:          codespace,
 		chainKeeper:        chainKeeper,
 		moduleCommunicator: moduleCommunicator,
+		validatorSetCache:  &validatorSetCache{},
 	}
 	return keeper
 }
@@ -96,18 +134,50 @@ func GetValidatorMapKey(address []byte) []byte {
 	return append(ValidatorMapKey, address...)
 }
 
+// GetArchivedValidatorKey drafts the archived validator key for addresses
+func GetArchivedValidatorKey(address []byte) []byte {
+	return append(ArchivedValidatorsKey, address...)
+}
+
+// GetHistoricalValidatorSetKey returns key for validator set written at height
+func GetHistoricalValidatorSetKey(height int64) []byte {
+	return append(HistoricalValidatorSetKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// GetLastSignedKey returns key for last signed height of validator
+func GetLastSignedKey(valID hmTypes.ValidatorID) []byte {
+	return append(LastSignedKey, valID.Bytes()...)
+}
+
+// GetValidatorDescriptionKey returns key for description of validator
+func GetValidatorDescriptionKey(valID hmTypes.ValidatorID) []byte {
+	return append(ValidatorDescriptionKey, valID.Bytes()...)
+}
+
 // GetStakingSequenceKey returns staking sequence key
 func GetStakingSequenceKey(sequence string) []byte {
 	return append(StakingSequenceKey, []byte(sequence)...)
 }
 
-// AddValidator adds validator indexed with address
+// AddValidator adds validator indexed with address.
+// An end epoch once set is never cleared; no handler in this fork re-stakes a deactivated validator.
 func (k *Keeper) AddValidator(ctx sdk.Context, validator hmTypes.Validator) error {
 	// TODO uncomment
 	//if ok:=validator.ValidateBasic(); !ok{
 	//	// return error
 	//}
 
+	if validator.CommissionRate > hmTypes.MaxCommissionRate {
+		return fmt.Errorf("invalid commission rate %v of validator %v, max %v", validator.CommissionRate, validator.ID, hmTypes.MaxCommissionRate)
+	}
+
+	// deactivated validator can't be re-activated
+	if validator.EndEpoch == 0 {
+		if existing, err := k.GetValidatorInfo(ctx, validator.Signer.Bytes()); err == nil && existing.EndEpoch != 0 {
+			return fmt.Errorf("%w: validator %v, end epoch %v", ErrValidatorReactivation, validator.ID, existing.EndEpoch)
+		}
+	}
+
 	store := ctx.KVStore(k.storeKey)
 
 	bz, err := hmTypes.MarshallValidator(k.cdc, validator)
@@ -115,12 +185,17 @@ func (k *Keeper) AddValidator(ctx sdk.Context, validator hmTypes.Validator) erro
 		return err
 	}
 
+	// add validator to validator ID => SignerAddress map, rejecting conflicting mappings
+	if err := k.SafeSetValidatorIDToSignerAddr(ctx, validator.ID, validator.Signer); err != nil {
+		return err
+	}
+
 	// store validator with address prefixed with validator key as index
 	store.Set(GetValidatorKey(validator.Signer.Bytes()), bz)
-	k.Logger(ctx).Debug("Validator stored", "key", hex.EncodeToString(GetValidatorKey(validator.Signer.Bytes())), "validator", validator.String())
 
-	// add validator to validator ID => SignerAddress map
-	k.SetValidatorIDToSignerAddr(ctx, validator.ID, validator.Signer)
+	// current validator set is stale until next update
+	store.Delete(ValidatorSetAckKey)
+	k.Logger(ctx).Debug("Validator stored", "key", hex.EncodeToString(GetValidatorKey(validator.Signer.Bytes())), "validator", validator.String())
 
 	return nil
 }
@@ -137,7 +212,23 @@ func (k *Keeper) IsCurrentValidatorByAddress(ctx sdk.Context, address []byte) bo
 	}
 
 	// check if validator is current validator
-	return validator.IsCurrentValidator(ackCount)
+	return validator.IsCurrentValidatorWithGrace(ackCount, k.GetValidatorGracePeriod(ctx))
+}
+
+// GetValidatorGracePeriod returns epochs a validator stays current after EndEpoch
+func (k *Keeper) GetValidatorGracePeriod(ctx sdk.Context) uint64 {
+	// params may not be set yet during genesis
+	var grace uint64
+	k.paramSpace.GetIfExists(ctx, types.KeyValidatorGracePeriod, &grace)
+	return grace
+}
+
+// GetMinValidatorPower returns voting power a validator needs to be in the current validator set
+func (k *Keeper) GetMinValidatorPower(ctx sdk.Context) int64 {
+	// params may not be set yet during genesis
+	var minPower uint64
+	k.paramSpace.GetIfExists(ctx, types.KeyMinValidatorPower, &minPower)
+	return int64(minPower)
 }
 
 // GetValidatorInfo returns validator
@@ -147,19 +238,109 @@ func (k *Keeper) GetValidatorInfo(ctx sdk.Context, address []byte) (validator hm
 	// check if validator exists
 	key := GetValidatorKey(address)
 	if !store.Has(key) {
-		return validator, errors.New("Validator not found")
+		return validator, ErrValidatorNotFound
 	}
 
 	// unmarshall validator and return
 	validator, err = hmTypes.UnmarshallValidator(k.cdc, store.Get(key))
 	if err != nil {
-		return validator, err
+		return validator, fmt.Errorf("%w: %v", ErrValidatorDecode, err)
 	}
 
 	// return true if validator
 	return validator, nil
 }
 
+// ResolveValidatorInfo returns the current validator for a signer address, following the validator ID
+// when the signer was replaced. stale reports that the address is no longer the validator's signer.
+// Records of replaced signers remain in store (or archive, after pruning) and serve as signer history.
+func (k *Keeper) ResolveValidatorInfo(ctx sdk.Context, address []byte) (validator hmTypes.Validator, stale bool, err error) {
+	validator, err = k.GetValidatorInfo(ctx, address)
+	if errors.Is(err, ErrValidatorNotFound) {
+		validator, err = k.GetArchivedValidator(ctx, address)
+		if err != nil {
+			return validator, false, ErrValidatorNotFound
+		}
+	} else if err != nil {
+		return validator, false, err
+	}
+
+	// validator ID points to the current signer
+	signer, ok := k.GetSignerFromValidatorID(ctx, validator.ID)
+	if !ok || bytes.Equal(signer.Bytes(), address) {
+		return validator, false, nil
+	}
+
+	current, ok := k.GetValidatorFromValID(ctx, validator.ID)
+	if !ok {
+		return validator, false, nil
+	}
+
+	return current, true, nil
+}
+
+// HasValidator checks if validator exists for signer address, without decoding it
+func (k *Keeper) HasValidator(ctx sdk.Context, address []byte) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(GetValidatorKey(address))
+}
+
+// IsArchivedValidator checks if validator for signer address was pruned, without decoding it
+func (k *Keeper) IsArchivedValidator(ctx sdk.Context, address []byte) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(GetArchivedValidatorKey(address))
+}
+
+// HasValidatorID checks if validator ID is mapped to a signer address
+func (k *Keeper) HasValidatorID(ctx sdk.Context, valID hmTypes.ValidatorID) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(GetValidatorMapKey(valID.Bytes()))
+}
+
+// DeleteValidator removes validator and its ID => SignerAddress map entry, if it points to the validator
+func (k *Keeper) DeleteValidator(ctx sdk.Context, validator hmTypes.Validator) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(GetValidatorKey(validator.Signer.Bytes()))
+
+	if signer, ok := k.GetSignerFromValidatorID(ctx, validator.ID); ok && bytes.Equal(signer.Bytes(), validator.Signer.Bytes()) {
+		store.Delete(GetValidatorMapKey(validator.ID.Bytes()))
+	}
+
+	// current validator set is stale until next update
+	store.Delete(ValidatorSetAckKey)
+}
+
+// GetValidatorInfos returns validators for the given addresses along with the addresses not found
+func (k *Keeper) GetValidatorInfos(ctx sdk.Context, addresses [][]byte) (validators map[hmTypes.HeimdallAddress]hmTypes.Validator, missing [][]byte) {
+	store := ctx.KVStore(k.storeKey)
+	validators = make(map[hmTypes.HeimdallAddress]hmTypes.Validator, len(addresses))
+
+	for _, address := range addresses {
+		signer := hmTypes.BytesToHeimdallAddress(address)
+		if _, ok := validators[signer]; ok {
+			continue
+		}
+
+		// store.Get returns nil for absent keys, avoiding a separate Has lookup
+		bz := store.Get(GetValidatorKey(address))
+		if bz == nil {
+			missing = append(missing, address)
+			continue
+		}
+
+		validator, err := hmTypes.UnmarshallValidator(k.cdc, bz)
+		if err != nil {
+			k.Logger(ctx).Error("GetValidatorInfos | UnmarshallValidator", "address", signer.String(), "error", err)
+			missing = append(missing, address)
+			continue
+		}
+
+		validators[signer] = validator
+	}
+
+	return validators, missing
+}
+
 // GetActiveValidatorInfo returns active validator
 func (k *Keeper) GetActiveValidatorInfo(ctx sdk.Context, address []byte) (validator hmTypes.Validator, err error) {
 	validator, err = k.GetValidatorInfo(ctx, address)
@@ -169,7 +350,7 @@ func (k *Keeper) GetActiveValidatorInfo(ctx sdk.Context, address []byte) (valida
 
 	// get ack count
 	ackCount := k.moduleCommunicator.GetACKCount(ctx)
-	if !validator.IsCurrentValidator(ackCount) {
+	if !validator.IsCurrentValidatorWithGrace(ackCount, k.GetValidatorGracePeriod(ctx)) {
 		return validator, errors.New("Validator is not active")
 	}
 
@@ -177,16 +358,37 @@ func (k *Keeper) GetActiveValidatorInfo(ctx sdk.Context, address []byte) (valida
 	return validator, nil
 }
 
+// GetValidatorsAtEpoch returns validators active at the given epoch, regardless of the current one
+func (k *Keeper) GetValidatorsAtEpoch(ctx sdk.Context, epoch uint64) (validators []hmTypes.Validator) {
+	k.IterateValidatorsAndApplyFn(ctx, func(validator hmTypes.Validator) error {
+		if validator.IsActiveAtEpoch(epoch) {
+			validators = append(validators, validator)
+		}
+		return nil
+	})
+
+	return
+}
+
 // GetCurrentValidators returns all validators who are in validator set
 func (k *Keeper) GetCurrentValidators(ctx sdk.Context) (validators []hmTypes.Validator) {
 	// get ack count
 	ackCount := k.moduleCommunicator.GetACKCount(ctx)
 
+	grace := k.GetValidatorGracePeriod(ctx)
+	minPower := k.GetMinValidatorPower(ctx)
+
+	// use stored validator set if GetValidatorUpdates recomputed it at this ack count and params,
+	// writes of the set alone, e.g. by IncrementAccum, don't make it fresh
+	if k.IsValidatorSetFresh(ctx, ackCount) {
+		return k.getCurrentValidatorsFromSet(ctx, ackCount, grace, minPower)
+	}
+
 	// Get validators
 	// iterate through validator list
 	k.IterateValidatorsAndApplyFn(ctx, func(validator hmTypes.Validator) error {
 		// check if validator is valid for current epoch
-		if validator.IsCurrentValidator(ackCount) {
+		if validator.IsCurrentValidatorWithGrace(ackCount, grace) && validator.VotingPower >= minPower {
 			// append if validator is current valdiator
 			validators = append(validators, validator)
 		}
@@ -196,6 +398,24 @@ func (k *Keeper) GetCurrentValidators(ctx sdk.Context) (validators []hmTypes.Val
 	return
 }
 
+// getCurrentValidatorsFromSet returns current validators from the members of stored validator set
+func (k *Keeper) getCurrentValidatorsFromSet(ctx sdk.Context, ackCount uint64, grace uint64, minPower int64) (validators []hmTypes.Validator) {
+	validatorSet := k.GetValidatorSet(ctx)
+	for _, v := range validatorSet.Validators {
+		// set members carry proposer priority, return validators as stored
+		validator, err := k.GetValidatorInfo(ctx, v.Signer.Bytes())
+		if err != nil {
+			continue
+		}
+
+		if validator.IsCurrentValidatorWithGrace(ackCount, grace) && validator.VotingPower >= minPower {
+			validators = append(validators, validator)
+		}
+	}
+
+	return
+}
+
 func (k *Keeper) GetTotalPower(ctx sdk.Context) (totalPower int64) {
 	k.IterateCurrentValidatorsAndApplyFn(ctx, func(validator *hmTypes.Validator) bool {
 		totalPower += validator.VotingPower
@@ -222,6 +442,26 @@ func (k *Keeper) GetSpanEligibleValidators(ctx sdk.Context) (validators []hmType
 	return
 }
 
+// GetValidatorsExitingBefore returns validators with an EndEpoch set and at most epoch,
+// ordered by EndEpoch and then ID
+func (k *Keeper) GetValidatorsExitingBefore(ctx sdk.Context, epoch uint64) (validators []hmTypes.Validator) {
+	k.IterateValidatorsAndApplyFn(ctx, func(validator hmTypes.Validator) error {
+		if validator.EndEpoch != 0 && validator.EndEpoch <= epoch {
+			validators = append(validators, validator)
+		}
+		return nil
+	})
+
+	sort.Slice(validators, func(i, j int) bool {
+		if validators[i].EndEpoch != validators[j].EndEpoch {
+			return validators[i].EndEpoch < validators[j].EndEpoch
+		}
+		return validators[i].ID < validators[j].ID
+	})
+
+	return
+}
+
 // GetAllValidators returns all validators
 func (k *Keeper) GetAllValidators(ctx sdk.Context) (validators []*hmTypes.Validator) {
 	// iterate through validators and create validator update array
@@ -234,6 +474,99 @@ func (k *Keeper) GetAllValidators(ctx sdk.Context) (validators []*hmTypes.Valida
 	return
 }
 
+// PruneExpiredValidators archives and removes validators whose EndEpoch has been
+// passed by the configured grace period. It is a no-op unless pruning is enabled.
+func (k *Keeper) PruneExpiredValidators(ctx sdk.Context) (pruned []hmTypes.Validator) {
+	params := k.GetParams(ctx)
+	if !params.EnableValidatorPruning {
+		return nil
+	}
+
+	// get ack count
+	ackCount := k.moduleCommunicator.GetACKCount(ctx)
+
+	// validators still in the current set are kept regardless of EndEpoch
+	inCurrentSet := make(map[hmTypes.ValidatorID]bool)
+	for _, v := range k.GetValidatorSet(ctx).Validators {
+		inCurrentSet[v.ID] = true
+	}
+
+	k.IterateValidatorsAndApplyFn(ctx, func(validator hmTypes.Validator) error {
+		if validator.EndEpoch != 0 && ackCount > validator.EndEpoch+params.ValidatorPruneGracePeriod && !inCurrentSet[validator.ID] {
+			pruned = append(pruned, validator)
+		}
+		return nil
+	})
+
+	store := ctx.KVStore(k.storeKey)
+	for _, validator := range pruned {
+		bz, err := hmTypes.MarshallValidator(k.cdc, validator)
+		if err != nil {
+			k.Logger(ctx).Error("PruneExpiredValidators | MarshallValidator", "validatorID", validator.ID, "error", err)
+			continue
+		}
+
+		// archive validator, then remove it. ID => SignerAddress map entry is kept so
+		// lookups by ID resolve to the archive and the ID can't be joined again
+		store.Set(GetArchivedValidatorKey(validator.Signer.Bytes()), bz)
+		store.Delete(GetValidatorKey(validator.Signer.Bytes()))
+
+		k.Logger(ctx).Debug("Pruned expired validator", "validatorID", validator.ID, "endEpoch", validator.EndEpoch, "ackCount", ackCount)
+	}
+
+	return pruned
+}
+
+// GetArchivedValidator returns a validator removed by pruning
+func (k *Keeper) GetArchivedValidator(ctx sdk.Context, address []byte) (validator hmTypes.Validator, err error) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := store.Get(GetArchivedValidatorKey(address))
+	if bz == nil {
+		return validator, errors.New("Archived validator not found")
+	}
+
+	return hmTypes.UnmarshallValidator(k.cdc, bz)
+}
+
+// GetArchivedValidators returns all validators removed by pruning
+func (k *Keeper) GetArchivedValidators(ctx sdk.Context) (validators []*hmTypes.Validator) {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, ArchivedValidatorsKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		validator, err := hmTypes.UnmarshallValidator(k.cdc, iterator.Value())
+		if err != nil {
+			k.Logger(ctx).Error("GetArchivedValidators | UnmarshallValidator", "error", err)
+			continue
+		}
+		validators = append(validators, &validator)
+	}
+
+	return validators
+}
+
+// AddArchivedValidator stores a pruned validator, mapping its ID to its signer unless the ID is already mapped
+func (k *Keeper) AddArchivedValidator(ctx sdk.Context, validator hmTypes.Validator) error {
+	bz, err := hmTypes.MarshallValidator(k.cdc, validator)
+	if err != nil {
+		return err
+	}
+
+	if !k.HasValidatorID(ctx, validator.ID) {
+		if err := k.SafeSetValidatorIDToSignerAddr(ctx, validator.ID, validator.Signer); err != nil {
+			return err
+		}
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetArchivedValidatorKey(validator.Signer.Bytes()), bz)
+
+	return nil
+}
+
 // IterateValidatorsAndApplyFn interate validators and apply the given function.
 func (k *Keeper) IterateValidatorsAndApplyFn(ctx sdk.Context, f func(validator hmTypes.Validator) error) {
 	store := ctx.KVStore(k.storeKey)
@@ -255,133 +588,1032 @@ func (k *Keeper) IterateValidatorsAndApplyFn(ctx sdk.Context, f func(validator h
 
 // UpdateSigner updates validator with signer and pubkey + validator => signer map
 func (k *Keeper) UpdateSigner(ctx sdk.Context, newSigner hmTypes.HeimdallAddress, newPubkey hmTypes.PubKey, prevSigner hmTypes.HeimdallAddress) error {
-	// get old validator from state and make power 0
+	// get old validator from state
 	validator, err := k.GetValidatorInfo(ctx, prevSigner.Bytes())
 	if err != nil {
 		k.Logger(ctx).Error("Unable to fetch valiator from store")
 		return err
 	}
 
-	// copy power to reassign below
-	validatorPower := validator.VotingPower
-	validator.VotingPower = 0
-
-	// update validator
-	if err := k.AddValidator(ctx, validator); err != nil {
-		k.Logger(ctx).Error("UpdateSigner | AddValidator", "error", err)
+	if signer, ok := k.GetSignerFromValidatorID(ctx, validator.ID); ok && !bytes.Equal(signer.Bytes(), prevSigner.Bytes()) {
+		return fmt.Errorf("%v is not the current signer of validator %v", prevSigner.String(), validator.ID)
 	}
 
-	//update signer in prev Validator
-	validator.Signer = newSigner
-	validator.PubKey = newPubkey
-	validator.VotingPower = validatorPower
-
-	// add updated validator to store with new key
-	if err := k.AddValidator(ctx, validator); err != nil {
-		k.Logger(ctx).Error("UpdateSigner | AddValidator", "error", err)
+	if err := k.SwapSigner(ctx, validator.ID, newSigner, newPubkey); err != nil {
+		k.Logger(ctx).Error("UpdateSigner | SwapSigner", "error", err)
+		return err
 	}
 	return nil
 }
 
-// UpdateValidatorSetInStore adds validator set to store
-func (k *Keeper) UpdateValidatorSetInStore(ctx sdk.Context, newValidatorSet hmTypes.ValidatorSet) error {
-	// TODO check if we may have to delay this by 1 height to sync with tendermint validator updates
-	store := ctx.KVStore(k.storeKey)
+// SwapSigner moves validator to new signer and pubkey, keeping its power, and remaps validator ID to the new signer.
+// The new pubkey must not be in use, so no two validators share a consensus identity.
+// The old signer keeps a zero power record ending at the current ack count, which removes it from the validator set
+// on next update and marks the set stale.
+// Preconditions are checked before any write and all writes go through a cache context,
+// so on error the store is left unchanged.
+func (k *Keeper) SwapSigner(ctx sdk.Context, valID hmTypes.ValidatorID, newSigner hmTypes.HeimdallAddress, newPubkey hmTypes.PubKey) error {
+	validator, ok := k.GetValidatorFromValID(ctx, valID)
+	if !ok {
+		return fmt.Errorf("%w: validator %v", ErrValidatorNotFound, valID)
+	}
 
-	// marshall validator set
-	bz, err := k.cdc.MarshalBinaryBare(newValidatorSet)
-	if err != nil {
+	if newSigner.Empty() {
+		return fmt.Errorf("empty new signer for validator %v", valID)
+	}
+	if bytes.Equal(newSigner.Bytes(), validator.Signer.Bytes()) {
+		return fmt.Errorf("validator %v already has signer %v", valID, newSigner.String())
+	}
+	// signer is the consensus address of the pubkey, it must not index another record
+	if !bytes.Equal(newPubkey.Address().Bytes(), newSigner.Bytes()) {
+		return fmt.Errorf("signer %v doesn't match pubkey address %v", newSigner.String(), newPubkey.Address().String())
+	}
+	if existing, err := k.GetValidatorInfo(ctx, newSigner.Bytes()); err == nil {
+		if existing.ID != valID {
+			return fmt.Errorf("%w: validator %v has signer %v", ErrPubKeyInUse, existing.ID, newSigner.String())
+		}
+		return fmt.Errorf("signer %v already used by validator %v", newSigner.String(), valID)
+	} else if !errors.Is(err, ErrValidatorNotFound) {
 		return err
 	}
 
-	// set validator set with CurrentValidatorSetKey as key in store
-	store.Set(CurrentValidatorSetKey, bz)
-	return nil
-}
+	cacheCtx, writeCache := ctx.CacheContext()
 
-// GetValidatorSet returns current Validator Set from store
-func (k *Keeper) GetValidatorSet(ctx sdk.Context) (validatorSet hmTypes.ValidatorSet) {
-	store := ctx.KVStore(k.storeKey)
-	// get current validator set from store
-	bz := store.Get(CurrentValidatorSetKey)
-	// unmarhsall
+	// old signer record with power 0, ending at current ack count unless it ends earlier
+	oldValidator := validator
+	oldValidator.VotingPower = 0
+	if ackCount := k.moduleCommunicator.GetACKCount(cacheCtx); ackCount != 0 && (oldValidator.EndEpoch == 0 || ackCount < oldValidator.EndEpoch) {
+		oldValidator.EndEpoch = ackCount
+	}
+	if err := k.AddValidator(cacheCtx, oldValidator); err != nil {
+		return err
+	}
 
-	if err := k.cdc.UnmarshalBinaryBare(bz, &validatorSet); err != nil {
-		k.Logger(ctx).Error("GetValidatorSet | UnmarshalBinaryBare", "error", err)
+	// remap validator ID to new signer
+	k.SetValidatorIDToSignerAddr(cacheCtx, valID, newSigner)
+
+	// add updated validator to store with new key
+	validator.Signer = newSigner
+	validator.PubKey = newPubkey
+	if err := k.AddValidator(cacheCtx, validator); err != nil {
+		return err
 	}
 
-	// return validator set
-	return validatorSet
+	writeCache()
+	return nil
 }
 
-// IncrementAccum increments accum for validator set by n times and replace validator set in store
-func (k *Keeper) IncrementAccum(ctx sdk.Context, times int) {
-	// get validator set
-	validatorSet := k.GetValidatorSet(ctx)
+// GetPowerDivisor returns divisor scaling total stake down to voting power, 0 if powers are not derived from stake
+func (k *Keeper) GetPowerDivisor(ctx sdk.Context) uint64 {
+	// params may not be set yet during genesis
+	var divisor uint64
+	k.paramSpace.GetIfExists(ctx, types.KeyPowerDivisor, &divisor)
+	return divisor
+}
 
-	// increment accum
-	validatorSet.IncrementProposerPriority(times)
+// PowerFromStake returns voting power of validator derived from its total stake with the current power divisor.
+// Validators keep their power while the divisor is 0 or they have no stake, e.g. genesis validators.
+func (k *Keeper) PowerFromStake(ctx sdk.Context, validator hmTypes.Validator) int64 {
+	divisor := int64(k.GetPowerDivisor(ctx))
+	if divisor == 0 || validator.GetTotalStake() == 0 {
+		return validator.VotingPower
+	}
 
-	// replace
+	return validator.GetTotalStake() / divisor
+}
 
-	if err := k.UpdateValidatorSetInStore(ctx, validatorSet); err != nil {
-		k.Logger(ctx).Error("IncrementAccum | UpdateValidatorSetInStore", "error", err)
+// RecomputeAllValidatorPowers re-derives voting power of every validator from its stored stake with the
+// current power divisor, to be called after the divisor changed. Changed validators are written back along
+// with their power update events, all or none, and the current validator set picks them up at the next
+// validator update.
+func (k *Keeper) RecomputeAllValidatorPowers(ctx sdk.Context) (updated []hmTypes.Validator, err error) {
+	previousPowers := make(map[hmTypes.ValidatorID]int64)
+	if err := k.iterateValidatorsOrError(ctx, func(validator hmTypes.Validator) {
+		if power := k.PowerFromStake(ctx, validator); power != validator.VotingPower {
+			previousPowers[validator.ID] = validator.VotingPower
+			validator.VotingPower = power
+			updated = append(updated, validator)
+		}
+	}); err != nil {
+		return nil, err
 	}
-}
 
-// GetNextProposer returns next proposer
-func (k *Keeper) GetNextProposer(ctx sdk.Context) *hmTypes.Validator {
-	// get validator set
-	validatorSet := k.GetValidatorSet(ctx)
+	// write all validators or none
+	cacheCtx, writeCache := ctx.CacheContext()
 
-	// Increment accum in copy
-	copiedValidatorSet := validatorSet.CopyIncrementProposerPriority(1)
+	events := make(sdk.Events, 0, len(updated))
+	for _, validator := range updated {
+		if err := k.AddValidator(cacheCtx, validator); err != nil {
+			return nil, err
+		}
 
-	// get signer address for next signer
-	return copiedValidatorSet.GetProposer()
-}
+		events = append(events, sdk.NewEvent(
+			types.EventTypePowerUpdate,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyValidatorID, strconv.FormatUint(validator.ID.Uint64(), 10)),
+			sdk.NewAttribute(types.AttributeKeyPreviousPower, strconv.FormatInt(previousPowers[validator.ID], 10)),
+			sdk.NewAttribute(types.AttributeKeyPower, strconv.FormatInt(validator.VotingPower, 10)),
+		))
+	}
 
-// GetCurrentProposer returns current proposer
-func (k *Keeper) GetCurrentProposer(ctx sdk.Context) *hmTypes.Validator {
-	// get validator set
-	validatorSet := k.GetValidatorSet(ctx)
+	writeCache()
+	ctx.EventManager().EmitEvents(events)
 
-	// return get proposer
-	return validatorSet.GetProposer()
+	return updated, nil
 }
 
-// SetValidatorIDToSignerAddr sets mapping for validator ID to signer address
-func (k *Keeper) SetValidatorIDToSignerAddr(ctx sdk.Context, valID hmTypes.ValidatorID, signerAddr hmTypes.HeimdallAddress) {
+// iterateValidatorsOrError iterates validators and applies f, unlike IterateValidatorsAndApplyFn it fails
+// on a validator that can't be decoded
+func (k *Keeper) iterateValidatorsOrError(ctx sdk.Context, f func(validator hmTypes.Validator)) error {
 	store := ctx.KVStore(k.storeKey)
-	store.Set(GetValidatorMapKey(valID.Bytes()), signerAddr.Bytes())
+
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorsKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		validator, err := hmTypes.UnmarshallValidator(k.cdc, iterator.Value())
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrValidatorDecode, err)
+		}
+		f(validator)
+	}
+
+	return nil
 }
 
-// GetSignerFromValidatorID get signer address from validator ID
-func (k *Keeper) GetSignerFromValidatorID(ctx sdk.Context, valID hmTypes.ValidatorID) (common.Address, bool) {
+// ApplyPowerDivisorChange recomputes validator powers if the power divisor changed since last applied.
+// The divisor is only recorded the first time, so existing powers are kept on chains started without it.
+// Changing it back to 0 keeps the derived powers.
+func (k *Keeper) ApplyPowerDivisorChange(ctx sdk.Context) ([]hmTypes.Validator, error) {
 	store := ctx.KVStore(k.storeKey)
-	key := GetValidatorMapKey(valID.Bytes())
-	// check if validator address has been mapped
-	if !store.Has(key) {
-		return helper.ZeroAddress, false
+	divisor := k.GetPowerDivisor(ctx)
+
+	applied := store.Get(AppliedPowerDivisorKey)
+	if applied != nil && string(applied) == strconv.FormatUint(divisor, 10) {
+		return nil, nil
 	}
-	// return address from bytes
-	return common.BytesToAddress(store.Get(key)), true
+
+	var updated []hmTypes.Validator
+	if applied != nil {
+		var err error
+		if updated, err = k.RecomputeAllValidatorPowers(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	store.Set(AppliedPowerDivisorKey, []byte(strconv.FormatUint(divisor, 10)))
+
+	return updated, nil
 }
 
-// GetValidatorFromValID returns signer from validator ID
-func (k *Keeper) GetValidatorFromValID(ctx sdk.Context, valID hmTypes.ValidatorID) (validator hmTypes.Validator, ok bool) {
-	signerAddr, ok := k.GetSignerFromValidatorID(ctx, valID)
-	if !ok {
-		return validator, ok
+// UpdateCommissionRate updates commission rate of validator, in basis points
+func (k *Keeper) UpdateCommissionRate(ctx sdk.Context, valID hmTypes.ValidatorID, rate uint64) error {
+	if rate > hmTypes.MaxCommissionRate {
+		return fmt.Errorf("invalid commission rate %v for validator %v, max %v", rate, valID, hmTypes.MaxCommissionRate)
 	}
-	// query for validator signer address
-	validator, err := k.GetValidatorInfo(ctx, signerAddr.Bytes())
-	if err != nil {
+
+	// get validator from state
+	validator, ok := k.GetValidatorFromValID(ctx, valID)
+	if !ok {
+		return errors.New("validator not found")
+	}
+
+	if validator.CommissionRate == rate {
+		return nil
+	}
+	validator.CommissionRate = rate
+
+	// save validator
+	if err := k.AddValidator(ctx, validator); err != nil {
+		return err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCommissionUpdate,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyValidatorID, strconv.FormatUint(valID.Uint64(), 10)),
+			sdk.NewAttribute(types.AttributeKeyCommissionRate, strconv.FormatUint(rate, 10)),
+		),
+	)
+
+	return nil
+}
+
+// SetValidatorDescription validates and stores human readable metadata of validator
+func (k *Keeper) SetValidatorDescription(ctx sdk.Context, valID hmTypes.ValidatorID, description types.Description) error {
+	if err := description.Validate(); err != nil {
+		return err
+	}
+
+	if !k.HasValidatorID(ctx, valID) {
+		return fmt.Errorf("%w: validator %v", ErrValidatorNotFound, valID)
+	}
+
+	if existing, ok := k.GetValidatorDescription(ctx, valID); ok && existing == description {
+		return nil
+	}
+
+	bz, err := k.cdc.MarshalBinaryBare(description)
+	if err != nil {
+		return err
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetValidatorDescriptionKey(valID), bz)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeDescriptionUpdate,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyValidatorID, strconv.FormatUint(valID.Uint64(), 10)),
+			sdk.NewAttribute(types.AttributeKeyMoniker, description.Moniker),
+		),
+	)
+
+	return nil
+}
+
+// GetValidatorDescription returns stored metadata of validator
+func (k *Keeper) GetValidatorDescription(ctx sdk.Context, valID hmTypes.ValidatorID) (description types.Description, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetValidatorDescriptionKey(valID))
+	if bz == nil {
+		return description, false
+	}
+
+	if err := k.cdc.UnmarshalBinaryBare(bz, &description); err != nil {
+		k.Logger(ctx).Error("Error unmarshalling validator description", "validatorID", valID, "error", err)
+		return types.Description{}, false
+	}
+
+	return description, true
+}
+
+// UpdateValidatorSetInStore adds validator set to store
+func (k *Keeper) UpdateValidatorSetInStore(ctx sdk.Context, newValidatorSet hmTypes.ValidatorSet) error {
+	// TODO check if we may have to delay this by 1 height to sync with tendermint validator updates
+	store := ctx.KVStore(k.storeKey)
+
+	// skip redundant write if set is unchanged
+	if store.Has(CurrentValidatorSetKey) {
+		currentValidatorSet := k.GetValidatorSet(ctx)
+		if currentValidatorSet.Equal(&newValidatorSet) {
+			recordValidatorSet(&newValidatorSet)
+			return nil
+		}
+	}
+
+	// marshall validator set
+	bz, err := k.cdc.MarshalBinaryBare(newValidatorSet)
+	if err != nil {
+		return err
+	}
+
+	// set validator set with CurrentValidatorSetKey as key in store
+	store.Set(CurrentValidatorSetKey, bz)
+
+	// retain validator set by height, if enabled
+	k.retainValidatorSet(ctx, bz)
+
+	recordValidatorSet(&newValidatorSet)
+
+	return nil
+}
+
+// retainValidatorSet stores validator set by height and prunes sets older than the retention window
+func (k *Keeper) retainValidatorSet(ctx sdk.Context, bz []byte) {
+	// params may not be set yet during genesis
+	var retention uint64
+	k.paramSpace.GetIfExists(ctx, types.KeyValidatorSetHistoryRetention, &retention)
+	if retention == 0 {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetHistoricalValidatorSetKey(ctx.BlockHeight()), bz)
+
+	// prune sets written before the retention window
+	if ctx.BlockHeight() <= int64(retention) {
+		return
+	}
+
+	iterator := store.Iterator(HistoricalValidatorSetKey, GetHistoricalValidatorSetKey(ctx.BlockHeight()-int64(retention)))
+	defer iterator.Close()
+
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// GetHistoricalValidatorSet returns validator set written at height
+func (k *Keeper) GetHistoricalValidatorSet(ctx sdk.Context, height int64) (validatorSet hmTypes.ValidatorSet, err error) {
+	store := ctx.KVStore(k.storeKey)
+	key := GetHistoricalValidatorSetKey(height)
+	if !store.Has(key) {
+		return validatorSet, fmt.Errorf("validator set at height %v not retained", height)
+	}
+
+	err = k.cdc.UnmarshalBinaryBare(store.Get(key), &validatorSet)
+	return validatorSet, err
+}
+
+// GetValidatorSetDiff returns validators added, removed and with changed power between validator sets written at given heights
+func (k *Keeper) GetValidatorSetDiff(ctx sdk.Context, fromHeight int64, toHeight int64) (diff types.ValidatorSetDiff, err error) {
+	fromSet, err := k.GetHistoricalValidatorSet(ctx, fromHeight)
+	if err != nil {
+		return diff, err
+	}
+
+	toSet, err := k.GetHistoricalValidatorSet(ctx, toHeight)
+	if err != nil {
+		return diff, err
+	}
+
+	// index validators by ID, signer may change between heights
+	fromValidators := make(map[hmTypes.ValidatorID]*hmTypes.Validator, len(fromSet.Validators))
+	for _, v := range fromSet.Validators {
+		fromValidators[v.ID] = v
+	}
+
+	toValidators := make(map[hmTypes.ValidatorID]*hmTypes.Validator, len(toSet.Validators))
+	for _, v := range toSet.Validators {
+		toValidators[v.ID] = v
+
+		prev, ok := fromValidators[v.ID]
+		if !ok {
+			diff.Added = append(diff.Added, *v)
+		} else if prev.VotingPower != v.VotingPower {
+			diff.PowerChanged = append(diff.PowerChanged, *v)
+		}
+	}
+
+	for _, v := range fromSet.Validators {
+		if _, ok := toValidators[v.ID]; !ok {
+			diff.Removed = append(diff.Removed, *v)
+		}
+	}
+
+	return diff, nil
+}
+
+// ComputeSetTransition returns the operations transforming the current validator set into target:
+// removals, then power updates, then additions, each ordered by validator ID.
+// Validators are matched by ID, a validator with a new signer is removed under the old one and added under the new one.
+// Target validators without power are treated as absent.
+func (k *Keeper) ComputeSetTransition(ctx sdk.Context, target hmTypes.ValidatorSet) ([]types.SetTransitionOp, error) {
+	targetValidators := make(map[hmTypes.ValidatorID]*hmTypes.Validator, len(target.Validators))
+	targetSigners := make(map[hmTypes.HeimdallAddress]hmTypes.ValidatorID, len(target.Validators))
+	for _, v := range target.Validators {
+		if v.VotingPower < 0 {
+			return nil, fmt.Errorf("negative voting power %v for validator %v", v.VotingPower, v.ID)
+		}
+		if v.VotingPower == 0 {
+			continue
+		}
+		if _, ok := targetValidators[v.ID]; ok {
+			return nil, fmt.Errorf("duplicate validator %v in target set", v.ID)
+		}
+		if id, ok := targetSigners[v.Signer]; ok {
+			return nil, fmt.Errorf("signer %v used by validators %v and %v in target set", v.Signer.String(), id, v.ID)
+		}
+		targetValidators[v.ID] = v
+		targetSigners[v.Signer] = v.ID
+	}
+
+	currentSet := k.GetValidatorSet(ctx)
+	currentValidators := make(map[hmTypes.ValidatorID]*hmTypes.Validator, len(currentSet.Validators))
+	for _, v := range currentSet.Validators {
+		currentValidators[v.ID] = v
+	}
+
+	var removed, updated, added []types.SetTransitionOp
+	for _, v := range currentSet.Validators {
+		next, ok := targetValidators[v.ID]
+		if !ok || !bytes.Equal(next.Signer.Bytes(), v.Signer.Bytes()) {
+			validator := *v.Copy()
+			validator.VotingPower = 0
+			removed = append(removed, types.SetTransitionOp{Type: types.SetTransitionRemove, Validator: validator})
+		}
+	}
+
+	for _, v := range targetValidators {
+		prev, ok := currentValidators[v.ID]
+		switch {
+		case !ok || !bytes.Equal(prev.Signer.Bytes(), v.Signer.Bytes()):
+			added = append(added, types.SetTransitionOp{Type: types.SetTransitionAdd, Validator: *v.Copy()})
+		case prev.VotingPower != v.VotingPower:
+			updated = append(updated, types.SetTransitionOp{Type: types.SetTransitionUpdate, Validator: *v.Copy()})
+		}
+	}
+
+	ops := make([]types.SetTransitionOp, 0, len(removed)+len(updated)+len(added))
+	for _, group := range [][]types.SetTransitionOp{removed, updated, added} {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Validator.ID < group[j].Validator.ID
+		})
+		ops = append(ops, group...)
+	}
+
+	return ops, nil
+}
+
+// GetValidatorUpdates applies validator changes to the current validator set and returns them as
+// tendermint validator updates, removed validators with zero power. Nothing is recomputed while
+// the set is fresh, a change of validators, ack count or membership params makes it stale.
+// It is the only place the set is marked fresh, once it holds every pending update.
+func (k *Keeper) GetValidatorUpdates(ctx sdk.Context) ([]abci.ValidatorUpdate, error) {
+	ackCount := k.moduleCommunicator.GetACKCount(ctx)
+	if k.IsValidatorSetFresh(ctx, ackCount) {
+		return nil, nil
+	}
+
+	currentValidatorSet, setUpdates := k.getPendingValidatorUpdates(ctx, ackCount)
+	if len(setUpdates) == 0 {
+		// current validator set is in sync with validators
+		k.markValidatorSetFresh(ctx, ackCount)
+		return nil, nil
+	}
+
+	// create new validator set
+	if err := currentValidatorSet.UpdateWithChangeSet(setUpdates); err != nil {
+		return nil, err
+	}
+
+	// increment proposer priority
+	currentValidatorSet.IncrementProposerPriority(1)
+
+	// validator set change
+	k.Logger(ctx).Debug("Updated current validator set", "proposer", currentValidatorSet.GetProposer())
+
+	// save set in store
+	if err := k.UpdateValidatorSetInStore(ctx, currentValidatorSet); err != nil {
+		return nil, err
+	}
+
+	// set now holds every pending update
+	k.markValidatorSetFresh(ctx, ackCount)
+
+	// convert updates to tendermint updates
+	tmValUpdates := make([]abci.ValidatorUpdate, 0, len(setUpdates))
+	for _, v := range setUpdates {
+		tmValUpdates = append(tmValUpdates, abci.ValidatorUpdate{
+			Power:  int64(v.VotingPower),
+			PubKey: v.PubKey.ABCIPubKey(),
+		})
+	}
+
+	return tmValUpdates, nil
+}
+
+// getPendingValidatorUpdates returns the current validator set and the validator changes not yet applied to it
+func (k *Keeper) getPendingValidatorUpdates(ctx sdk.Context, ackCount uint64) (hmTypes.ValidatorSet, []*hmTypes.Validator) {
+	currentValidatorSet := k.GetValidatorSet(ctx)
+
+	// validators below the power floor leave the set, they stay stored with their power
+	validators := k.GetAllValidators(ctx)
+	minPower := k.GetMinValidatorPower(ctx)
+	for _, v := range validators {
+		if v.VotingPower < minPower {
+			v.VotingPower = 0
+		}
+	}
+
+	// get validator updates
+	setUpdates := helper.GetUpdatedValidatorsWithGrace(
+		&currentValidatorSet,           // pointer to current validator set -- UpdateValidators will modify it
+		validators,                     // All validators
+		ackCount,                       // ack count
+		k.GetValidatorGracePeriod(ctx), // epochs validators stay after EndEpoch
+	)
+
+	return currentValidatorSet, setUpdates
+}
+
+// markValidatorSetFresh records that current validator set is in sync with validators at given ack count.
+// Only callers that recomputed the set may mark it, writes of the set alone don't make it fresh.
+func (k *Keeper) markValidatorSetFresh(ctx sdk.Context, ackCount uint64) {
+	if k.IsValidatorSetFresh(ctx, ackCount) {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(ValidatorSetAckKey, k.validatorSetFreshness(ctx, ackCount))
+}
+
+// IsValidatorSetFresh checks if current validator set is in sync with validators at given ack count
+// and current membership params
+func (k *Keeper) IsValidatorSetFresh(ctx sdk.Context, ackCount uint64) bool {
+	store := ctx.KVStore(k.storeKey)
+	return bytes.Equal(store.Get(ValidatorSetAckKey), k.validatorSetFreshness(ctx, ackCount))
+}
+
+// validatorSetFreshness encodes ack count along with the params deciding set membership. Params
+// may change through governance without SetParams, so they are compared rather than invalidated.
+func (k *Keeper) validatorSetFreshness(ctx sdk.Context, ackCount uint64) []byte {
+	return []byte(fmt.Sprintf("%d/%d/%d", ackCount, k.GetValidatorGracePeriod(ctx), k.GetMinValidatorPower(ctx)))
+}
+
+// GetValidatorSet returns current Validator Set from store, decoded only when the stored set changed
+func (k *Keeper) GetValidatorSet(ctx sdk.Context) (validatorSet hmTypes.ValidatorSet) {
+	store := ctx.KVStore(k.storeKey)
+	// get current validator set from store
+	bz := store.Get(CurrentValidatorSetKey)
+
+	if validatorSet, ok := k.validatorSetCache.get(bz); ok {
+		return validatorSet
+	}
+
+	validatorSet = k.decodeValidatorSet(ctx, bz)
+	if len(bz) > 0 {
+		k.validatorSetCache.set(bz, validatorSet)
+	}
+
+	// return validator set
+	return validatorSet
+}
+
+// decodeValidatorSet unmarshalls the stored validator set, bypassing the cache
+func (k *Keeper) decodeValidatorSet(ctx sdk.Context, bz []byte) (validatorSet hmTypes.ValidatorSet) {
+	// unmarhsall
+	if err := k.cdc.UnmarshalBinaryBare(bz, &validatorSet); err != nil {
+		k.Logger(ctx).Error("GetValidatorSet | UnmarshalBinaryBare", "error", err)
+	}
+
+	// return validator set
+	return validatorSet
+}
+
+// IncrementAccum increments accum for validator set by n times and replace validator set in store
+func (k *Keeper) IncrementAccum(ctx sdk.Context, times int) {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+
+	switch len(validatorSet.Validators) {
+	case 0:
+		// nothing to rotate, incrementing an empty set panics
+		k.Logger(ctx).Error("IncrementAccum | empty validator set, skipping")
+		return
+	case 1:
+		// sole validator is always the proposer
+		validatorSet.Proposer = validatorSet.Validators[0].Copy()
+	default:
+		// increment accum
+		validatorSet.IncrementProposerPriority(times)
+	}
+
+	// replace
+
+	if err := k.UpdateValidatorSetInStore(ctx, validatorSet); err != nil {
+		k.Logger(ctx).Error("IncrementAccum | UpdateValidatorSetInStore", "error", err)
+	}
+}
+
+// CheckProposerPriorityBounds verifies that proposer priorities of the current validator set
+// stay within the window allowed relative to total voting power
+func (k *Keeper) CheckProposerPriorityBounds(ctx sdk.Context) error {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+	if validatorSet.IsNilOrEmpty() {
+		return nil
+	}
+
+	// window enforced by RescalePriorities plus one increment round
+	bound := (hmTypes.PriorityWindowSizeFactor + 1) * validatorSet.TotalVotingPower()
+
+	var offending []string
+	for _, v := range validatorSet.Validators {
+		if v.ProposerPriority > bound || v.ProposerPriority < -bound {
+			offending = append(offending, fmt.Sprintf("%v:%v", v.ID, v.ProposerPriority))
+		}
+	}
+
+	if len(offending) > 0 {
+		return fmt.Errorf("proposer priority out of bounds [%v, %v] for validators: %v", -bound, bound, strings.Join(offending, ", "))
+	}
+
+	return nil
+}
+
+// CheckPowerConcentration verifies that no validator of the current validator set
+// holds more than MaxPowerSharePercent of total voting power
+func (k *Keeper) CheckPowerConcentration(ctx sdk.Context) error {
+	// params may not be set yet during genesis
+	var maxShare uint64
+	k.paramSpace.GetIfExists(ctx, types.KeyMaxPowerSharePercent, &maxShare)
+	if maxShare == 0 {
+		return nil
+	}
+
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+	if validatorSet.IsNilOrEmpty() {
+		return nil
+	}
+
+	totalPower := validatorSet.TotalVotingPower()
+
+	var offending []string
+	for _, v := range validatorSet.Validators {
+		if v.VotingPower*100 > int64(maxShare)*totalPower {
+			offending = append(offending, fmt.Sprintf("%v:%v", v.ID, v.VotingPower))
+		}
+	}
+
+	if len(offending) > 0 {
+		return fmt.Errorf("voting power exceeds %v%% of total power %v for validators: %v", maxShare, totalPower, strings.Join(offending, ", "))
+	}
+
+	return nil
+}
+
+// CheckSignerUniqueness verifies that no signer address is mapped to more than one validator ID
+func (k *Keeper) CheckSignerUniqueness(ctx sdk.Context) error {
+	store := ctx.KVStore(k.storeKey)
+
+	// get validator map iterator
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorMapKey)
+	defer iterator.Close()
+
+	var signers []string
+	valIDs := make(map[string][]string)
+	for ; iterator.Valid(); iterator.Next() {
+		signer := common.BytesToAddress(iterator.Value()).Hex()
+		if _, ok := valIDs[signer]; !ok {
+			signers = append(signers, signer)
+		}
+		valIDs[signer] = append(valIDs[signer], string(iterator.Key()[len(ValidatorMapKey):]))
+	}
+
+	var duplicates []string
+	for _, signer := range signers {
+		if len(valIDs[signer]) > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("%v:[%v]", signer, strings.Join(valIDs[signer], " ")))
+		}
+	}
+
+	if len(duplicates) > 0 {
+		return fmt.Errorf("signers mapped to multiple validator IDs: %v", strings.Join(duplicates, ", "))
+	}
+
+	return nil
+}
+
+// GetQuorumPower returns minimum voting power required for +2/3 majority of current validator set
+func (k *Keeper) GetQuorumPower(ctx sdk.Context) int64 {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+	if validatorSet.IsNilOrEmpty() {
+		return 0
+	}
+
+	return validatorSet.TotalVotingPower()*2/3 + 1
+}
+
+// GetValidatorPowerShare returns voting power of validator valID as a fraction of total power of current validator set.
+// The share is for display and alerting only, it is 0 when the set has no power.
+func (k *Keeper) GetValidatorPowerShare(ctx sdk.Context, valID hmTypes.ValidatorID) (float64, error) {
+	validatorSet := k.GetValidatorSet(ctx)
+	for _, v := range validatorSet.Validators {
+		if v.ID != valID {
+			continue
+		}
+
+		totalPower := validatorSet.TotalVotingPower()
+		if totalPower == 0 {
+			return 0, nil
+		}
+		return float64(v.VotingPower) / float64(totalPower), nil
+	}
+
+	return 0, fmt.Errorf("%w: validator %v not in current validator set", ErrValidatorNotFound, valID)
+}
+
+// GetPowerDistribution returns voting power share of every current validator, see GetValidatorPowerShare
+func (k *Keeper) GetPowerDistribution(ctx sdk.Context) map[hmTypes.ValidatorID]float64 {
+	validatorSet := k.GetValidatorSet(ctx)
+	totalPower := validatorSet.TotalVotingPower()
+
+	shares := make(map[hmTypes.ValidatorID]float64, len(validatorSet.Validators))
+	for _, v := range validatorSet.Validators {
+		if totalPower == 0 {
+			shares[v.ID] = 0
+			continue
+		}
+		shares[v.ID] = float64(v.VotingPower) / float64(totalPower)
+	}
+
+	return shares
+}
+
+// HasQuorum checks if given signers hold +2/3 voting power of current validator set
+func (k *Keeper) HasQuorum(ctx sdk.Context, signers []hmTypes.HeimdallAddress) bool {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+	if validatorSet.IsNilOrEmpty() {
+		return false
+	}
+
+	signed := make(map[hmTypes.HeimdallAddress]bool, len(signers))
+	for _, signer := range signers {
+		signed[signer] = true
+	}
+
+	// sum power of current validators who signed, duplicates are counted once
+	var signedPower int64
+	for _, validator := range validatorSet.Validators {
+		if signed[validator.Signer] {
+			signedPower += validator.VotingPower
+		}
+	}
+
+	return signedPower >= validatorSet.TotalVotingPower()*2/3+1
+}
+
+// MinimalSignersForQuorum returns the fewest validators, highest power first, whose signatures
+// together with alreadySigned reach +2/3 voting power of current validator set
+func (k *Keeper) MinimalSignersForQuorum(ctx sdk.Context, alreadySigned []hmTypes.HeimdallAddress) (validators []hmTypes.Validator) {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+	if validatorSet.IsNilOrEmpty() {
+		return nil
+	}
+
+	signed := make(map[hmTypes.HeimdallAddress]bool, len(alreadySigned))
+	for _, signer := range alreadySigned {
+		signed[signer] = true
+	}
+
+	var signedPower int64
+	var pending []hmTypes.Validator
+	for _, validator := range validatorSet.Validators {
+		if signed[validator.Signer] {
+			signedPower += validator.VotingPower
+		} else if validator.VotingPower > 0 {
+			pending = append(pending, *validator)
+		}
+	}
+
+	// highest power first, signer address breaks ties
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].VotingPower != pending[j].VotingPower {
+			return pending[i].VotingPower > pending[j].VotingPower
+		}
+		return bytes.Compare(pending[i].Signer.Bytes(), pending[j].Signer.Bytes()) < 0
+	})
+
+	quorumPower := validatorSet.TotalVotingPower()*2/3 + 1
+	for _, validator := range pending {
+		if signedPower >= quorumPower {
+			break
+		}
+		validators = append(validators, validator)
+		signedPower += validator.VotingPower
+	}
+
+	return validators
+}
+
+// GetNextProposer returns next proposer
+func (k *Keeper) GetNextProposer(ctx sdk.Context) *hmTypes.Validator {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+
+	// Increment accum in copy
+	copiedValidatorSet := validatorSet.CopyIncrementProposerPriority(1)
+
+	// get signer address for next signer
+	return copiedValidatorSet.GetProposer()
+}
+
+// GetNextProposerExcluding returns next proposer as if the given validator were unavailable, stored set is left untouched
+func (k *Keeper) GetNextProposerExcluding(ctx sdk.Context, valID hmTypes.ValidatorID) (*hmTypes.Validator, error) {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+	if validatorSet.IsNilOrEmpty() {
+		return nil, errors.New("empty validator set")
+	}
+
+	copiedValidatorSet := validatorSet.Copy()
+
+	// remove excluded validator from copy, zero power marks removal
+	for _, validator := range copiedValidatorSet.Validators {
+		if validator.ID == valID {
+			excluded := validator.Copy()
+			excluded.VotingPower = 0
+
+			if err := copiedValidatorSet.UpdateWithChangeSet([]*hmTypes.Validator{excluded}); err != nil {
+				return nil, fmt.Errorf("unable to exclude validator %v: %v", valID, err)
+			}
+
+			break
+		}
+	}
+
+	// Increment accum in copy
+	copiedValidatorSet.IncrementProposerPriority(1)
+
+	return copiedValidatorSet.GetProposer(), nil
+}
+
+// GetCurrentProposer returns current proposer
+func (k *Keeper) GetCurrentProposer(ctx sdk.Context) *hmTypes.Validator {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+
+	// return get proposer
+	return k.getMemberProposer(ctx, &validatorSet)
+}
+
+// getMemberProposer returns the recorded proposer of the set. A proposer that is no longer a member,
+// e.g. after a membership change, is replaced by the member with the highest proposer priority.
+func (k *Keeper) getMemberProposer(ctx sdk.Context, validatorSet *hmTypes.ValidatorSet) *hmTypes.Validator {
+	proposer := validatorSet.GetProposer()
+	if proposer == nil || validatorSet.HasAddress(proposer.Signer.Bytes()) {
+		return proposer
+	}
+
+	k.Logger(ctx).Error("Recorded proposer is not in validator set, reselecting", "proposer", proposer.Signer.String(), "validatorID", proposer.ID)
+	validatorSet.Proposer = nil
+	return validatorSet.GetProposer()
+}
+
+// GetValidatorSetSnapshot returns current validator set, proposer, total power and epoch from a single read of the set
+func (k *Keeper) GetValidatorSetSnapshot(ctx sdk.Context) types.ValidatorSetSnapshot {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+	proposer := k.getMemberProposer(ctx, &validatorSet)
+
+	return types.ValidatorSetSnapshot{
+		ValidatorSet: validatorSet,
+		Proposer:     proposer,
+		TotalPower:   validatorSet.TotalVotingPower(),
+		Epoch:        k.moduleCommunicator.GetACKCount(ctx),
+	}
+}
+
+// SetValidatorIDToSignerAddr sets mapping for validator ID to signer address
+func (k *Keeper) SetValidatorIDToSignerAddr(ctx sdk.Context, valID hmTypes.ValidatorID, signerAddr hmTypes.HeimdallAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetValidatorMapKey(valID.Bytes()), signerAddr.Bytes())
+}
+
+// SafeSetValidatorIDToSignerAddr sets mapping for validator ID to signer address.
+// It returns an error if the ID is already mapped to a different signer; use
+// SetValidatorIDToSignerAddr to explicitly overwrite the mapping on signer change.
+func (k *Keeper) SafeSetValidatorIDToSignerAddr(ctx sdk.Context, valID hmTypes.ValidatorID, signerAddr hmTypes.HeimdallAddress) error {
+	if mappedSigner, ok := k.GetSignerFromValidatorID(ctx, valID); ok && !bytes.Equal(mappedSigner.Bytes(), signerAddr.Bytes()) {
+		return fmt.Errorf("validator ID %v already mapped to signer %v", valID, mappedSigner.Hex())
+	}
+
+	k.SetValidatorIDToSignerAddr(ctx, valID, signerAddr)
+	return nil
+}
+
+// GetSignerFromValidatorID get signer address from validator ID
+func (k *Keeper) GetSignerFromValidatorID(ctx sdk.Context, valID hmTypes.ValidatorID) (common.Address, bool) {
+	store := ctx.KVStore(k.storeKey)
+	key := GetValidatorMapKey(valID.Bytes())
+	// check if validator address has been mapped
+	if !store.Has(key) {
+		return helper.ZeroAddress, false
+	}
+	// return address from bytes
+	return common.BytesToAddress(store.Get(key)), true
+}
+
+// GetAllValidatorIDs returns all mapped validator IDs in ascending order
+func (k *Keeper) GetAllValidatorIDs(ctx sdk.Context) (valIDs []hmTypes.ValidatorID) {
+	store := ctx.KVStore(k.storeKey)
+
+	// get validator map iterator
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorMapKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		id, err := strconv.ParseUint(string(iterator.Key()[len(ValidatorMapKey):]), 10, 64)
+		if err != nil {
+			k.Logger(ctx).Error("Invalid validator ID in validator map", "key", iterator.Key(), "error", err)
+			continue
+		}
+
+		valIDs = append(valIDs, hmTypes.NewValidatorID(id))
+	}
+
+	// keys are decimal strings, sort numerically
+	sort.Slice(valIDs, func(i, j int) bool {
+		return valIDs[i] < valIDs[j]
+	})
+
+	return valIDs
+}
+
+// GetValidatorByIDDetailed returns validator for validator ID, along with the reason when it is not found
+func (k *Keeper) GetValidatorByIDDetailed(ctx sdk.Context, valID hmTypes.ValidatorID) (validator hmTypes.Validator, found bool, reason types.ValidatorLookupReason) {
+	signerAddr, ok := k.GetSignerFromValidatorID(ctx, valID)
+	if !ok {
+		return validator, false, types.ValidatorLookupIDUnmapped
+	}
+
+	// query for validator signer address
+	validator, err := k.GetValidatorByEthAddress(ctx, signerAddr)
+	if err != nil {
+		return validator, false, types.ValidatorLookupValidatorMissing
+	}
+
+	return validator, true, types.ValidatorLookupFound
+}
+
+// GetValidatorFromValID returns signer from validator ID, falling back to the archive for pruned validators
+func (k *Keeper) GetValidatorFromValID(ctx sdk.Context, valID hmTypes.ValidatorID) (validator hmTypes.Validator, ok bool) {
+	signerAddr, ok := k.GetSignerFromValidatorID(ctx, valID)
+	if !ok {
+		return validator, ok
+	}
+	// query for validator signer address
+	validator, err := k.GetValidatorByEthAddress(ctx, signerAddr)
+	if errors.Is(err, ErrValidatorNotFound) {
+		validator, err = k.GetArchivedValidator(ctx, signerAddr.Bytes())
+	}
+	if err != nil {
 		return validator, false
 	}
 	return validator, true
 }
 
+// VerifyValidatorSignature reports whether sig over msgHash was made by the stored pubkey of validator valID.
+// Signatures that can't be recovered don't match, an error is only returned for an unknown validator.
+func (k *Keeper) VerifyValidatorSignature(ctx sdk.Context, valID hmTypes.ValidatorID, msgHash []byte, sig []byte) (bool, error) {
+	validator, ok := k.GetValidatorFromValID(ctx, valID)
+	if !ok {
+		return false, fmt.Errorf("%w: validator %v", ErrValidatorNotFound, valID)
+	}
+
+	pubkey, err := ethCrypto.RecoverPubkey(msgHash, sig)
+	if err != nil {
+		return false, nil
+	}
+
+	return bytes.Equal(pubkey, validator.PubKey.Bytes()), nil
+}
+
+// GetValidatorByEthAddress returns validator for the given ethereum signer address
+func (k *Keeper) GetValidatorByEthAddress(ctx sdk.Context, address common.Address) (validator hmTypes.Validator, err error) {
+	signer, err := hmTypes.EthAddressToHeimdallAddress(address)
+	if err != nil {
+		return validator, err
+	}
+
+	return k.GetValidatorInfo(ctx, signer.Bytes())
+}
+
+// SetLastSigned records the last block height signed by validator
+func (k *Keeper) SetLastSigned(ctx sdk.Context, valID hmTypes.ValidatorID, height int64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetLastSignedKey(valID), sdk.Uint64ToBigEndian(uint64(height)))
+}
+
+// UpdateLastSigned records a block height signed by validator when the recorded one is LastSignedInterval
+// blocks older or missing, it reports whether the height was written
+func (k *Keeper) UpdateLastSigned(ctx sdk.Context, valID hmTypes.ValidatorID, height int64) bool {
+	if lastSigned, found := k.GetLastSigned(ctx, valID); found && height-lastSigned < LastSignedInterval {
+		return false
+	}
+
+	k.SetLastSigned(ctx, valID, height)
+	return true
+}
+
+// GetLastSigned returns the last block height signed by validator
+func (k *Keeper) GetLastSigned(ctx sdk.Context, valID hmTypes.ValidatorID) (height int64, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetLastSignedKey(valID))
+	if len(bz) != 8 {
+		return 0, false
+	}
+
+	return int64(binary.BigEndian.Uint64(bz)), true
+}
+
+// GetInactiveValidators returns current validators which have not signed within the last window blocks,
+// including those never seen signing. Recorded heights lag by up to LastSignedInterval blocks, so a
+// validator is reported once its recorded height is window plus that interval old.
+func (k *Keeper) GetInactiveValidators(ctx sdk.Context, window int64) (validators []hmTypes.Validator) {
+	for _, validator := range k.GetValidatorSet(ctx).Validators {
+		lastSigned, found := k.GetLastSigned(ctx, validator.ID)
+		if !found || ctx.BlockHeight()-lastSigned >= window+LastSignedInterval {
+			validators = append(validators, *validator)
+		}
+	}
+
+	return validators
+}
+
 // GetLastUpdated get last updated at for validator
 func (k *Keeper) GetLastUpdated(ctx sdk.Context, valID hmTypes.ValidatorID) (updatedAt string, found bool) {
 	// get validator
@@ -392,6 +1624,63 @@ func (k *Keeper) GetLastUpdated(ctx sdk.Context, valID hmTypes.ValidatorID) (upd
 	return validator.LastUpdated, true
 }
 
+// GetValidatorNearestUpdate returns the validator record valid at targetHeight, the one with the
+// latest LastUpdated at or before it. Records of replaced signers, in store or archive, serve as
+// history, only the last update of each signer is kept. latest reports whether the selected record
+// is the validator's current one.
+func (k *Keeper) GetValidatorNearestUpdate(ctx sdk.Context, valID hmTypes.ValidatorID, targetHeight uint64) (validator hmTypes.Validator, latest bool, err error) {
+	current, ok := k.GetValidatorFromValID(ctx, valID)
+	if !ok {
+		return validator, false, ErrValidatorNotFound
+	}
+
+	var history []hmTypes.Validator
+	k.IterateValidatorsAndApplyFn(ctx, func(v hmTypes.Validator) error {
+		if v.ID == valID {
+			history = append(history, v)
+		}
+		return nil
+	})
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, ArchivedValidatorsKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		v, err := hmTypes.UnmarshallValidator(k.cdc, iterator.Value())
+		if err == nil && v.ID == valID {
+			history = append(history, v)
+		}
+	}
+
+	var (
+		found      bool
+		bestHeight uint64
+	)
+	for _, v := range history {
+		sequence, ok := new(big.Int).SetString(v.LastUpdated, 10)
+		if !ok {
+			continue
+		}
+
+		height := new(big.Int).Div(sequence, big.NewInt(hmTypes.DefaultLogIndexUnit)).Uint64()
+		if height > targetHeight {
+			continue
+		}
+
+		// a signer update writes old and new records with the same sequence, the new one keeps the power
+		if !found || height > bestHeight || (height == bestHeight && validator.VotingPower == 0 && v.VotingPower > 0) {
+			validator, bestHeight, found = v, height, true
+		}
+	}
+
+	if !found {
+		return validator, false, fmt.Errorf("No validator record at or before height %d", targetHeight)
+	}
+
+	return validator, validator.Signer.Equals(current.Signer), nil
+}
+
 // IterateCurrentValidatorsAndApplyFn iterate through current validators
 func (k *Keeper) IterateCurrentValidatorsAndApplyFn(ctx sdk.Context, f func(validator *hmTypes.Validator) bool) {
 	currentValidatorSet := k.GetValidatorSet(ctx)
@@ -447,6 +1736,64 @@ func (k *Keeper) IterateStakingSequencesAndApplyFn(ctx sdk.Context, f func(seque
 	}
 }
 
+// ValidateOnboarding checks a joining validator before anything is written: the validator must be valid,
+// its signer the consensus address derived from the pubkey, and neither its ID nor its signer known.
+func (k *Keeper) ValidateOnboarding(ctx sdk.Context, validator hmTypes.Validator) error {
+	if !validator.ValidateBasic() {
+		return fmt.Errorf("invalid validator %v", validator.ID)
+	}
+
+	// consensus address must match signer
+	if !bytes.Equal(validator.PubKey.Address().Bytes(), validator.Signer.Bytes()) {
+		return fmt.Errorf("signer %v doesn't match pubkey of validator %v", validator.Signer.String(), validator.ID)
+	}
+
+	if k.HasValidatorID(ctx, validator.ID) || k.HasValidator(ctx, validator.Signer.Bytes()) || k.IsArchivedValidator(ctx, validator.Signer.Bytes()) {
+		return fmt.Errorf("%w: validator %v", ErrValidatorExists, validator.ID)
+	}
+
+	return nil
+}
+
+// OnboardValidator is the canonical path for a joining validator. It checks the validator with ValidateOnboarding,
+// stamps LastUpdated with the staking sequence, stores the record, its indexes and signing info, and emits
+// the join event with the caller's attributes, e.g. of the join tx. The signer is the consensus address derived
+// from the pubkey, so the signer keyed record doubles as the consensus address index.
+func (k *Keeper) OnboardValidator(ctx sdk.Context, validator hmTypes.Validator, sequence string, attributes ...sdk.Attribute) error {
+	if err := k.ValidateOnboarding(ctx, validator); err != nil {
+		return err
+	}
+
+	// update last updated
+	validator.LastUpdated = sequence
+
+	// store record with signer and ID indexes
+	if err := k.AddValidator(ctx, validator); err != nil {
+		return err
+	}
+
+	// Add Validator signing info. It is required for slashing module
+	valSigningInfo := hmTypes.NewValidatorSigningInfo(validator.ID, ctx.BlockHeight(), int64(0), int64(0))
+	if err := k.AddValidatorSigningInfo(ctx, validator.ID, valSigningInfo); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidatorSigningInfo, err)
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeValidatorJoin,
+			append(
+				attributes,
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+				sdk.NewAttribute(types.AttributeKeyValidatorID, strconv.FormatUint(validator.ID.Uint64(), 10)),
+				sdk.NewAttribute(types.AttributeKeySigner, validator.Signer.String()),
+				sdk.NewAttribute(types.AttributeKeyValidatorNonce, strconv.FormatUint(validator.Nonce, 10)),
+			)...,
+		),
+	)
+
+	return nil
+}
+
 // Slashing api's
 // AddValidatorSigningInfo creates a signing info for validator
 func (k *Keeper) AddValidatorSigningInfo(ctx sdk.Context, valID hmTypes.ValidatorID, valSigningInfo hmTypes.ValidatorSigningInfo) error {
@@ -515,6 +1862,16 @@ func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
 
 // GetParams gets the auth module's parameters.
 func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
-	k.paramSpace.GetParamSet(ctx, &params)
+	k.paramSpace.Get(ctx, types.KeyStakingBufferTime, &params.StakingBufferTime)
+
+	// params below are absent on chains started before they were introduced
+	k.paramSpace.GetIfExists(ctx, types.KeyEnableValidatorPruning, &params.EnableValidatorPruning)
+	k.paramSpace.GetIfExists(ctx, types.KeyValidatorPruneGracePeriod, &params.ValidatorPruneGracePeriod)
+	k.paramSpace.GetIfExists(ctx, types.KeyValidatorSetHistoryRetention, &params.ValidatorSetHistoryRetention)
+	k.paramSpace.GetIfExists(ctx, types.KeyMaxPowerSharePercent, &params.MaxPowerSharePercent)
+	k.paramSpace.GetIfExists(ctx, types.KeyValidatorGracePeriod, &params.ValidatorGracePeriod)
+	k.paramSpace.GetIfExists(ctx, types.KeyPowerDivisor, &params.PowerDivisor)
+	k.paramSpace.GetIfExists(ctx, types.KeyMinValidatorPower, &params.MinValidatorPower)
+
 	return
 }
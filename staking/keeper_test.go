@@ -1,14 +1,22 @@
 package staking_test
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"math/big"
 	"math/rand"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/maticnetwork/heimdall/staking"
 	stakingTypes "github.com/maticnetwork/heimdall/staking/types"
 
+	storeTypes "github.com/cosmos/cosmos-sdk/store/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/maticnetwork/heimdall/app"
 
 	"github.com/maticnetwork/heimdall/helper"
@@ -19,8 +27,10 @@ import (
 	"github.com/maticnetwork/heimdall/types"
 	hmTypes "github.com/maticnetwork/heimdall/types"
 	"github.com/maticnetwork/heimdall/types/simulation"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
@@ -246,7 +256,7 @@ func (suite *KeeperTestSuite) TestAddValidatorSetChange() {
 	chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
 	initValSet := keeper.GetValidatorSet(ctx)
 
-	validators := stakingSim.GenRandomVal(1, 0, 10, 10, false, 1)
+	validators := stakingSim.GenRandomVal(1, 0, 10, 10, false, 5)
 	prevValSet := initValSet.Copy()
 
 	valToBeAdded := validators[0]
@@ -263,6 +273,261 @@ func (suite *KeeperTestSuite) TestAddValidatorSetChange() {
 
 }
 
+func (suite *KeeperTestSuite) TestSwapSigner() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	store := ctx.KVStore(app.GetKey(stakingTypes.StoreKey))
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+	oldSigner := validators[0].Signer
+
+	newPubKey := hmTypes.NewPubKey(secp256k1.GenPrivKey().PubKey().Bytes())
+	newSigner := hmTypes.HexToHeimdallAddress(newPubKey.Address().String())
+
+	// precondition failures
+	require.ErrorIs(t, keeper.SwapSigner(ctx, 100, newSigner, newPubKey), staking.ErrValidatorNotFound)
+	require.Error(t, keeper.SwapSigner(ctx, validators[0].ID, oldSigner, validators[0].PubKey))
+	require.Error(t, keeper.SwapSigner(ctx, validators[0].ID, validators[1].Signer, validators[1].PubKey))
+	require.Error(t, keeper.SwapSigner(ctx, validators[0].ID, hmTypes.HeimdallAddress{}, newPubKey))
+
+	// record move fails after checks, e.g. on a stored record AddValidator rejects
+	invalid := validators[0]
+	invalid.CommissionRate = hmTypes.MaxCommissionRate + 1
+	bz, err := hmTypes.MarshallValidator(app.Codec(), invalid)
+	require.NoError(t, err)
+	store.Set(staking.GetValidatorKey(oldSigner.Bytes()), bz)
+
+	require.Error(t, keeper.SwapSigner(ctx, validators[0].ID, newSigner, newPubKey))
+
+	// original state intact
+	require.False(t, keeper.HasValidator(ctx, newSigner.Bytes()))
+	mapped, ok := keeper.GetSignerFromValidatorID(ctx, validators[0].ID)
+	require.True(t, ok)
+	require.Equal(t, oldSigner.EthAddress(), mapped)
+	require.Equal(t, bz, store.Get(staking.GetValidatorKey(oldSigner.Bytes())))
+
+	// successful swap
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+	require.NoError(t, keeper.SwapSigner(ctx, validators[0].ID, newSigner, newPubKey))
+
+	old, err := keeper.GetValidatorInfo(ctx, oldSigner.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), old.VotingPower)
+
+	swapped, ok := keeper.GetValidatorFromValID(ctx, validators[0].ID)
+	require.True(t, ok)
+	require.Equal(t, newSigner, swapped.Signer)
+	require.Equal(t, newPubKey, swapped.PubKey)
+	require.Equal(t, validators[0].VotingPower, swapped.VotingPower)
+}
+
+func (suite *KeeperTestSuite) TestUpdateSignerPubKeyInUse() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+
+	// rotating to the pubkey of another validator is rejected
+	err := keeper.UpdateSigner(ctx, validators[1].Signer, validators[1].PubKey, validators[0].Signer)
+	require.ErrorIs(t, err, staking.ErrPubKeyInUse)
+
+	other, err := keeper.GetValidatorInfo(ctx, validators[1].Signer.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, validators[1].ID, other.ID)
+	require.Equal(t, validators[1].VotingPower, other.VotingPower)
+
+	current, ok := keeper.GetValidatorFromValID(ctx, validators[0].ID)
+	require.True(t, ok)
+	require.Equal(t, validators[0].Signer, current.Signer)
+
+	// signer must be the address of the new pubkey
+	newPubKey := hmTypes.NewPubKey(secp256k1.GenPrivKey().PubKey().Bytes())
+	require.Error(t, keeper.UpdateSigner(ctx, validators[1].Signer, newPubKey, validators[0].Signer))
+
+	// unused pubkey is accepted, rotating back to the previous one is not
+	newSigner := hmTypes.HexToHeimdallAddress(newPubKey.Address().String())
+	require.NoError(t, keeper.UpdateSigner(ctx, newSigner, newPubKey, validators[0].Signer))
+	require.Error(t, keeper.UpdateSigner(ctx, validators[0].Signer, validators[0].PubKey, newSigner))
+}
+
+func (suite *KeeperTestSuite) TestVerifyValidatorSignature() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	privKey := secp256k1.GenPrivKey()
+	ecdsaKey, err := ethCrypto.ToECDSA(privKey[:])
+	require.NoError(t, err)
+	pubkey := hmTypes.NewPubKey(ethCrypto.FromECDSAPub(&ecdsaKey.PublicKey))
+	validator := hmTypes.NewValidator(1, 0, 0, 1, 10, pubkey, hmTypes.HexToHeimdallAddress(pubkey.Address().String()))
+	require.NoError(t, keeper.AddValidator(ctx, *validator))
+
+	msgHash := ethCrypto.Keccak256([]byte("checkpoint"))
+	sig, err := ethCrypto.Sign(msgHash, ecdsaKey)
+	require.NoError(t, err)
+
+	// valid signature
+	ok, err := keeper.VerifyValidatorSignature(ctx, validator.ID, msgHash, sig)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// signature over another message
+	ok, err = keeper.VerifyValidatorSignature(ctx, validator.ID, ethCrypto.Keccak256([]byte("other")), sig)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// signature by another key
+	otherKey, err := ethCrypto.GenerateKey()
+	require.NoError(t, err)
+	otherSig, err := ethCrypto.Sign(msgHash, otherKey)
+	require.NoError(t, err)
+	ok, err = keeper.VerifyValidatorSignature(ctx, validator.ID, msgHash, otherSig)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// malformed signature
+	ok, err = keeper.VerifyValidatorSignature(ctx, validator.ID, msgHash, sig[:10])
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// missing validator
+	ok, err = keeper.VerifyValidatorSignature(ctx, 2, msgHash, sig)
+	require.ErrorIs(t, err, staking.ErrValidatorNotFound)
+	require.False(t, ok)
+}
+
+func (suite *KeeperTestSuite) TestComputeSetTransition() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
+	currentSet := keeper.GetValidatorSet(ctx)
+
+	validators := make([]hmTypes.Validator, 0, len(currentSet.Validators))
+	for _, v := range currentSet.Validators {
+		validators = append(validators, *v.Copy())
+	}
+	sort.Slice(validators, func(i, j int) bool { return validators[i].ID < validators[j].ID })
+
+	// drop the first, raise power of the second, rotate signer of the third, keep the fourth and add a new one
+	rotated := stakingSim.GenRandomVal(2, 0, 10, 10, false, 0)
+	raised := validators[1]
+	raised.VotingPower += 5
+	rotatedVal := validators[2]
+	rotatedVal.Signer = rotated[0].Signer
+	rotatedVal.PubKey = rotated[0].PubKey
+	newVal := rotated[1]
+	newVal.ID = 100
+
+	targetValidators := []*hmTypes.Validator{&raised, &rotatedVal, &validators[3], &newVal}
+	target := hmTypes.NewValidatorSet(targetValidators)
+
+	ops, err := keeper.ComputeSetTransition(ctx, *target)
+	require.NoError(t, err)
+
+	summary := make([]string, 0, len(ops))
+	for _, op := range ops {
+		summary = append(summary, fmt.Sprintf("%s:%v", op.Type, op.Validator.ID))
+	}
+	require.Equal(t, []string{
+		fmt.Sprintf("remove:%v", validators[0].ID),
+		fmt.Sprintf("remove:%v", validators[2].ID),
+		fmt.Sprintf("update:%v", validators[1].ID),
+		fmt.Sprintf("add:%v", validators[2].ID),
+		"add:100",
+	}, summary)
+
+	// applied operations yield the target
+	require.NoError(t, currentSet.UpdateWithChangeSet(stakingTypes.ChangeSet(ops)))
+	require.Len(t, currentSet.Validators, len(targetValidators))
+	for _, v := range targetValidators {
+		_, applied := currentSet.GetByAddress(v.Signer.Bytes())
+		require.NotNil(t, applied)
+		require.Equal(t, v.ID, applied.ID)
+		require.Equal(t, v.VotingPower, applied.VotingPower)
+	}
+
+	// nothing left to do once current set matches target
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, currentSet))
+	ops, err = keeper.ComputeSetTransition(ctx, *target)
+	require.NoError(t, err)
+	require.Empty(t, ops)
+
+	// duplicate IDs in the target are rejected
+	duplicate := newVal
+	duplicate.Signer = rotated[0].Signer
+	_, err = keeper.ComputeSetTransition(ctx, hmTypes.ValidatorSet{Validators: []*hmTypes.Validator{&newVal, &duplicate}})
+	require.Error(t, err)
+}
+
+func (suite *KeeperTestSuite) TestGetPowerDistribution() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	// no validators, no power
+	require.Empty(t, keeper.GetPowerDistribution(ctx))
+	_, err := keeper.GetValidatorPowerShare(ctx, 1)
+	require.ErrorIs(t, err, staking.ErrValidatorNotFound)
+
+	validators := stakingSim.GenRandomVal(3, 0, 10, 10, false, 1)
+	powers := []int64{10, 20, 70}
+	validatorPointers := make([]*hmTypes.Validator, 0, len(validators))
+	for i := range validators {
+		validators[i].VotingPower = powers[i]
+		require.NoError(t, keeper.AddValidator(ctx, validators[i]))
+		validatorPointers = append(validatorPointers, &validators[i])
+	}
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, *hmTypes.NewValidatorSet(validatorPointers)))
+
+	distribution := keeper.GetPowerDistribution(ctx)
+	require.Len(t, distribution, len(validators))
+
+	var sum float64
+	for _, validator := range validators {
+		share, err := keeper.GetValidatorPowerShare(ctx, validator.ID)
+		require.NoError(t, err)
+		require.InDelta(t, float64(validator.VotingPower)/100, share, 1e-9)
+		require.Equal(t, share, distribution[validator.ID])
+		sum += share
+	}
+	require.InDelta(t, 1, sum, 1e-9)
+
+	// validator outside the current set
+	_, err = keeper.GetValidatorPowerShare(ctx, 4)
+	require.ErrorIs(t, err, staking.ErrValidatorNotFound)
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorsExitingBefore() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	// end epochs by ID, 0 is not exiting
+	endEpochs := []uint64{30, 0, 10, 20, 10, 40}
+	validators := stakingSim.GenRandomVal(len(endEpochs), 0, 10, 10, false, 1)
+	for i := range validators {
+		validators[i].EndEpoch = endEpochs[i]
+		require.NoError(t, keeper.AddValidator(ctx, validators[i]))
+	}
+
+	exitingIDs := func(epoch uint64) []hmTypes.ValidatorID {
+		var ids []hmTypes.ValidatorID
+		for _, validator := range keeper.GetValidatorsExitingBefore(ctx, epoch) {
+			ids = append(ids, validator.ID)
+		}
+		return ids
+	}
+
+	require.Empty(t, exitingIDs(5))
+	require.Equal(t, []hmTypes.ValidatorID{3, 5}, exitingIDs(10))
+	require.Equal(t, []hmTypes.ValidatorID{3, 5, 4}, exitingIDs(25))
+	require.Equal(t, []hmTypes.ValidatorID{3, 5, 4, 1, 6}, exitingIDs(100))
+}
+
 func (suite *KeeperTestSuite) TestUpdateValidatorSetChange() {
 	// create sub test to check if validator remove
 	t, app, ctx := suite.T(), suite.app, suite.ctx
@@ -324,6 +589,33 @@ func (suite *KeeperTestSuite) TestGetCurrentProposer() {
 	require.Equal(t, currentValSet.GetProposer(), currentProposer)
 }
 
+func (suite *KeeperTestSuite) TestGetCurrentProposerNotMember() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
+
+	// proposer recorded before it was removed from the set
+	validatorSet := keeper.GetValidatorSet(ctx)
+	removed := stakingSim.GenRandomVal(1, 0, 10, 10, false, 10)[0]
+	validatorSet.Proposer = &removed
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, validatorSet))
+
+	validatorSet.Proposer = nil
+	expected := validatorSet.GetProposer()
+
+	// highest priority member is returned, the same on every read
+	for i := 0; i < 2; i++ {
+		proposer := keeper.GetCurrentProposer(ctx)
+		require.NotNil(t, proposer)
+		require.True(t, validatorSet.HasAddress(proposer.Signer.Bytes()))
+		require.Equal(t, expected, proposer)
+	}
+
+	snapshot := keeper.GetValidatorSetSnapshot(ctx)
+	require.Equal(t, expected, snapshot.Proposer)
+	require.Equal(t, expected, snapshot.ValidatorSet.Proposer)
+}
+
 func (suite *KeeperTestSuite) TestGetNextProposer() {
 	t, app, ctx := suite.T(), suite.app, suite.ctx
 	keeper := app.StakingKeeper
@@ -333,6 +625,38 @@ func (suite *KeeperTestSuite) TestGetNextProposer() {
 	require.NotNil(t, nextProposer)
 }
 
+func (suite *KeeperTestSuite) TestGetNextProposerExcluding() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	// excluding the only validator leaves an empty set
+	validatorSet := chSim.LoadValidatorSet(1, t, keeper, ctx, false, 10)
+	_, err := keeper.GetNextProposerExcluding(ctx, validatorSet.Validators[0].ID)
+	require.Error(t, err)
+
+	app, ctx, _ = createTestApp(false)
+	keeper = app.StakingKeeper
+	validatorSet = chSim.LoadValidatorSet(2, t, keeper, ctx, false, 10)
+	storedSet := keeper.GetValidatorSet(ctx)
+
+	// excluding the would-be proposer returns the next in line
+	wouldBe := keeper.GetNextProposer(ctx)
+	nextInLine, err := keeper.GetNextProposerExcluding(ctx, wouldBe.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, wouldBe.ID, nextInLine.ID)
+	require.True(t, validatorSet.HasAddress(nextInLine.Signer.Bytes()))
+
+	// excluding the other validator leaves the would-be proposer
+	result, err := keeper.GetNextProposerExcluding(ctx, nextInLine.ID)
+	require.NoError(t, err)
+	require.Equal(t, wouldBe.ID, result.ID)
+
+	// stored set is untouched
+	currentSet := keeper.GetValidatorSet(ctx)
+	require.True(t, storedSet.Equal(&currentSet))
+	require.Equal(t, storedSet.Proposer, currentSet.Proposer)
+}
+
 func (suite *KeeperTestSuite) TestGetValidatorFromValID() {
 	t, app, ctx := suite.T(), suite.app, suite.ctx
 	keeper := app.StakingKeeper
@@ -355,6 +679,106 @@ func (suite *KeeperTestSuite) TestGetLastUpdated() {
 	require.Equal(t, validators[0].LastUpdated, lastUpdated)
 }
 
+func (suite *KeeperTestSuite) TestGetValidatorNearestUpdate() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	sequence := func(height int64) string {
+		return big.NewInt(height * hmTypes.DefaultLogIndexUnit).String()
+	}
+
+	validators := stakingSim.GenRandomVal(1, 0, 10, 10, false, 1)
+	valID := validators[0].ID
+	firstSigner := validators[0].Signer
+	validators[0].LastUpdated = sequence(100)
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+
+	// signer update at 200 leaves the first signer record as history
+	newSigner := func() (hmTypes.PubKey, hmTypes.HeimdallAddress) {
+		pubKey := hmTypes.NewPubKey(secp256k1.GenPrivKey().PubKey().Bytes())
+		return pubKey, hmTypes.HexToHeimdallAddress(pubKey.Address().String())
+	}
+	secondPubKey, secondSigner := newSigner()
+	require.NoError(t, keeper.UpdateSigner(ctx, secondSigner, secondPubKey, firstSigner))
+	second, ok := keeper.GetValidatorFromValID(ctx, valID)
+	require.True(t, ok)
+	second.LastUpdated = sequence(200)
+	require.NoError(t, keeper.AddValidator(ctx, second))
+
+	// another signer update leaves old and new records with the same sequence
+	thirdPubKey, thirdSigner := newSigner()
+	require.NoError(t, keeper.UpdateSigner(ctx, thirdSigner, thirdPubKey, secondSigner))
+
+	_, _, err := keeper.GetValidatorNearestUpdate(ctx, valID, 50)
+	require.Error(t, err)
+
+	validator, latest, err := keeper.GetValidatorNearestUpdate(ctx, valID, 150)
+	require.NoError(t, err)
+	require.False(t, latest)
+	require.Equal(t, firstSigner, validator.Signer)
+
+	validator, latest, err = keeper.GetValidatorNearestUpdate(ctx, valID, 250)
+	require.NoError(t, err)
+	require.True(t, latest)
+	require.Equal(t, thirdSigner, validator.Signer)
+	require.Equal(t, validators[0].VotingPower, validator.VotingPower)
+
+	_, _, err = keeper.GetValidatorNearestUpdate(ctx, valID+1, 300)
+	require.Error(t, err)
+}
+
+func (suite *KeeperTestSuite) TestLastSigned() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	chSim.LoadValidatorSet(3, t, keeper, ctx, false, 10)
+	validators := keeper.GetValidatorSet(ctx).Validators
+
+	_, found := keeper.GetLastSigned(ctx, validators[0].ID)
+	require.False(t, found)
+
+	keeper.SetLastSigned(ctx, validators[0].ID, 90)
+	height, found := keeper.GetLastSigned(ctx, validators[0].ID)
+	require.True(t, found)
+	require.Equal(t, int64(90), height)
+
+	// begin block records signers of the previous block
+	ctx = ctx.WithBlockHeight(100)
+	beginBlock := func(ctx sdk.Context) {
+		staking.BeginBlocker(ctx, abci.RequestBeginBlock{
+			LastCommitInfo: abci.LastCommitInfo{Votes: []abci.VoteInfo{
+				{Validator: abci.Validator{Address: validators[0].Signer.Bytes()}, SignedLastBlock: true},
+				{Validator: abci.Validator{Address: validators[1].Signer.Bytes()}, SignedLastBlock: true},
+				{Validator: abci.Validator{Address: validators[2].Signer.Bytes()}, SignedLastBlock: false},
+			}},
+		}, keeper)
+	}
+	beginBlock(ctx)
+	height, found = keeper.GetLastSigned(ctx, validators[1].ID)
+	require.True(t, found)
+	require.Equal(t, int64(99), height)
+	_, found = keeper.GetLastSigned(ctx, validators[2].ID)
+	require.False(t, found)
+
+	// recorded heights are only rewritten once they are an interval old
+	height, _ = keeper.GetLastSigned(ctx, validators[0].ID)
+	require.Equal(t, int64(90), height)
+
+	ctx = ctx.WithBlockHeight(99 + staking.LastSignedInterval)
+	beginBlock(ctx)
+	height, _ = keeper.GetLastSigned(ctx, validators[0].ID)
+	require.Equal(t, 98+staking.LastSignedInterval, height)
+	height, _ = keeper.GetLastSigned(ctx, validators[1].ID)
+	require.Equal(t, int64(99), height)
+
+	// first signed just now, second a full interval ago, third never
+	inactive := keeper.GetInactiveValidators(ctx, 0)
+	require.Len(t, inactive, 2)
+	require.ElementsMatch(t, []hmTypes.ValidatorID{validators[1].ID, validators[2].ID}, []hmTypes.ValidatorID{inactive[0].ID, inactive[1].ID})
+
+	inactive = keeper.GetInactiveValidators(ctx, 2)
+	require.Len(t, inactive, 1)
+	require.Equal(t, validators[2].ID, inactive[0].ID)
+}
+
 func (suite *KeeperTestSuite) TestGetSpanEligibleValidators() {
 	t, app, ctx := suite.T(), suite.app, suite.ctx
 	keeper := app.StakingKeeper
@@ -405,3 +829,1736 @@ func (suite *KeeperTestSuite) TestUpdateStakingSync() {
 	fmt.Println(stakingBufferTime)
 	require.Equal(t, result.TimeStamp >= now && result.TimeStamp-now < stakingBufferTime, true)
 }
+
+func (suite *KeeperTestSuite) TestGetValidatorInfoErrors() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+
+	// absent
+	_, err := keeper.GetValidatorInfo(ctx, validators[1].Signer.Bytes())
+	require.True(t, errors.Is(err, staking.ErrValidatorNotFound))
+	require.False(t, errors.Is(err, staking.ErrValidatorDecode))
+
+	// corrupted
+	store := ctx.KVStore(app.GetKey(stakingTypes.StoreKey))
+	store.Set(staking.GetValidatorKey(validators[0].Signer.Bytes()), []byte{0xff, 0xff})
+	_, err = keeper.GetValidatorInfo(ctx, validators[0].Signer.Bytes())
+	require.True(t, errors.Is(err, staking.ErrValidatorDecode))
+	require.False(t, errors.Is(err, staking.ErrValidatorNotFound))
+
+	// decode failure is not masked by the archive fallback
+	_, _, err = keeper.ResolveValidatorInfo(ctx, validators[0].Signer.Bytes())
+	require.True(t, errors.Is(err, staking.ErrValidatorDecode))
+}
+
+func (suite *KeeperTestSuite) TestResolveValidatorInfo() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(1, 0, 10, 10, false, 1)
+	oldSigner := validators[0].Signer
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+	keeper.SetValidatorIDToSignerAddr(ctx, validators[0].ID, oldSigner)
+
+	// current signer is not stale
+	validator, stale, err := keeper.ResolveValidatorInfo(ctx, oldSigner.Bytes())
+	require.NoError(t, err)
+	require.False(t, stale)
+	require.Equal(t, oldSigner, validator.Signer)
+
+	newPubKey := hmTypes.NewPubKey(secp256k1.GenPrivKey().PubKey().Bytes())
+	newSigner := hmTypes.HexToHeimdallAddress(newPubKey.Address().String())
+	require.NoError(t, keeper.UpdateSigner(ctx, newSigner, newPubKey, oldSigner))
+
+	// old signer resolves to the current record
+	validator, stale, err = keeper.ResolveValidatorInfo(ctx, oldSigner.Bytes())
+	require.NoError(t, err)
+	require.True(t, stale)
+	require.Equal(t, validators[0].ID, validator.ID)
+	require.Equal(t, newSigner, validator.Signer)
+	require.Equal(t, validators[0].VotingPower, validator.VotingPower)
+
+	validator, stale, err = keeper.ResolveValidatorInfo(ctx, newSigner.Bytes())
+	require.NoError(t, err)
+	require.False(t, stale)
+	require.Equal(t, newSigner, validator.Signer)
+
+	// unknown signer
+	_, _, err = keeper.ResolveValidatorInfo(ctx, hmTypes.HexToHeimdallAddress("0x01").Bytes())
+	require.Error(t, err)
+}
+
+func (suite *KeeperTestSuite) TestCheckProposerPriorityBounds() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
+
+	// freshly loaded set is within bounds
+	require.NoError(t, keeper.CheckProposerPriorityBounds(ctx))
+
+	msg, broken := staking.ProposerPriorityBoundsInvariant(keeper)(ctx)
+	require.False(t, broken, msg)
+
+	// push one validator far outside the allowed window
+	valSet := keeper.GetValidatorSet(ctx)
+	bound := (hmTypes.PriorityWindowSizeFactor + 1) * valSet.TotalVotingPower()
+	valSet.Validators[0].ProposerPriority = bound + 1
+	err := keeper.UpdateValidatorSetInStore(ctx, valSet)
+	require.NoError(t, err)
+
+	err = keeper.CheckProposerPriorityBounds(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("%v:%v", valSet.Validators[0].ID, bound+1))
+
+	msg, broken = staking.ProposerPriorityBoundsInvariant(keeper)(ctx)
+	require.True(t, broken, msg)
+}
+
+func (suite *KeeperTestSuite) TestValidatorGracePeriod() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	// current epoch is 21
+	app.CheckpointKeeper.UpdateACKCountWithValue(ctx, 20, hmTypes.RootChainTypeStake)
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	validators[0].StartEpoch, validators[0].EndEpoch = 0, 20 // unstaked one epoch ago
+	validators[1].StartEpoch, validators[1].EndEpoch = 0, 15 // unstaked six epochs ago
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+
+	// hard boundary without grace
+	require.False(t, keeper.IsCurrentValidatorByAddress(ctx, validators[0].Signer.Bytes()))
+	require.False(t, keeper.IsCurrentValidatorByAddress(ctx, validators[1].Signer.Bytes()))
+
+	params := keeper.GetParams(ctx)
+	params.ValidatorGracePeriod = 3
+	keeper.SetParams(ctx, params)
+
+	// within grace is still current, past it is not
+	require.True(t, keeper.IsCurrentValidatorByAddress(ctx, validators[0].Signer.Bytes()))
+	require.False(t, keeper.IsCurrentValidatorByAddress(ctx, validators[1].Signer.Bytes()))
+
+	_, err := keeper.GetActiveValidatorInfo(ctx, validators[0].Signer.Bytes())
+	require.NoError(t, err)
+	_, err = keeper.GetActiveValidatorInfo(ctx, validators[1].Signer.Bytes())
+	require.Error(t, err)
+
+	current := keeper.GetCurrentValidators(ctx)
+	require.Len(t, current, 1)
+	require.Equal(t, validators[0].ID, current[0].ID)
+}
+
+func (suite *KeeperTestSuite) TestParamsRoundTrip() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	params := stakingTypes.DefaultParams()
+	params.StakingBufferTime = 20 * time.Minute
+	params.EnableValidatorPruning = true
+	params.ValidatorPruneGracePeriod = 7
+	params.ValidatorSetHistoryRetention = 100
+	params.MaxPowerSharePercent = 40
+	params.ValidatorGracePeriod = 3
+	params.PowerDivisor = 10
+	params.MinValidatorPower = 5
+	keeper.SetParams(ctx, params)
+
+	// every param survives a read-modify-write cycle
+	read := keeper.GetParams(ctx)
+	require.Equal(t, params, read)
+	keeper.SetParams(ctx, read)
+	require.Equal(t, params, keeper.GetParams(ctx))
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorUpdates() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(4, 0, 10, 10, false, 1)
+	for i := range validators {
+		validators[i].EndEpoch = 0
+		validators[i].VotingPower = 10
+	}
+
+	// validators 0, 1 and 2 are in the current set
+	for _, validator := range validators[:3] {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+	valSet := hmTypes.NewValidatorSet([]*hmTypes.Validator{validators[0].Copy(), validators[1].Copy(), validators[2].Copy()})
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, *valSet))
+
+	updates, err := keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Empty(t, updates)
+	require.True(t, keeper.IsValidatorSetFresh(ctx, app.CheckpointKeeper.GetACKCount(ctx, hmTypes.RootChainTypeStake)))
+
+	// 1 changes power, 2 is removed and 3 is added
+	validators[1].VotingPower = 25
+	require.NoError(t, keeper.AddValidator(ctx, validators[1]))
+	validators[2].Jailed = true
+	require.NoError(t, keeper.AddValidator(ctx, validators[2]))
+	require.NoError(t, keeper.AddValidator(ctx, validators[3]))
+
+	updates, err = keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+
+	powers := make(map[string]int64)
+	for _, update := range updates {
+		powers[string(update.PubKey.Data)] = update.Power
+	}
+	require.Equal(t, map[string]int64{
+		string(validators[1].PubKey.ABCIPubKey().Data): 25,
+		string(validators[2].PubKey.ABCIPubKey().Data): 0,
+		string(validators[3].PubKey.ABCIPubKey().Data): 10,
+	}, powers)
+
+	// stored set reflects the updates
+	stored := keeper.GetValidatorSet(ctx)
+	require.Len(t, stored.Validators, 3)
+	_, removed := stored.GetByAddress(validators[2].Signer.Bytes())
+	require.Nil(t, removed)
+
+	// nothing left to apply
+	updates, err = keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Empty(t, updates)
+}
+
+func (suite *KeeperTestSuite) TestMinValidatorPower() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	params := keeper.GetParams(ctx)
+	params.MinValidatorPower = 5
+	keeper.SetParams(ctx, params)
+
+	validators := stakingSim.GenRandomVal(3, 0, 10, 10, false, 1)
+	for i := range validators {
+		validators[i].EndEpoch = 0
+		validators[i].VotingPower = 10
+	}
+	validators[2].VotingPower = 1 // below threshold
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+
+	currentIDs := func() []hmTypes.ValidatorID {
+		var ids []hmTypes.ValidatorID
+		for _, v := range keeper.GetCurrentValidators(ctx) {
+			ids = append(ids, v.ID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+	expected := []hmTypes.ValidatorID{validators[0].ID, validators[1].ID}
+	sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+	require.Equal(t, expected, currentIDs())
+
+	// the set is built without it
+	updates, err := keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	stored := keeper.GetValidatorSet(ctx)
+	require.Len(t, stored.Validators, 2)
+	_, excluded := stored.GetByAddress(validators[2].Signer.Bytes())
+	require.Nil(t, excluded)
+	require.Equal(t, expected, currentIDs())
+
+	// it stays stored with its power
+	validator, ok := keeper.GetValidatorFromValID(ctx, validators[2].ID)
+	require.True(t, ok)
+	require.Equal(t, int64(1), validator.VotingPower)
+
+	// a member falling below the threshold leaves the set
+	validators[1].VotingPower = 4
+	require.NoError(t, keeper.AddValidator(ctx, validators[1]))
+	updates, err = keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.Zero(t, updates[0].Power)
+	require.Equal(t, []hmTypes.ValidatorID{validators[0].ID}, currentIDs())
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorUpdatesAfterIncrementAccum() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	chSim.LoadValidatorSet(2, t, keeper, ctx, false, 10)
+
+	updates, err := keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Empty(t, updates)
+
+	// validator joins and a checkpoint ack rotates the proposer in the same block
+	joined := stakingSim.GenRandomVal(1, 0, 10, 10, false, 5)[0]
+	joined.EndEpoch = 0
+	require.NoError(t, keeper.AddValidator(ctx, joined))
+	keeper.IncrementAccum(ctx, 1)
+	require.False(t, keeper.IsValidatorSetFresh(ctx, 0))
+
+	updates, err = keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.Equal(t, joined.PubKey.ABCIPubKey(), updates[0].PubKey)
+	require.Equal(t, joined.VotingPower, updates[0].Power)
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorUpdatesParamChange() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	validators[0].EndEpoch, validators[0].VotingPower = 0, 10
+	validators[1].EndEpoch, validators[1].VotingPower = 0, 25
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+	valSet := hmTypes.NewValidatorSet([]*hmTypes.Validator{validators[0].Copy(), validators[1].Copy()})
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, *valSet))
+
+	updates, err := keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Empty(t, updates)
+	require.True(t, keeper.IsValidatorSetFresh(ctx, 0))
+
+	// raising the power floor stales the set without a validator change or ack
+	params := keeper.GetParams(ctx)
+	params.MinValidatorPower = 20
+	keeper.SetParams(ctx, params)
+	require.False(t, keeper.IsValidatorSetFresh(ctx, 0))
+	require.Len(t, keeper.GetCurrentValidators(ctx), 1)
+
+	updates, err = keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []abci.ValidatorUpdate{{PubKey: validators[0].PubKey.ABCIPubKey(), Power: 0}}, updates)
+	require.True(t, keeper.IsValidatorSetFresh(ctx, 0))
+
+	current := keeper.GetCurrentValidators(ctx)
+	require.Len(t, current, 1)
+	require.Equal(t, validators[1].ID, current[0].ID)
+}
+
+func (suite *KeeperTestSuite) TestCheckPowerConcentration() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(3, 0, 10, 10, false, 1)
+	validators[0].VotingPower = 80
+	validators[1].VotingPower = 10
+	validators[2].VotingPower = 10
+
+	valSet := hmTypes.NewValidatorSet([]*hmTypes.Validator{&validators[0], &validators[1], &validators[2]})
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, *valSet))
+
+	// cap disabled by default
+	require.NoError(t, keeper.CheckPowerConcentration(ctx))
+
+	params := keeper.GetParams(ctx)
+	params.MaxPowerSharePercent = 50
+	keeper.SetParams(ctx, params)
+
+	err := keeper.CheckPowerConcentration(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("%v:%v", validators[0].ID, 80))
+	require.NotContains(t, err.Error(), fmt.Sprintf("%v:%v", validators[1].ID, 10))
+
+	msg, broken := staking.PowerConcentrationInvariant(keeper)(ctx)
+	require.True(t, broken, msg)
+
+	// exact share is allowed
+	params.MaxPowerSharePercent = 80
+	keeper.SetParams(ctx, params)
+	require.NoError(t, keeper.CheckPowerConcentration(ctx))
+}
+
+func (suite *KeeperTestSuite) TestCheckSignerUniqueness() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+
+	require.NoError(t, keeper.CheckSignerUniqueness(ctx))
+	msg, broken := staking.SignerUniquenessInvariant(keeper)(ctx)
+	require.False(t, broken, msg)
+
+	// unchecked mapping points another ID at signer of validator 0
+	dupID := hmTypes.NewValidatorID(100)
+	keeper.SetValidatorIDToSignerAddr(ctx, dupID, validators[0].Signer)
+
+	err := keeper.CheckSignerUniqueness(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), validators[0].Signer.EthAddress().Hex())
+	require.Contains(t, err.Error(), validators[0].ID.String())
+	require.Contains(t, err.Error(), dupID.String())
+	require.NotContains(t, err.Error(), validators[1].Signer.EthAddress().Hex())
+
+	msg, broken = staking.SignerUniquenessInvariant(keeper)(ctx)
+	require.True(t, broken, msg)
+}
+
+func (suite *KeeperTestSuite) TestQuorum() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	// no validator set, no quorum
+	require.Equal(t, int64(0), keeper.GetQuorumPower(ctx))
+	require.False(t, keeper.HasQuorum(ctx, nil))
+
+	// nine validators with power 1 each, +2/3 of 9 needs 7
+	validators := stakingSim.GenRandomVal(9, 0, 1, 10, false, 1)
+	valz := make([]*hmTypes.Validator, 0, len(validators))
+	signers := make([]hmTypes.HeimdallAddress, 0, len(validators))
+	for i := range validators {
+		valz = append(valz, &validators[i])
+		signers = append(signers, validators[i].Signer)
+	}
+	err := keeper.UpdateValidatorSetInStore(ctx, *hmTypes.NewValidatorSet(valz))
+	require.NoError(t, err)
+
+	require.Equal(t, int64(7), keeper.GetQuorumPower(ctx))
+
+	// exactly 2/3 is not a majority
+	require.False(t, keeper.HasQuorum(ctx, signers[:6]))
+
+	// just below 2/3
+	require.False(t, keeper.HasQuorum(ctx, signers[:5]))
+
+	// just above 2/3
+	require.True(t, keeper.HasQuorum(ctx, signers[:7]))
+
+	// duplicate signers are counted once
+	require.False(t, keeper.HasQuorum(ctx, append(signers[:6:6], signers[0])))
+
+	// unknown signers add no power
+	unknown := stakingSim.GenRandomVal(1, 0, 1, 10, false, 10)
+	require.False(t, keeper.HasQuorum(ctx, append(signers[:6:6], unknown[0].Signer)))
+}
+
+func (suite *KeeperTestSuite) TestMinimalSignersForQuorum() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	// no validator set
+	require.Empty(t, keeper.MinimalSignersForQuorum(ctx, nil))
+
+	// powers 10..50, total 150, quorum 101
+	validators := stakingSim.GenRandomVal(5, 0, 1, 10, false, 1)
+	valz := make([]*hmTypes.Validator, 0, len(validators))
+	for i := range validators {
+		validators[i].VotingPower = int64(10 * (i + 1))
+		valz = append(valz, &validators[i])
+	}
+	err := keeper.UpdateValidatorSetInStore(ctx, *hmTypes.NewValidatorSet(valz))
+	require.NoError(t, err)
+
+	// power 40 signed, 50 and 30 are the fewest to cross quorum
+	alreadySigned := []hmTypes.HeimdallAddress{validators[3].Signer}
+	minimal := keeper.MinimalSignersForQuorum(ctx, alreadySigned)
+	require.Len(t, minimal, 2)
+	require.Equal(t, validators[4].ID, minimal[0].ID)
+	require.Equal(t, validators[2].ID, minimal[1].ID)
+
+	signers := append([]hmTypes.HeimdallAddress{}, alreadySigned...)
+	for _, validator := range minimal {
+		signers = append(signers, validator.Signer)
+	}
+	require.True(t, keeper.HasQuorum(ctx, signers))
+	require.False(t, keeper.HasQuorum(ctx, signers[:len(signers)-1]))
+
+	// nothing needed once quorum is reached
+	require.Empty(t, keeper.MinimalSignersForQuorum(ctx, signers))
+}
+
+func (suite *KeeperTestSuite) TestGetProposerWeightedRandom() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	// no validator set
+	_, err := keeper.GetProposerWeightedRandom(ctx, []byte("seed"))
+	require.Error(t, err)
+
+	// powers 10..40, total 100
+	validators := stakingSim.GenRandomVal(4, 0, 1, 10, false, 1)
+	valz := make([]*hmTypes.Validator, 0, len(validators))
+	for i := range validators {
+		validators[i].VotingPower = int64(10 * (i + 1))
+		valz = append(valz, &validators[i])
+	}
+	err = keeper.UpdateValidatorSetInStore(ctx, *hmTypes.NewValidatorSet(valz))
+	require.NoError(t, err)
+
+	before := keeper.GetValidatorSet(ctx)
+
+	const rounds = 20000
+	picks := make(map[hmTypes.ValidatorID]int)
+	for i := 0; i < rounds; i++ {
+		seed := make([]byte, 8)
+		binary.BigEndian.PutUint64(seed, uint64(i))
+		proposer, err := keeper.GetProposerWeightedRandom(ctx, seed)
+		require.NoError(t, err)
+		picks[proposer.ID]++
+	}
+
+	// selection frequency approximates power share
+	for _, validator := range validators {
+		share := float64(picks[validator.ID]) / rounds
+		require.InDelta(t, float64(validator.VotingPower)/100, share, 0.02, "validator %v", validator.ID)
+	}
+
+	// same seed, same proposer
+	first, err := keeper.GetProposerWeightedRandom(ctx, []byte("block hash"))
+	require.NoError(t, err)
+	second, err := keeper.GetProposerWeightedRandom(ctx, []byte("block hash"))
+	require.NoError(t, err)
+	require.Equal(t, first.ID, second.ID)
+
+	// stored accums untouched
+	require.Equal(t, before, keeper.GetValidatorSet(ctx))
+}
+
+func (suite *KeeperTestSuite) TestPruneExpiredValidators() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(3, 0, 10, 10, false, 1)
+	validators[0].EndEpoch = 10 // past EndEpoch plus grace
+	validators[1].EndEpoch = 16 // deactivated, still within grace
+	validators[2].EndEpoch = 0  // active
+	for i := range validators {
+		validators[i].LastUpdated = big.NewInt(hmTypes.DefaultLogIndexUnit).String()
+	}
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+	app.CheckpointKeeper.UpdateACKCountWithValue(ctx, 20, hmTypes.RootChainTypeStake)
+
+	// pruning is disabled by default
+	require.Empty(t, keeper.PruneExpiredValidators(ctx))
+	require.Len(t, keeper.GetAllValidators(ctx), 3)
+
+	params := keeper.GetParams(ctx)
+	params.EnableValidatorPruning = true
+	params.ValidatorPruneGracePeriod = 5
+	keeper.SetParams(ctx, params)
+
+	pruned := keeper.PruneExpiredValidators(ctx)
+	require.Len(t, pruned, 1)
+	require.Equal(t, validators[0].ID, pruned[0].ID)
+
+	// pruned validator is archived and removed, its ID map entry is kept
+	_, err := keeper.GetValidatorInfo(ctx, validators[0].Signer.Bytes())
+	require.Error(t, err)
+	_, ok := keeper.GetSignerFromValidatorID(ctx, validators[0].ID)
+	require.True(t, ok)
+
+	archived, err := keeper.GetArchivedValidator(ctx, validators[0].Signer.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, validators[0].ID, archived.ID)
+
+	// lookups fall back to the archive
+	byID, ok := keeper.GetValidatorFromValID(ctx, validators[0].ID)
+	require.True(t, ok)
+	require.Equal(t, archived, byID)
+
+	resolved, stale, err := keeper.ResolveValidatorInfo(ctx, validators[0].Signer.Bytes())
+	require.NoError(t, err)
+	require.False(t, stale)
+	require.Equal(t, archived, resolved)
+
+	nearest, latest, err := keeper.GetValidatorNearestUpdate(ctx, validators[0].ID, 1)
+	require.NoError(t, err)
+	require.True(t, latest)
+	require.Equal(t, archived, nearest)
+
+	// pruned ID and signer can't be joined again
+	require.True(t, keeper.HasValidatorID(ctx, validators[0].ID))
+	require.False(t, keeper.HasValidator(ctx, validators[0].Signer.Bytes()))
+	require.True(t, keeper.IsArchivedValidator(ctx, validators[0].Signer.Bytes()))
+	require.False(t, keeper.IsArchivedValidator(ctx, validators[1].Signer.Bytes()))
+	require.ErrorIs(t, keeper.ValidateOnboarding(ctx, validators[0]), staking.ErrValidatorExists)
+
+	// others remain
+	require.Len(t, keeper.GetAllValidators(ctx), 2)
+	for _, validator := range validators[1:] {
+		_, err := keeper.GetValidatorInfo(ctx, validator.Signer.Bytes())
+		require.NoError(t, err)
+		_, ok := keeper.GetSignerFromValidatorID(ctx, validator.ID)
+		require.True(t, ok)
+	}
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorSetSnapshot() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
+	app.CheckpointKeeper.UpdateACKCountWithValue(ctx, 7, hmTypes.RootChainTypeStake)
+
+	snapshot := keeper.GetValidatorSetSnapshot(ctx)
+	validatorSet := keeper.GetValidatorSet(ctx)
+
+	require.Equal(t, validatorSet.Hash(), snapshot.ValidatorSet.Hash())
+	require.Equal(t, keeper.GetCurrentProposer(ctx).String(), snapshot.Proposer.String())
+	require.Equal(t, validatorSet.TotalVotingPower(), snapshot.TotalPower)
+	require.Equal(t, uint64(7), snapshot.Epoch)
+}
+
+func (suite *KeeperTestSuite) TestUpdateValidatorSetInStoreSkipsUnchanged() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
+
+	// gas consumed by a store update of the given set
+	updateGas := func(validatorSet hmTypes.ValidatorSet) uint64 {
+		gasCtx := ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+		require.NoError(t, keeper.UpdateValidatorSetInStore(gasCtx, validatorSet))
+		return gasCtx.GasMeter().GasConsumed()
+	}
+
+	// unchanged set is not written
+	unchangedGas := updateGas(keeper.GetValidatorSet(ctx))
+
+	// changed set is written
+	validatorSet := keeper.GetValidatorSet(ctx)
+	validatorSet.IncrementProposerPriority(1)
+	changedGas := updateGas(validatorSet)
+
+	require.GreaterOrEqual(t, changedGas-unchangedGas, storeTypes.KVGasConfig().WriteCostFlat)
+
+	stored := keeper.GetValidatorSet(ctx)
+	require.True(t, stored.Equal(&validatorSet))
+}
+
+func (suite *KeeperTestSuite) TestGetCurrentValidatorsFromSet() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
+
+	// iteration path
+	extra := stakingSim.GenRandomVal(1, 0, 5, 10, false, 5)[0]
+	require.NoError(t, keeper.AddValidator(ctx, extra))
+	require.False(t, keeper.IsValidatorSetFresh(ctx, 0))
+	iterated := keeper.GetCurrentValidators(ctx)
+	require.Len(t, iterated, 5)
+
+	// writing the set alone doesn't make it fresh
+	validatorSet := keeper.GetValidatorSet(ctx)
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, validatorSet))
+	require.False(t, keeper.IsValidatorSetFresh(ctx, 0))
+
+	// fast path, once validator updates are applied
+	updates, err := keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.True(t, keeper.IsValidatorSetFresh(ctx, 0))
+	require.ElementsMatch(t, iterated, keeper.GetCurrentValidators(ctx))
+
+	// stale on membership param change, iteration applies the new floor before the set is recomputed
+	params := keeper.GetParams(ctx)
+	params.MinValidatorPower = uint64(extra.VotingPower) + 1
+	keeper.SetParams(ctx, params)
+	require.False(t, keeper.IsValidatorSetFresh(ctx, 0))
+	require.Len(t, keeper.GetCurrentValidators(ctx), 4)
+	require.NotContains(t, keeper.GetCurrentValidators(ctx), extra)
+
+	_, err = keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.True(t, keeper.IsValidatorSetFresh(ctx, 0))
+	require.Len(t, keeper.GetCurrentValidators(ctx), 4)
+
+	// stale on ack count change
+	app.CheckpointKeeper.UpdateACKCountWithValue(ctx, 1, hmTypes.RootChainTypeStake)
+	require.False(t, keeper.IsValidatorSetFresh(ctx, 1))
+}
+
+func (suite *KeeperTestSuite) TestGetCurrentValidatorsAcrossAck() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(3, 0, 10, 10, false, 1)
+	validators[0].StartEpoch, validators[0].EndEpoch = 0, 0
+	validators[1].StartEpoch, validators[1].EndEpoch = 0, 2 // expires with ack 1
+	validators[2].StartEpoch, validators[2].EndEpoch = 2, 0 // joins with ack 1
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+
+	currentIDs := func() []hmTypes.ValidatorID {
+		var ids []hmTypes.ValidatorID
+		for _, v := range keeper.GetCurrentValidators(ctx) {
+			ids = append(ids, v.ID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+
+	_, err := keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.True(t, keeper.IsValidatorSetFresh(ctx, 0))
+	require.Equal(t, []hmTypes.ValidatorID{validators[0].ID, validators[1].ID}, currentIDs())
+
+	// checkpoint ack rotates the proposer, the set stays stale until recomputed
+	keeper.IncrementAccum(ctx, 1)
+	app.CheckpointKeeper.UpdateACKCountWithValue(ctx, 1, hmTypes.RootChainTypeStake)
+	require.False(t, keeper.IsValidatorSetFresh(ctx, 1))
+	require.Equal(t, []hmTypes.ValidatorID{validators[0].ID, validators[2].ID}, currentIDs())
+
+	updates, err := keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	require.True(t, keeper.IsValidatorSetFresh(ctx, 1))
+	require.Equal(t, []hmTypes.ValidatorID{validators[0].ID, validators[2].ID}, currentIDs())
+}
+
+func (suite *KeeperTestSuite) TestRecomputeAllValidatorPowers() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+	validators := stakingSim.GenRandomVal(3, 0, 10, 10, false, 1)
+	stakes := [][2]int64{{120, 40}, {30, 0}, {0, 0}} // validator 2 has no stake
+	for i := range validators {
+		validators[i].EndEpoch = 0
+		validators[i].VotingPower = 10
+		validators[i].SelfStake, validators[i].DelegatedStake = stakes[i][0], stakes[i][1]
+		require.NoError(t, keeper.AddValidator(ctx, validators[i]))
+	}
+	valSet := hmTypes.NewValidatorSet([]*hmTypes.Validator{validators[0].Copy(), validators[1].Copy(), validators[2].Copy()})
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, *valSet))
+
+	// powers are not derived from stake while the divisor is 0
+	require.Equal(t, int64(10), keeper.PowerFromStake(ctx, validators[0]))
+
+	// divisor is recorded the first time, existing powers are kept
+	updated, err := keeper.ApplyPowerDivisorChange(ctx)
+	require.NoError(t, err)
+	require.Empty(t, updated)
+
+	params := keeper.GetParams(ctx)
+	params.PowerDivisor = 10
+	keeper.SetParams(ctx, params)
+
+	updated, err = keeper.ApplyPowerDivisorChange(ctx)
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+
+	powerEvents := func() (n int) {
+		for _, event := range ctx.EventManager().Events() {
+			if event.Type == stakingTypes.EventTypePowerUpdate {
+				n++
+			}
+		}
+		return n
+	}
+	require.Equal(t, 2, powerEvents())
+
+	powers := func() map[hmTypes.ValidatorID]int64 {
+		powers := make(map[hmTypes.ValidatorID]int64)
+		for _, v := range keeper.GetAllValidators(ctx) {
+			powers[v.ID] = v.VotingPower
+		}
+		return powers
+	}
+	require.Equal(t, map[hmTypes.ValidatorID]int64{
+		validators[0].ID: 16,
+		validators[1].ID: 3,
+		validators[2].ID: 10,
+	}, powers())
+
+	// current set picks up the recomputed powers
+	updates, err := keeper.GetValidatorUpdates(ctx)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	stored := keeper.GetValidatorSet(ctx)
+	_, current := stored.GetByAddress(validators[1].Signer.Bytes())
+	require.Equal(t, int64(3), current.VotingPower)
+
+	// unchanged divisor applies nothing, recomputing again is a no-op
+	updated, err = keeper.ApplyPowerDivisorChange(ctx)
+	require.NoError(t, err)
+	require.Empty(t, updated)
+	updated, err = keeper.RecomputeAllValidatorPowers(ctx)
+	require.NoError(t, err)
+	require.Empty(t, updated)
+
+	params.PowerDivisor = 1
+	keeper.SetParams(ctx, params)
+	updated, err = keeper.ApplyPowerDivisorChange(ctx)
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+	require.Equal(t, map[hmTypes.ValidatorID]int64{
+		validators[0].ID: 160,
+		validators[1].ID: 30,
+		validators[2].ID: 10,
+	}, powers())
+
+	// an undecodable validator fails the recompute, nothing is written or emitted
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	store := ctx.KVStore(app.GetKey(stakingTypes.StoreKey))
+	store.Set(staking.GetValidatorKey([]byte("undecodable")), []byte{0xff})
+	params.PowerDivisor = 5
+	keeper.SetParams(ctx, params)
+	_, err = keeper.RecomputeAllValidatorPowers(ctx)
+	require.ErrorIs(t, err, staking.ErrValidatorDecode)
+	require.Zero(t, powerEvents())
+	store.Delete(staking.GetValidatorKey([]byte("undecodable")))
+	require.Equal(t, map[hmTypes.ValidatorID]int64{
+		validators[0].ID: 160,
+		validators[1].ID: 30,
+		validators[2].ID: 10,
+	}, powers())
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorSetDiff() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	params := keeper.GetParams(ctx)
+	params.ValidatorSetHistoryRetention = 100
+	params.MaxPowerSharePercent = 40
+	keeper.SetParams(ctx, params)
+
+	validators := stakingSim.GenRandomVal(3, 0, 10, 10, false, 1)
+
+	// validators 1 and 2 at height 10
+	fromSet := hmTypes.NewValidatorSet([]*hmTypes.Validator{validators[0].Copy(), validators[1].Copy()})
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx.WithBlockHeight(10), *fromSet))
+
+	// validator 1 removed, 2 power changed and 3 added at height 20
+	changed := validators[1].Copy()
+	changed.VotingPower += 5
+	toSet := hmTypes.NewValidatorSet([]*hmTypes.Validator{changed, validators[2].Copy()})
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx.WithBlockHeight(20), *toSet))
+
+	diff, err := keeper.GetValidatorSetDiff(ctx, 10, 20)
+	require.NoError(t, err)
+	require.Len(t, diff.Added, 1)
+	require.Equal(t, validators[2].ID, diff.Added[0].ID)
+	require.Len(t, diff.Removed, 1)
+	require.Equal(t, validators[0].ID, diff.Removed[0].ID)
+	require.Len(t, diff.PowerChanged, 1)
+	require.Equal(t, changed.ID, diff.PowerChanged[0].ID)
+	require.Equal(t, changed.VotingPower, diff.PowerChanged[0].VotingPower)
+
+	// height without retained set
+	_, err = keeper.GetValidatorSetDiff(ctx, 10, 15)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "height 15 not retained")
+
+	// sets older than the retention window are pruned
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx.WithBlockHeight(115), *fromSet))
+	_, err = keeper.GetValidatorSetDiff(ctx, 10, 20)
+	require.Error(t, err)
+	_, err = keeper.GetValidatorSetDiff(ctx, 20, 115)
+	require.NoError(t, err)
+}
+
+func (suite *KeeperTestSuite) TestIncrementAccumDegenerateSets() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	// empty set is left untouched
+	require.NotPanics(t, func() { keeper.IncrementAccum(ctx, 1) })
+	require.Empty(t, keeper.GetValidatorSet(ctx).Validators)
+
+	// single validator stays proposer
+	validatorSet := chSim.LoadValidatorSet(1, t, keeper, ctx, false, 10)
+	require.NotPanics(t, func() { keeper.IncrementAccum(ctx, 5) })
+
+	stored := keeper.GetValidatorSet(ctx)
+	require.Len(t, stored.Validators, 1)
+	require.NotNil(t, stored.Proposer)
+	require.Equal(t, validatorSet.Validators[0].Signer, stored.Proposer.Signer)
+}
+
+func (suite *KeeperTestSuite) TestHasValidator() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validator := stakingSim.GenRandomVal(1, 0, 10, 10, false, 1)[0]
+	require.False(t, keeper.HasValidator(ctx, validator.Signer.Bytes()))
+	require.False(t, keeper.HasValidatorID(ctx, validator.ID))
+
+	require.NoError(t, keeper.AddValidator(ctx, validator))
+	require.True(t, keeper.HasValidator(ctx, validator.Signer.Bytes()))
+	require.True(t, keeper.HasValidatorID(ctx, validator.ID))
+
+	keeper.DeleteValidator(ctx, validator)
+	require.False(t, keeper.HasValidator(ctx, validator.Signer.Bytes()))
+	require.False(t, keeper.HasValidatorID(ctx, validator.ID))
+	_, err := keeper.GetValidatorInfo(ctx, validator.Signer.Bytes())
+	require.Error(t, err)
+}
+
+func (suite *KeeperTestSuite) TestUpdateCommissionRate() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+	validators := stakingSim.GenRandomVal(1, 0, 10, 10, false, 1)
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+
+	require.NoError(t, keeper.UpdateCommissionRate(ctx, validators[0].ID, 1500))
+
+	validator, ok := keeper.GetValidatorFromValID(ctx, validators[0].ID)
+	require.True(t, ok)
+	require.Equal(t, uint64(1500), validator.GetCommissionRate())
+
+	events := ctx.EventManager().Events()
+	require.Len(t, events, 1)
+	require.Equal(t, stakingTypes.EventTypeCommissionUpdate, events[0].Type)
+	require.Contains(t, events[0].Attributes, sdk.NewAttribute(stakingTypes.AttributeKeyCommissionRate, "1500").ToKVPair())
+
+	// full commission is allowed, above is rejected
+	require.NoError(t, keeper.UpdateCommissionRate(ctx, validators[0].ID, hmTypes.MaxCommissionRate))
+	require.Error(t, keeper.UpdateCommissionRate(ctx, validators[0].ID, hmTypes.MaxCommissionRate+1))
+
+	validator, ok = keeper.GetValidatorFromValID(ctx, validators[0].ID)
+	require.True(t, ok)
+	require.Equal(t, hmTypes.MaxCommissionRate, validator.GetCommissionRate())
+
+	// unknown validator and out of range rate on add are rejected
+	require.Error(t, keeper.UpdateCommissionRate(ctx, hmTypes.NewValidatorID(100), 10))
+	validator.CommissionRate = hmTypes.MaxCommissionRate + 1
+	require.Error(t, keeper.AddValidator(ctx, validator))
+}
+
+func (suite *KeeperTestSuite) TestSetValidatorDescription() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+	validators := stakingSim.GenRandomVal(1, 0, 10, 10, false, 1)
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+	valID := validators[0].ID
+
+	_, found := keeper.GetValidatorDescription(ctx, valID)
+	require.False(t, found)
+
+	description := stakingTypes.NewDescription("validator-one", "3A8F2C91D0E4B7A6", "https://validator.example.com", "runs in two regions")
+	require.NoError(t, keeper.SetValidatorDescription(ctx, valID, description))
+
+	stored, found := keeper.GetValidatorDescription(ctx, valID)
+	require.True(t, found)
+	require.Equal(t, description, stored)
+
+	events := ctx.EventManager().Events()
+	require.Len(t, events, 1)
+	require.Equal(t, stakingTypes.EventTypeDescriptionUpdate, events[0].Type)
+	require.Contains(t, events[0].Attributes, sdk.NewAttribute(stakingTypes.AttributeKeyMoniker, "validator-one").ToKVPair())
+
+	// unchanged description emits nothing
+	require.NoError(t, keeper.SetValidatorDescription(ctx, valID, description))
+	require.Len(t, ctx.EventManager().Events(), 1)
+
+	// invalid descriptions are rejected and leave the stored one
+	for _, invalid := range []stakingTypes.Description{
+		stakingTypes.NewDescription("", "", "", ""),
+		stakingTypes.NewDescription(strings.Repeat("m", stakingTypes.MaxMonikerLength+1), "", "", ""),
+		stakingTypes.NewDescription("validator-one", strings.Repeat("i", stakingTypes.MaxIdentityLength+1), "", ""),
+		stakingTypes.NewDescription("validator-one", "", "https://"+strings.Repeat("w", stakingTypes.MaxWebsiteLength)+".com", ""),
+		stakingTypes.NewDescription("validator-one", "", "", strings.Repeat("d", stakingTypes.MaxDetailsLength+1)),
+		stakingTypes.NewDescription("validator-one", "", "validator.example.com", ""),
+		stakingTypes.NewDescription("validator-one", "", "ftp://validator.example.com", ""),
+	} {
+		require.Error(t, keeper.SetValidatorDescription(ctx, valID, invalid))
+	}
+	stored, found = keeper.GetValidatorDescription(ctx, valID)
+	require.True(t, found)
+	require.Equal(t, description, stored)
+
+	// max length fields are accepted
+	maxed := stakingTypes.NewDescription(strings.Repeat("m", stakingTypes.MaxMonikerLength), "", "", strings.Repeat("d", stakingTypes.MaxDetailsLength))
+	require.NoError(t, keeper.SetValidatorDescription(ctx, valID, maxed))
+
+	// unknown validator
+	require.ErrorIs(t, keeper.SetValidatorDescription(ctx, hmTypes.NewValidatorID(100), description), staking.ErrValidatorNotFound)
+}
+
+func (suite *KeeperTestSuite) TestOnboardValidator() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+	validator := stakingSim.GenRandomVal(1, 0, 10, 10, false, 1)[0]
+	validator.Nonce = 3
+	require.NoError(t, keeper.OnboardValidator(ctx, validator, "100001", sdk.NewAttribute(sdk.AttributeKeyAction, "join")))
+
+	// record, ID map and signing info are set in one call
+	stored, err := keeper.GetValidatorInfo(ctx, validator.Signer.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "100001", stored.LastUpdated)
+	signer, ok := keeper.GetSignerFromValidatorID(ctx, validator.ID)
+	require.True(t, ok)
+	require.Equal(t, validator.Signer.EthAddress(), signer)
+	_, found := app.SlashingKeeper.GetValidatorSigningInfo(ctx, validator.ID)
+	require.True(t, found)
+
+	// join event carries the caller's attributes
+	events := ctx.EventManager().Events()
+	require.Len(t, events, 1)
+	require.Equal(t, stakingTypes.EventTypeValidatorJoin, events[0].Type)
+	require.Contains(t, events[0].Attributes, sdk.NewAttribute(stakingTypes.AttributeKeyValidatorNonce, "3").ToKVPair())
+	require.Contains(t, events[0].Attributes, sdk.NewAttribute(sdk.AttributeKeyAction, "join").ToKVPair())
+
+	// already onboarded, no event
+	require.ErrorIs(t, keeper.OnboardValidator(ctx, validator, "100002"), staking.ErrValidatorExists)
+	require.Len(t, ctx.EventManager().Events(), 1)
+
+	// signer must be derived from pubkey
+	mismatched := stakingSim.GenRandomVal(2, 0, 10, 10, false, 2)
+	mismatched[0].Signer = mismatched[1].Signer
+	require.Error(t, keeper.OnboardValidator(ctx, mismatched[0], "100003"))
+	require.False(t, keeper.HasValidatorID(ctx, mismatched[0].ID))
+}
+
+func (suite *KeeperTestSuite) TestValidatorReactivationGuard() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validator := stakingSim.GenRandomVal(1, 0, 10, 0, false, 1)[0]
+	require.NoError(t, keeper.AddValidator(ctx, validator))
+
+	// deactivate
+	validator.EndEpoch = 5
+	require.NoError(t, keeper.AddValidator(ctx, validator))
+
+	// clearing end epoch is rejected
+	validator.EndEpoch = 0
+	err := keeper.AddValidator(ctx, validator)
+	require.True(t, errors.Is(err, staking.ErrValidatorReactivation))
+	stored, err := keeper.GetValidatorInfo(ctx, validator.Signer.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), stored.EndEpoch)
+
+	// end epoch can still be updated
+	validator.EndEpoch = 7
+	require.NoError(t, keeper.AddValidator(ctx, validator))
+	stored, err = keeper.GetValidatorInfo(ctx, validator.Signer.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), stored.EndEpoch)
+}
+
+func (suite *KeeperTestSuite) TestValidatorSetMetrics() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validatorSet := chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, validatorSet))
+	require.Equal(t, float64(4), testutil.ToFloat64(staking.ValidatorSetSize))
+	require.Equal(t, float64(validatorSet.TotalVotingPower()), testutil.ToFloat64(staking.ValidatorSetTotalPower))
+
+	// set write with a removed validator updates both gauges
+	removed := validatorSet.Validators[0].Copy()
+	removed.VotingPower = 0
+	require.NoError(t, validatorSet.UpdateWithChangeSet([]*hmTypes.Validator{removed}))
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, validatorSet))
+	require.Equal(t, float64(3), testutil.ToFloat64(staking.ValidatorSetSize))
+	require.Equal(t, float64(validatorSet.TotalVotingPower()), testutil.ToFloat64(staking.ValidatorSetTotalPower))
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorsAtEpoch() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(3, 1, 10, 0, false, 1)
+	validators[0].EndEpoch = 0   // active indefinitely
+	validators[1].EndEpoch = 3   // leaving
+	validators[2].StartEpoch = 5 // joining
+	validators[2].EndEpoch = 0
+	for _, validator := range validators {
+		require.NoError(t, keeper.AddValidator(ctx, validator))
+	}
+
+	ids := func(validators []hmTypes.Validator) (result []hmTypes.ValidatorID) {
+		for _, validator := range validators {
+			result = append(result, validator.ID)
+		}
+		return
+	}
+
+	// current epoch is ack count + 1
+	ackCount := app.CheckpointKeeper.GetACKCount(ctx, hmTypes.RootChainTypeStake)
+	require.ElementsMatch(t, ids(keeper.GetCurrentValidators(ctx)), ids(keeper.GetValidatorsAtEpoch(ctx, ackCount+1)))
+	require.ElementsMatch(t, []hmTypes.ValidatorID{1, 2}, ids(keeper.GetValidatorsAtEpoch(ctx, ackCount+1)))
+
+	// leaving validator is gone and joining one is active, unset end epoch stays active
+	require.ElementsMatch(t, []hmTypes.ValidatorID{1, 3}, ids(keeper.GetValidatorsAtEpoch(ctx, 5)))
+	require.ElementsMatch(t, []hmTypes.ValidatorID{1, 3}, ids(keeper.GetValidatorsAtEpoch(ctx, 1000)))
+}
+
+func (suite *KeeperTestSuite) TestGetAllValidatorIDs() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+
+	require.Empty(t, k.GetAllValidatorIDs(ctx))
+
+	accounts := simulation.RandomAccounts(rand.New(rand.NewSource(1)), 3)
+	ids := []hmTypes.ValidatorID{2, 10, 1}
+	for i, id := range ids {
+		k.SetValidatorIDToSignerAddr(ctx, id, accounts[i].Address)
+	}
+
+	require.Equal(t, []hmTypes.ValidatorID{1, 2, 10}, k.GetAllValidatorIDs(ctx))
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorByIDDetailed() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+
+	// found
+	validator, found, reason := keeper.GetValidatorByIDDetailed(ctx, validators[0].ID)
+	require.True(t, found)
+	require.Equal(t, stakingTypes.ValidatorLookupFound, reason)
+	require.Equal(t, validators[0].Signer, validator.Signer)
+
+	// ID not mapped
+	_, found, reason = keeper.GetValidatorByIDDetailed(ctx, hmTypes.NewValidatorID(100))
+	require.False(t, found)
+	require.Equal(t, stakingTypes.ValidatorLookupIDUnmapped, reason)
+
+	// ID mapped, validator missing
+	keeper.SetValidatorIDToSignerAddr(ctx, validators[1].ID, validators[1].Signer)
+	_, found, reason = keeper.GetValidatorByIDDetailed(ctx, validators[1].ID)
+	require.False(t, found)
+	require.Equal(t, stakingTypes.ValidatorLookupValidatorMissing, reason)
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorByEthAddress() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+
+	validator, err := keeper.GetValidatorByEthAddress(ctx, validators[0].Signer.EthAddress())
+	require.NoError(t, err)
+	require.Equal(t, validators[0].ID, validator.ID)
+
+	// unknown signer
+	_, err = keeper.GetValidatorByEthAddress(ctx, validators[1].Signer.EthAddress())
+	require.Error(t, err)
+
+	// zero address
+	_, err = keeper.GetValidatorByEthAddress(ctx, helper.ZeroAddress)
+	require.Error(t, err)
+}
+
+func (suite *KeeperTestSuite) TestSafeSetValidatorIDToSignerAddr() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	validators := stakingSim.GenRandomVal(2, 0, 10, 10, false, 1)
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+
+	// re-set to the same signer succeeds
+	require.NoError(t, keeper.SafeSetValidatorIDToSignerAddr(ctx, validators[0].ID, validators[0].Signer))
+	require.NoError(t, keeper.AddValidator(ctx, validators[0]))
+
+	// conflicting mapping for the same ID is rejected
+	conflicting := validators[1]
+	conflicting.ID = validators[0].ID
+	require.Error(t, keeper.SafeSetValidatorIDToSignerAddr(ctx, conflicting.ID, conflicting.Signer))
+	require.Error(t, keeper.AddValidator(ctx, conflicting))
+
+	_, err := keeper.GetValidatorInfo(ctx, conflicting.Signer.Bytes())
+	require.Error(t, err, "conflicting validator should not be stored")
+
+	signer, ok := keeper.GetSignerFromValidatorID(ctx, validators[0].ID)
+	require.True(t, ok)
+	require.Equal(t, validators[0].Signer.EthAddress(), signer)
+
+	// explicit overwrite is allowed for signer changes
+	keeper.SetValidatorIDToSignerAddr(ctx, conflicting.ID, conflicting.Signer)
+	require.NoError(t, keeper.AddValidator(ctx, conflicting))
+}
+
+func (suite *KeeperTestSuite) TestGetValidatorInfos() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+
+	stored := stakingSim.GenRandomVal(3, 0, 10, 10, false, 1)
+	for _, v := range stored {
+		require.NoError(t, keeper.AddValidator(ctx, v))
+	}
+	unknown := stakingSim.GenRandomVal(2, 0, 10, 10, false, 10)
+
+	addresses := [][]byte{
+		stored[0].Signer.Bytes(),
+		unknown[0].Signer.Bytes(),
+		stored[2].Signer.Bytes(),
+		unknown[1].Signer.Bytes(),
+	}
+
+	validators, missing := keeper.GetValidatorInfos(ctx, addresses)
+	require.Len(t, validators, 2)
+	require.Equal(t, stored[0], validators[stored[0].Signer])
+	require.Equal(t, stored[2], validators[stored[2].Signer])
+	require.Equal(t, [][]byte{unknown[0].Signer.Bytes(), unknown[1].Signer.Bytes()}, missing)
+}
+
+func (suite *KeeperTestSuite) TestRemoveStakingRecordByTxHash() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	rootChainID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+
+	records := make([]stakingTypes.StakingRecord, 3)
+	for i := range records {
+		records[i] = stakingTypes.StakingRecord{
+			Type:        "validatorJoin",
+			ValidatorID: hmTypes.NewValidatorID(uint64(i + 1)),
+			Nonce:       uint64(i),
+			Height:      ctx.BlockHeight(),
+			TxHash:      hmTypes.BytesToHeimdallHash([]byte{byte(i + 1)}),
+		}
+		k.AddStakingRecordToQueue(ctx, rootChainID, records[i])
+	}
+
+	// unknown hash removes nothing
+	require.False(t, k.RemoveStakingRecordByTxHash(ctx, rootChainID, hmTypes.BytesToHeimdallHash([]byte{0xff})))
+
+	// remove the middle record by hash
+	require.True(t, k.RemoveStakingRecordByTxHash(ctx, rootChainID, records[1].TxHash))
+
+	queue, err := k.GetStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, []stakingTypes.StakingRecord{records[0], records[2]}, queue)
+
+	// removing the rest empties the queue
+	require.True(t, k.RemoveStakingRecordByTxHash(ctx, rootChainID, records[0].TxHash))
+	require.True(t, k.RemoveStakingRecordByTxHash(ctx, rootChainID, records[2].TxHash))
+	queue, err = k.GetStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Empty(t, queue)
+}
+
+func (suite *KeeperTestSuite) TestPowerUpdateStakingRecord() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	rootChainID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+
+	joinRecord := stakingTypes.StakingRecord{
+		Type:        "validatorJoin",
+		ValidatorID: 1,
+		Nonce:       1,
+		Height:      ctx.BlockHeight(),
+		TxHash:      hmTypes.BytesToHeimdallHash([]byte{0x01}),
+	}
+	powerRecord := stakingTypes.NewPowerUpdateRecord(1, 2, 100, ctx.BlockHeight(), hmTypes.BytesToHeimdallHash([]byte{0x02}))
+	require.True(t, powerRecord.IsPowerUpdate())
+	require.False(t, joinRecord.IsPowerUpdate())
+
+	k.AddStakingRecordToQueue(ctx, rootChainID, joinRecord)
+	k.AddStakingRecordToQueue(ctx, rootChainID, powerRecord)
+
+	// negative power is rejected
+	k.AddStakingRecordToQueue(ctx, rootChainID, stakingTypes.NewPowerUpdateRecord(1, 3, -1, ctx.BlockHeight(), hmTypes.BytesToHeimdallHash([]byte{0x03})))
+	queue, err := k.GetStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, []stakingTypes.StakingRecord{joinRecord, powerRecord}, queue)
+
+	// power update is dequeued in order after the join
+	k.RemoveStakingRecordFromQueue(ctx, rootChainID, joinRecord.ValidatorID, joinRecord.Nonce)
+	result, err := k.GetNextStakingRecordFromQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, powerRecord, *result)
+	require.Equal(t, int64(100), result.Power)
+
+	k.RemoveStakingRecordFromQueue(ctx, rootChainID, powerRecord.ValidatorID, powerRecord.Nonce)
+	result, err = k.GetNextStakingRecordFromQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func (suite *KeeperTestSuite) TestStakingQueueVersioning() {
+	t, app := suite.T(), suite.app
+	k := app.StakingKeeper
+
+	records := []stakingTypes.StakingRecord{
+		{Type: "validatorJoin", ValidatorID: 1, Nonce: 1, Height: 10, TxHash: hmTypes.BytesToHeimdallHash([]byte{0x01})},
+		stakingTypes.NewPowerUpdateRecord(2, 3, 100, 11, hmTypes.BytesToHeimdallHash([]byte{0x02})),
+	}
+
+	// legacy unversioned value
+	legacy, err := app.Codec().MarshalBinaryBare(records)
+	require.NoError(t, err)
+	require.NotEqual(t, byte(0x01), legacy[0])
+	decoded, err := k.DecodeStakingQueue(legacy)
+	require.NoError(t, err)
+	require.Equal(t, records, decoded)
+
+	// versioned value
+	versioned, err := k.EncodeStakingQueue(records)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x01), versioned[0])
+	require.Equal(t, legacy, versioned[1:])
+	decoded, err = k.DecodeStakingQueue(versioned)
+	require.NoError(t, err)
+	require.Equal(t, records, decoded)
+}
+
+func (suite *KeeperTestSuite) TestCompactStakingQueue() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	rootChainID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+	store := ctx.KVStore(app.GetKey(stakingTypes.StoreKey))
+	key := staking.GetStakingQueueKey(rootChainID)
+
+	// missing queue
+	dropped, err := k.CompactStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, 0, dropped)
+
+	first := stakingTypes.StakingRecord{Type: "validatorJoin", ValidatorID: 1, Nonce: 1, Height: 10, TxHash: hmTypes.BytesToHeimdallHash([]byte{0x01})}
+	second := stakingTypes.NewPowerUpdateRecord(2, 3, 100, 11, hmTypes.BytesToHeimdallHash([]byte{0x02}))
+	third := stakingTypes.StakingRecord{Type: "stakeUpdate", ValidatorID: 1, Nonce: 2, Height: 12, TxHash: hmTypes.BytesToHeimdallHash([]byte{0x03})}
+	stale := []stakingTypes.StakingRecord{
+		first,
+		second,
+		first, // duplicate
+		stakingTypes.NewPowerUpdateRecord(3, 1, -1, 11, hmTypes.BytesToHeimdallHash([]byte{0x04})), // invalid power
+		third,
+	}
+
+	// legacy unversioned value with stale entries
+	legacy, err := app.Codec().MarshalBinaryBare(stale)
+	require.NoError(t, err)
+	store.Set(key, legacy)
+
+	dropped, err = k.CompactStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, 2, dropped)
+
+	expected := []stakingTypes.StakingRecord{first, second, third}
+	queue, err := k.GetStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, expected, queue)
+
+	versioned, err := k.EncodeStakingQueue(expected)
+	require.NoError(t, err)
+	require.Equal(t, versioned, store.Get(key))
+
+	// compacting again is a no-op
+	dropped, err = k.CompactStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, 0, dropped)
+	require.Equal(t, versioned, store.Get(key))
+
+	// empty queue value is removed
+	empty, err := k.EncodeStakingQueue(nil)
+	require.NoError(t, err)
+	store.Set(key, empty)
+	_, err = k.CompactStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.False(t, store.Has(key))
+}
+
+func (suite *KeeperTestSuite) TestExportStakingQueues() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	ethRootID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+	tronRootID := hmTypes.GetRootChainID(hmTypes.RootChainTypeTron)
+
+	// empty store
+	queues, err := k.ExportStakingQueues(ctx)
+	require.NoError(t, err)
+	require.Empty(t, queues)
+
+	var ethRecords, tronRecords []stakingTypes.StakingRecord
+	for i, nonce := range []uint64{2, 1, 3} {
+		record := stakingTypes.StakingRecord{
+			Type:        "stakeUpdate",
+			ValidatorID: 1,
+			Nonce:       nonce,
+			Height:      ctx.BlockHeight(),
+			TxHash:      hmTypes.BytesToHeimdallHash([]byte{byte(i + 1)}),
+		}
+		k.AddStakingRecordToQueue(ctx, ethRootID, record)
+		ethRecords = append(ethRecords, record)
+	}
+	for i, nonce := range []uint64{5, 4} {
+		record := stakingTypes.StakingRecord{
+			Type:        "stakeUpdate",
+			ValidatorID: 2,
+			Nonce:       nonce,
+			Height:      ctx.BlockHeight(),
+			TxHash:      hmTypes.BytesToHeimdallHash([]byte{byte(i + 0x10)}),
+		}
+		k.AddStakingRecordToQueue(ctx, tronRootID, record)
+		tronRecords = append(tronRecords, record)
+	}
+
+	queues, err = k.ExportStakingQueues(ctx)
+	require.NoError(t, err)
+	require.Len(t, queues, 2)
+	require.Equal(t, ethRecords, queues[ethRootID])
+	require.Equal(t, tronRecords, queues[tronRootID])
+}
+
+func (suite *KeeperTestSuite) TestGetNextStakingRecordHead() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	rootID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+	store := ctx.KVStore(app.GetKey(stakingTypes.StoreKey))
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	// empty queue
+	next, err := k.GetNextStakingRecordFromQueue(ctx, rootID)
+	require.NoError(t, err)
+	require.Nil(t, next)
+
+	records := make([]stakingTypes.StakingRecord, 0, 20)
+	for i := 0; i < 20; i++ {
+		records = append(records, stakingTypes.StakingRecord{
+			Type:        stakingTypes.StakingRecordTypePowerUpdate,
+			ValidatorID: hmTypes.ValidatorID(r.Uint64()),
+			Nonce:       r.Uint64(),
+			Height:      r.Int63(),
+			TxHash:      hmTypes.BytesToHeimdallHash([]byte{byte(i + 1)}),
+			TimeStamp:   r.Uint64(),
+			Power:       r.Int63(),
+		})
+	}
+
+	legacy, err := app.Codec().MarshalBinaryBare(records)
+	require.NoError(t, err)
+	versioned, err := k.EncodeStakingQueue(records)
+	require.NoError(t, err)
+
+	// head matches the full decode for versioned and legacy values
+	for _, bz := range [][]byte{versioned, legacy} {
+		store.Set(staking.GetStakingQueueKey(rootID), bz)
+
+		full, err := k.GetStakingQueue(ctx, rootID)
+		require.NoError(t, err)
+		next, err := k.GetNextStakingRecordFromQueue(ctx, rootID)
+		require.NoError(t, err)
+		require.Equal(t, full[0], *next)
+		require.Equal(t, records[0], *next)
+	}
+
+	// zero value head record
+	zero, err := k.EncodeStakingQueue([]stakingTypes.StakingRecord{{}, records[1]})
+	require.NoError(t, err)
+	store.Set(staking.GetStakingQueueKey(rootID), zero)
+	next, err = k.GetNextStakingRecordFromQueue(ctx, rootID)
+	require.NoError(t, err)
+	require.Equal(t, stakingTypes.StakingRecord{}, *next)
+
+	// truncated value
+	store.Set(staking.GetStakingQueueKey(rootID), versioned[:10])
+	_, err = k.GetNextStakingRecordFromQueue(ctx, rootID)
+	require.Error(t, err)
+}
+
+func (suite *KeeperTestSuite) TestStakingQueueInterleavedMutations() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	rootID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+
+	record := func(validatorID uint64, nonce uint64) stakingTypes.StakingRecord {
+		return stakingTypes.StakingRecord{
+			Type:        "stakeUpdate",
+			ValidatorID: hmTypes.ValidatorID(validatorID),
+			Nonce:       nonce,
+			Height:      ctx.BlockHeight(),
+			TxHash:      hmTypes.BytesToHeimdallHash([]byte(fmt.Sprintf("%d-%d", validatorID, nonce))),
+		}
+	}
+
+	// records later removed
+	const n = 50
+	for i := uint64(1); i <= n; i++ {
+		k.AddStakingRecordToQueue(ctx, rootID, record(1, i))
+	}
+
+	// adds and removes interleave, as handlers of a block do
+	for i := uint64(1); i <= n; i++ {
+		k.AddStakingRecordToQueue(ctx, rootID, record(2, i))
+		require.True(t, k.RemoveStakingRecordByTxHash(ctx, rootID, record(1, i).TxHash))
+	}
+
+	// every added record survived, every removed one is gone
+	records, err := k.GetStakingQueue(ctx, rootID)
+	require.NoError(t, err)
+	require.Len(t, records, n)
+
+	nonces := make(map[uint64]bool, n)
+	for _, record := range records {
+		require.Equal(t, hmTypes.ValidatorID(2), record.ValidatorID)
+		nonces[record.Nonce] = true
+	}
+	require.Len(t, nonces, n)
+}
+
+func (suite *KeeperTestSuite) TestGetAllQueuedStakingRecords() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	ethRootID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+	tronRootID := hmTypes.GetRootChainID(hmTypes.RootChainTypeTron)
+
+	// empty store
+	queues, total, err := k.GetAllQueuedStakingRecords(ctx)
+	require.NoError(t, err)
+	require.Empty(t, queues)
+	require.Zero(t, total)
+
+	for i, rootID := range []byte{ethRootID, ethRootID, tronRootID, ethRootID, tronRootID} {
+		k.AddStakingRecordToQueue(ctx, rootID, stakingTypes.StakingRecord{
+			Type:        "stakeUpdate",
+			ValidatorID: hmTypes.ValidatorID(rootID),
+			Nonce:       uint64(i + 1),
+			Height:      ctx.BlockHeight(),
+			TxHash:      hmTypes.BytesToHeimdallHash([]byte{byte(i + 1)}),
+		})
+	}
+
+	queues, total, err = k.GetAllQueuedStakingRecords(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5, total)
+	require.Len(t, queues, 2)
+	require.Len(t, queues[ethRootID], 3)
+	require.Len(t, queues[tronRootID], 2)
+	for rootID, records := range queues {
+		for _, record := range records {
+			require.Equal(t, hmTypes.ValidatorID(rootID), record.ValidatorID)
+		}
+	}
+}
+
+func (suite *KeeperTestSuite) TestDrainStakingQueue() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	rootChainID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+
+	var expected []stakingTypes.StakingRecord
+	for i := 0; i < 7; i++ {
+		record := stakingTypes.StakingRecord{
+			Type:        "stakeUpdate",
+			ValidatorID: 1,
+			Nonce:       uint64(i + 1),
+			Height:      ctx.BlockHeight(),
+			TxHash:      hmTypes.BytesToHeimdallHash([]byte{byte(i + 1)}),
+		}
+		k.AddStakingRecordToQueue(ctx, rootChainID, record)
+		expected = append(expected, record)
+	}
+
+	_, err := k.DrainStakingQueue(ctx, rootChainID, 0, func([]stakingTypes.StakingRecord) error { return nil })
+	require.Error(t, err)
+
+	// failed batch stays queued
+	_, err = k.DrainStakingQueue(ctx, rootChainID, 3, func([]stakingTypes.StakingRecord) error {
+		return errors.New("processing failed")
+	})
+	require.Error(t, err)
+	queue, err := k.GetStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, expected, queue)
+
+	var drained []stakingTypes.StakingRecord
+	var sizes []int
+	for {
+		batch, err := k.DrainStakingQueue(ctx, rootChainID, 3, func(records []stakingTypes.StakingRecord) error {
+			drained = append(drained, records...)
+			return nil
+		})
+		require.NoError(t, err)
+		if len(batch) == 0 {
+			break
+		}
+		sizes = append(sizes, len(batch))
+	}
+
+	require.Equal(t, []int{3, 3, 1}, sizes)
+	require.Equal(t, expected, drained)
+
+	queue, err = k.GetStakingQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Empty(t, queue)
+}
+
+func (suite *KeeperTestSuite) TestGetNextStakingRecordFromQueueWithOrder() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	rootChainID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+
+	// enqueue records out of nonce order
+	for i, nonce := range []uint64{3, 1, 2} {
+		k.AddStakingRecordToQueue(ctx, rootChainID, stakingTypes.StakingRecord{
+			Type:        "stakeUpdate",
+			ValidatorID: 1,
+			Nonce:       nonce,
+			Height:      ctx.BlockHeight(),
+			TxHash:      hmTypes.BytesToHeimdallHash([]byte{byte(i + 1)}),
+		})
+	}
+	// lower nonce of another validator
+	k.AddStakingRecordToQueue(ctx, rootChainID, stakingTypes.StakingRecord{
+		Type:        "stakeUpdate",
+		ValidatorID: 2,
+		Nonce:       0,
+		Height:      ctx.BlockHeight(),
+		TxHash:      hmTypes.BytesToHeimdallHash([]byte{0x10}),
+	})
+
+	// default keeps insertion order
+	result, err := k.GetNextStakingRecordFromQueue(ctx, rootChainID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), result.Nonce)
+
+	// insertion order ignores the validator
+	result, err = k.GetNextStakingRecordFromQueueWithOrder(ctx, rootChainID, stakingTypes.StakingQueueOrderInsertion, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), result.Nonce)
+
+	// lowest nonce for the given validator
+	result, err = k.GetNextStakingRecordFromQueueWithOrder(ctx, rootChainID, stakingTypes.StakingQueueOrderLowestNonce, 1)
+	require.NoError(t, err)
+	require.Equal(t, hmTypes.ValidatorID(1), result.ValidatorID)
+	require.Equal(t, uint64(1), result.Nonce)
+
+	// validator that isn't the head's
+	result, err = k.GetNextStakingRecordFromQueueWithOrder(ctx, rootChainID, stakingTypes.StakingQueueOrderLowestNonce, 2)
+	require.NoError(t, err)
+	require.Equal(t, hmTypes.ValidatorID(2), result.ValidatorID)
+	require.Equal(t, uint64(0), result.Nonce)
+
+	// validator with nothing queued
+	result, err = k.GetNextStakingRecordFromQueueWithOrder(ctx, rootChainID, stakingTypes.StakingQueueOrderLowestNonce, 3)
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func (suite *KeeperTestSuite) TestGetStakingRecordAfter() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	k := app.StakingKeeper
+	rootChainID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+
+	// empty queue
+	result, err := k.GetStakingRecordAfter(ctx, rootChainID, 1, 0)
+	require.NoError(t, err)
+	require.Nil(t, result)
+
+	// nonces of validator 1 enqueued out of order, interleaved with validator 2
+	for i, record := range []struct {
+		validatorID hmTypes.ValidatorID
+		nonce       uint64
+	}{{1, 5}, {2, 2}, {1, 2}, {1, 9}, {2, 3}} {
+		k.AddStakingRecordToQueue(ctx, rootChainID, stakingTypes.NewPowerUpdateRecord(record.validatorID, record.nonce, 10, ctx.BlockHeight(), hmTypes.BytesToHeimdallHash([]byte{byte(i + 1)})))
+	}
+
+	successors := func(validatorID hmTypes.ValidatorID, nonce uint64) []uint64 {
+		var nonces []uint64
+		for {
+			next, err := k.GetStakingRecordAfter(ctx, rootChainID, validatorID, nonce)
+			require.NoError(t, err)
+			if next == nil {
+				return nonces
+			}
+			require.Equal(t, validatorID, next.ValidatorID)
+			nonces = append(nonces, next.Nonce)
+			nonce = next.Nonce
+		}
+	}
+
+	require.Equal(t, []uint64{2, 5, 9}, successors(1, 0))
+	require.Equal(t, []uint64{3}, successors(2, 2))
+
+	// successor of a nonce that isn't queued
+	result, err = k.GetStakingRecordAfter(ctx, rootChainID, 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), result.Nonce)
+
+	// nil at the tail and for unknown validators
+	result, err = k.GetStakingRecordAfter(ctx, rootChainID, 1, 9)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	result, err = k.GetStakingRecordAfter(ctx, rootChainID, 3, 0)
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func (suite *KeeperTestSuite) TestValidatorSetCache() {
+	t, app, ctx := suite.T(), suite.app, suite.ctx
+	keeper := app.StakingKeeper
+	chSim.LoadValidatorSet(4, t, keeper, ctx, false, 10)
+
+	// cached reads are copies
+	validatorSet := keeper.GetValidatorSet(ctx)
+	validatorSet.Validators[0].VotingPower = 1000
+	validatorSet.IncrementProposerPriority(1)
+	require.Equal(t, keeper.GetValidatorSetUncached(ctx), keeper.GetValidatorSet(ctx))
+
+	// stored update is picked up
+	require.NoError(t, keeper.UpdateValidatorSetInStore(ctx, validatorSet))
+	cached := keeper.GetValidatorSet(ctx)
+	require.Equal(t, int64(1000), cached.Validators[0].VotingPower)
+	require.Equal(t, keeper.GetValidatorSetUncached(ctx), cached)
+
+	// reads from a discarded branch do not leak into the parent
+	branch, _ := ctx.CacheContext()
+	validatorSet.Validators[0].VotingPower = 2000
+	require.NoError(t, keeper.UpdateValidatorSetInStore(branch, validatorSet))
+	require.Equal(t, int64(2000), keeper.GetValidatorSet(branch).Validators[0].VotingPower)
+	require.Equal(t, int64(1000), keeper.GetValidatorSet(ctx).Validators[0].VotingPower)
+}
+
+func BenchmarkGetCurrentProposer(b *testing.B) {
+	app, ctx, _ := createTestApp(false)
+	keeper := app.StakingKeeper
+
+	var validatorSet hmTypes.ValidatorSet
+	for _, validator := range stakingSim.GenRandomVal(100, 0, 10, 10, false, 1) {
+		validator := validator
+		require.NoError(b, keeper.AddValidator(ctx, validator))
+		validatorSet.UpdateWithChangeSet([]*hmTypes.Validator{&validator})
+	}
+	require.NoError(b, keeper.UpdateValidatorSetInStore(ctx, validatorSet))
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			keeper.GetCurrentProposer(ctx)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			validatorSet := keeper.GetValidatorSetUncached(ctx)
+			validatorSet.GetProposer()
+		}
+	})
+}
+
+func BenchmarkGetNextStakingRecordFromQueue(b *testing.B) {
+	app, ctx, _ := createTestApp(false)
+	k := app.StakingKeeper
+	rootID := hmTypes.GetRootChainID(hmTypes.RootChainTypeEth)
+
+	for i := 0; i < 1000; i++ {
+		k.AddStakingRecordToQueue(ctx, rootID, stakingTypes.NewPowerUpdateRecord(hmTypes.ValidatorID(i), uint64(i), 100, ctx.BlockHeight(), hmTypes.BytesToHeimdallHash([]byte{byte(i)})))
+	}
+	store := ctx.KVStore(app.GetKey(stakingTypes.StoreKey))
+
+	b.Run("head", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = k.GetNextStakingRecordFromQueue(ctx, rootID)
+		}
+	})
+
+	b.Run("full", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			records, _ := k.DecodeStakingQueue(store.Get(staking.GetStakingQueueKey(rootID)))
+			_ = records[0]
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package staking
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	hmTypes "github.com/maticnetwork/heimdall/types"
+)
+
+const metricsNamespace = "staking"
+
+var (
+	// validatorSetSize tracks the number of validators in the current validator set
+	validatorSetSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "validator_set",
+		Name:      "size",
+		Help:      "Number of validators in the current validator set.",
+	})
+
+	// validatorSetTotalPower tracks the total voting power of the current validator set
+	validatorSetTotalPower = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "validator_set",
+		Name:      "total_power",
+		Help:      "Total voting power of the current validator set.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(validatorSetSize, validatorSetTotalPower)
+}
+
+// recordValidatorSet reports size and total power of the given validator set
+func recordValidatorSet(validatorSet *hmTypes.ValidatorSet) {
+	var totalPower int64
+	for _, validator := range validatorSet.Validators {
+		totalPower += validator.VotingPower
+	}
+
+	validatorSetSize.Set(float64(len(validatorSet.Validators)))
+	validatorSetTotalPower.Set(float64(totalPower))
+}
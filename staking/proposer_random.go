@@ -0,0 +1,49 @@
+package staking
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	hmTypes "github.com/maticnetwork/heimdall/types"
+)
+
+//
+// Weighted random proposer selection, for test and simulation modes only.
+// Consensus keeps using the accum based rotation of GetNextProposer and GetCurrentProposer.
+//
+
+// GetProposerWeightedRandom picks a current validator with probability proportional to its voting power,
+// using seed (e.g. a block hash) as the only source of randomness. The stored set and its accums are left untouched.
+func (k *Keeper) GetProposerWeightedRandom(ctx sdk.Context, seed []byte) (*hmTypes.Validator, error) {
+	// get validator set
+	validatorSet := k.GetValidatorSet(ctx)
+	if validatorSet.IsNilOrEmpty() {
+		return nil, errors.New("empty validator set")
+	}
+
+	totalPower := validatorSet.TotalVotingPower()
+	if totalPower <= 0 {
+		return nil, errors.New("validator set has no voting power")
+	}
+
+	// reduce the 256-bit seed hash into [0, totalPower)
+	hash := sha256.Sum256(seed)
+	target := new(big.Int).Mod(new(big.Int).SetBytes(hash[:]), big.NewInt(totalPower)).Int64()
+
+	// validators are kept sorted in the set, so the walk is deterministic for a seed
+	var cumulative int64
+	for _, validator := range validatorSet.Validators {
+		if validator.VotingPower <= 0 {
+			continue
+		}
+		cumulative += validator.VotingPower
+		if target < cumulative {
+			return validator.Copy(), nil
+		}
+	}
+
+	return nil, errors.New("no proposer selected")
+}
@@ -38,6 +38,8 @@ func NewQuerier(keeper Keeper, contractCaller helper.IContractCaller) sdk.Querie
 			return handleQueryNextStaking(ctx, req, keeper)
 		case types.QueryStakingQueue:
 			return handleQueryStakingQueue(ctx, req, keeper)
+		case types.QueryValidatorSetSnapshot:
+			return handleQueryValidatorSetSnapshot(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown staking query endpoint")
 		}
@@ -66,6 +68,15 @@ func handleQueryCurrentValidatorSet(ctx sdk.Context, req abci.RequestQuery, keep
 	return bz, nil
 }
 
+func handleQueryValidatorSetSnapshot(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	// json record
+	bz, err := json.Marshal(keeper.GetValidatorSetSnapshot(ctx))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
+
 func handleQuerySigner(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
 	var params types.QuerySignerParams
 	if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
@@ -93,9 +104,9 @@ func handleQueryValidator(ctx sdk.Context, req abci.RequestQuery, keeper Keeper)
 	}
 
 	// get validator info
-	validator, ok := keeper.GetValidatorFromValID(ctx, params.ValidatorID)
-	if !ok {
-		return nil, sdk.ErrUnknownRequest("No validator found")
+	validator, found, reason := keeper.GetValidatorByIDDetailed(ctx, params.ValidatorID)
+	if !found {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("No validator found: %s", reason))
 	}
 
 	// json record
@@ -2,6 +2,7 @@ package staking
 
 import (
 	"bytes"
+	"errors"
 	"math/big"
 	"strconv"
 
@@ -458,48 +459,42 @@ func PostHandleMsgValidatorJoin(ctx sdk.Context, k Keeper, msg types.MsgValidato
 	}
 
 	newValidator.VotingPower = 1
-	// update last updated
-	newValidator.LastUpdated = sequence.String()
 
-	// add validator to store
+	// set self stake from staked amount, join carries no delegated amount
+	if selfStake, err := helper.GetPowerFromAmount(msg.Amount.BigInt()); err == nil {
+		newValidator.SelfStake = selfStake.Int64()
+	}
+	newValidator.VotingPower = k.PowerFromStake(ctx, newValidator)
+
+	// TX bytes
+	txBytes := ctx.TxBytes()
+	hash := tmTypes.Tx(txBytes).Hash()
+
+	// add validator and its signing info to store, emitting the join event
 	k.Logger(ctx).Debug("Adding new validator to state", "validator", newValidator.String())
-	err := k.AddValidator(ctx, newValidator)
-	if err != nil {
+	if err := k.OnboardValidator(
+		ctx,
+		newValidator,
+		sequence.String(),
+		sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),                                  // action
+		sdk.NewAttribute(hmTypes.AttributeKeyTxHash, hmTypes.BytesToHeimdallHash(hash).Hex()), // tx hash
+		sdk.NewAttribute(hmTypes.AttributeKeyTxLogIndex, strconv.FormatUint(msg.LogIndex, 10)),
+		sdk.NewAttribute(hmTypes.AttributeKeySideTxResult, sideTxResult.String()), // result
+	); err != nil {
 		k.Logger(ctx).Error("Unable to add validator to state", "error", err, "validator", newValidator.String())
+		if errors.Is(err, ErrValidatorExists) {
+			return hmCommon.ErrValidatorAlreadyJoined(k.Codespace()).Result()
+		}
+		if errors.Is(err, ErrValidatorSigningInfo) {
+			return hmCommon.ErrValidatorSigningInfoSave(k.Codespace()).Result()
+		}
 		return hmCommon.ErrValidatorSave(k.Codespace()).Result()
 	}
 
-	// Add Validator signing info. It is required for slashing module
-	k.Logger(ctx).Debug("Adding signing info for new validator")
-	valSigningInfo := hmTypes.NewValidatorSigningInfo(newValidator.ID, ctx.BlockHeight(), int64(0), int64(0))
-	err = k.AddValidatorSigningInfo(ctx, newValidator.ID, valSigningInfo)
-	if err != nil {
-		k.Logger(ctx).Error("Unable to add validator signing info to state", "error", err, "valSigningInfo", valSigningInfo.String())
-		return hmCommon.ErrValidatorSigningInfoSave(k.Codespace()).Result()
-	}
-
 	// save staking sequence
 	k.SetStakingSequence(ctx, sequence.String())
 	k.Logger(ctx).Debug("✅ New validator successfully joined", "validator", strconv.FormatUint(newValidator.ID.Uint64(), 10))
 
-	// TX bytes
-	txBytes := ctx.TxBytes()
-	hash := tmTypes.Tx(txBytes).Hash()
-
-	ctx.EventManager().EmitEvents(sdk.Events{
-		sdk.NewEvent(
-			types.EventTypeValidatorJoin,
-			sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),                                  // action
-			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),                // module name
-			sdk.NewAttribute(hmTypes.AttributeKeyTxHash, hmTypes.BytesToHeimdallHash(hash).Hex()), // tx hash
-			sdk.NewAttribute(hmTypes.AttributeKeyTxLogIndex, strconv.FormatUint(msg.LogIndex, 10)),
-			sdk.NewAttribute(hmTypes.AttributeKeySideTxResult, sideTxResult.String()), // result
-			sdk.NewAttribute(types.AttributeKeyValidatorID, strconv.FormatUint(newValidator.ID.Uint64(), 10)),
-			sdk.NewAttribute(types.AttributeKeySigner, newValidator.Signer.String()),
-			sdk.NewAttribute(types.AttributeKeyValidatorNonce, strconv.FormatUint(msg.Nonce, 10)),
-		),
-	})
-
 	// save staking record
 	for root, rootID := range hmTypes.GetRootChainIDMap() {
 		if root != hmTypes.RootChainTypeStake {
@@ -624,7 +619,7 @@ func PostHandleMsgSignerUpdate(ctx sdk.Context, k Keeper, msg types.MsgSignerUpd
 	k.Logger(ctx).Debug("Persisting signer update", "sideTxResult", sideTxResult)
 
 	newPubKey := msg.NewSignerPubKey
-	newSigner := newPubKey.Address()
+	newSigner := hmTypes.BytesToHeimdallAddress(newPubKey.Address().Bytes())
 
 	// pull validator from store
 	validator, ok := k.GetValidatorFromValID(ctx, msg.ID)
@@ -634,52 +629,25 @@ func PostHandleMsgSignerUpdate(ctx sdk.Context, k Keeper, msg types.MsgSignerUpd
 	}
 	oldValidator := validator.Copy()
 
-	// update last udpated
+	// stamp last updated and nonce, the swap carries them to both signer records
+	cacheCtx, writeCache := ctx.CacheContext()
 	validator.LastUpdated = sequence.String()
-
-	// update nonce
 	validator.Nonce = msg.Nonce
-
-	// check if we are actually updating signer
-	if !bytes.Equal(newSigner.Bytes(), validator.Signer.Bytes()) {
-		// Update signer in prev Validator
-		validator.Signer = hmTypes.HeimdallAddress(newSigner)
-		validator.PubKey = newPubKey
-		k.Logger(ctx).Debug("Updating new signer", "newSigner", newSigner.String(), "oldSigner", oldValidator.Signer.String(), "validatorID", msg.ID)
-	} else {
-		k.Logger(ctx).Error("No signer change", "newSigner", newSigner.String(), "oldSigner", oldValidator.Signer.String(), "validatorID", msg.ID)
-		return hmCommon.ErrSignerUpdateError(k.Codespace()).Result()
-	}
-
-	k.Logger(ctx).Debug("Removing old validator", "validator", oldValidator.String())
-
-	// remove old validator from HM
-	oldValidator.EndEpoch = k.moduleCommunicator.GetACKCount(ctx)
-
-	// remove old validator from TM
-	oldValidator.VotingPower = 0
-	// updated last
-	oldValidator.LastUpdated = sequence.String()
-
-	// updated nonce
-	oldValidator.Nonce = msg.Nonce
-
-	// save old validator
-	if err := k.AddValidator(ctx, *oldValidator); err != nil {
+	if err := k.AddValidator(cacheCtx, validator); err != nil {
 		k.Logger(ctx).Error("Unable to update signer", "error", err, "validatorId", validator.ID)
 		return hmCommon.ErrSignerUpdateError(k.Codespace()).Result()
 	}
 
-	// adding new validator
-	k.Logger(ctx).Debug("Adding new validator", "validator", validator.String())
-
-	// save validator
-	err := k.AddValidator(ctx, validator)
-	if err != nil {
-		k.Logger(ctx).Error("Unable to update signer", "error", err, "ValidatorID", validator.ID)
+	// move validator to new signer, rejecting a signer or pubkey in use
+	k.Logger(ctx).Debug("Updating new signer", "newSigner", newSigner.String(), "oldSigner", oldValidator.Signer.String(), "validatorID", msg.ID)
+	if err := k.SwapSigner(cacheCtx, msg.ID, newSigner, newPubKey); err != nil {
+		k.Logger(ctx).Error("Unable to update signer", "error", err, "newSigner", newSigner.String(), "oldSigner", oldValidator.Signer.String(), "validatorID", msg.ID)
 		return hmCommon.ErrSignerUpdateError(k.Codespace()).Result()
 	}
 
+	writeCache()
+	validator.Signer = newSigner
+
 	// save staking sequence
 	k.SetStakingSequence(ctx, sequence.String())
 
@@ -1172,10 +1172,12 @@ func (suite *SideHandlerTestSuite) TestPostHandleMsgValidatorJoin() {
 		actualResult, ok := app.StakingKeeper.GetValidatorFromValID(ctx, hmTypes.ValidatorID(validatorId))
 		require.True(t, ok, "Should add validator")
 		require.NotNil(t, actualResult, "got %v", actualResult)
+		require.Equal(t, int64(1), actualResult.GetSelfStake(), "Self stake should be staked amount")
+		require.Equal(t, int64(0), actualResult.GetDelegatedStake())
 	})
 
-	suite.Run("Replay", func() {
-		blockNumber := big.NewInt(11)
+	suite.Run("Already joined", func() {
+		blockNumber := big.NewInt(12)
 
 		msgValJoin := types.NewMsgValidatorJoin(
 			hmTypes.BytesToHeimdallAddress(address.Bytes()),
@@ -1189,15 +1191,37 @@ func (suite *SideHandlerTestSuite) TestPostHandleMsgValidatorJoin() {
 			nonce.Uint64(),
 		)
 
+		result := suite.postHandler(ctx, msgValJoin, abci.SideTxResultType_Yes)
+		require.False(t, result.IsOK(), "expected validator join to fail for joined validator")
+		require.Equal(t, common.CodeValAlreadyJoined, result.Code)
+	})
+
+	suite.Run("Replay", func() {
+		blockNumber := big.NewInt(11)
+		replayPubkey := hmTypes.NewPubKey(secp256k1.GenPrivKey().PubKey().Bytes())
+
+		msgValJoin := types.NewMsgValidatorJoin(
+			hmTypes.BytesToHeimdallAddress(replayPubkey.Address().Bytes()),
+			validatorId+1,
+			uint64(1),
+			sdk.NewInt(1000000000000000000),
+			replayPubkey,
+			txHash,
+			logIndex,
+			blockNumber.Uint64(),
+			nonce.Uint64(),
+		)
+
 		result := suite.postHandler(ctx, msgValJoin, abci.SideTxResultType_Yes)
 		require.True(t, result.IsOK(), "expected validator join to be ok, got %v", result)
 
-		actualResult, ok := app.StakingKeeper.GetValidatorFromValID(ctx, hmTypes.ValidatorID(validatorId))
+		actualResult, ok := app.StakingKeeper.GetValidatorFromValID(ctx, hmTypes.ValidatorID(validatorId+1))
 		require.True(t, ok, "Should add validator")
 		require.NotNil(t, actualResult, "got %v", actualResult)
 
 		result = suite.postHandler(ctx, msgValJoin, abci.SideTxResultType_Yes)
 		require.False(t, result.IsOK(), "expected validator join to be ok, got %v", result)
+		require.Equal(t, common.CodeOldTx, result.Code)
 	})
 
 	suite.Run("Invalid Power", func() {
@@ -1241,7 +1265,23 @@ func (suite *SideHandlerTestSuite) TestPostHandleMsgSignerUpdate() {
 		require.True(t, !result.IsOK(), errs.CodeToDefaultMsg(result.Code))
 	})
 
+	suite.Run("Pubkey in use", func() {
+		otherSigner := oldValSet.Validators[1]
+		msg := types.NewMsgSignerUpdate(otherSigner.Signer, uint64(oldSigner.ID), otherSigner.PubKey, msgTxHash, 0, blockNumber.Uint64(), nonce.Uint64())
+
+		result := suite.postHandler(ctx, msg, abci.SideTxResultType_Yes)
+		require.False(t, result.IsOK(), "Post handler should reject a pubkey of another validator")
+
+		// nothing is written
+		validator, ok := keeper.GetValidatorFromValID(ctx, oldSigner.ID)
+		require.True(t, ok)
+		require.Equal(t, oldSigner.Signer, validator.Signer)
+		require.Equal(t, oldSigner.Nonce, validator.Nonce)
+		require.NoError(t, keeper.CheckSignerUniqueness(ctx))
+	})
+
 	suite.Run("Success", func() {
+		app.CheckpointKeeper.UpdateACKCountWithValue(ctx, 3, hmTypes.RootChainTypeStake)
 		msg := types.NewMsgSignerUpdate(newSigner[0].Signer, uint64(oldSigner.ID), newSigner[0].PubKey, msgTxHash, 0, blockNumber.Uint64(), nonce.Uint64())
 
 		result := suite.postHandler(ctx, msg, abci.SideTxResultType_Yes)
@@ -1262,6 +1302,15 @@ func (suite *SideHandlerTestSuite) TestPostHandleMsgSignerUpdate() {
 		removedVal, err := keeper.GetValidatorInfo(ctx, oldSigner.Signer.Bytes())
 		require.Empty(t, err, "deleted validator should be found, got %v", err)
 		require.Equal(t, removedVal.VotingPower, int64(0), "removed validator VotingPower should be zero")
+
+		// both signer records are stamped, only the old one ends
+		sequence := new(big.Int).Mul(blockNumber, big.NewInt(hmTypes.DefaultLogIndexUnit)).String()
+		require.Equal(t, nonce.Uint64(), ValFrmID.Nonce)
+		require.Equal(t, sequence, ValFrmID.LastUpdated)
+		require.Equal(t, uint64(0), ValFrmID.EndEpoch)
+		require.Equal(t, nonce.Uint64(), removedVal.Nonce)
+		require.Equal(t, sequence, removedVal.LastUpdated)
+		require.Equal(t, uint64(3), removedVal.EndEpoch)
 	})
 }
 
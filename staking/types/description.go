@@ -0,0 +1,67 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"unicode/utf8"
+)
+
+// Max lengths of validator description fields
+const (
+	MaxMonikerLength  = 70
+	MaxIdentityLength = 3000
+	MaxWebsiteLength  = 140
+	MaxDetailsLength  = 280
+)
+
+// Description is human readable validator metadata, stored apart from the validator record
+type Description struct {
+	Moniker  string `json:"moniker"`  // display name
+	Identity string `json:"identity"` // optional identity signature, eg. keybase
+	Website  string `json:"website"`  // optional http(s) url
+	Details  string `json:"details"`  // optional free text
+}
+
+// NewDescription creates validator description
+func NewDescription(moniker, identity, website, details string) Description {
+	return Description{
+		Moniker:  moniker,
+		Identity: identity,
+		Website:  website,
+		Details:  details,
+	}
+}
+
+// Validate checks field lengths, a non-empty moniker and that website is an http(s) url
+func (d Description) Validate() error {
+	if d.Moniker == "" {
+		return fmt.Errorf("empty moniker")
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"moniker", d.Moniker, MaxMonikerLength},
+		{"identity", d.Identity, MaxIdentityLength},
+		{"website", d.Website, MaxWebsiteLength},
+		{"details", d.Details, MaxDetailsLength},
+	} {
+		if !utf8.ValidString(field.value) {
+			return fmt.Errorf("invalid %v encoding", field.name)
+		}
+		if length := utf8.RuneCountInString(field.value); length > field.max {
+			return fmt.Errorf("invalid %v length %v, max %v", field.name, length, field.max)
+		}
+	}
+
+	if d.Website != "" {
+		website, err := url.ParseRequestURI(d.Website)
+		if err != nil || (website.Scheme != "http" && website.Scheme != "https") || website.Host == "" {
+			return fmt.Errorf("invalid website %v", d.Website)
+		}
+	}
+
+	return nil
+}
@@ -10,6 +10,10 @@ var (
 	EventTypeStakingSync    = "staking-sync"
 	EventTypeStakingSyncAck = "staking-ack"
 
+	EventTypeCommissionUpdate  = "commission-update"
+	EventTypeDescriptionUpdate = "description-update"
+	EventTypePowerUpdate       = "power-update"
+
 	AttributeKeySigner            = "signer"
 	AttributeKeyDeactivationEpoch = "deactivation-epoch"
 	AttributeKeyActivationEpoch   = "activation-epoch"
@@ -17,6 +21,10 @@ var (
 	AttributeKeyValidatorNonce    = "validator-nonce"
 	AttributeKeyUpdatedAt         = "updated-at"
 	AttributeKeyRootChain         = "root-chain"
+	AttributeKeyCommissionRate    = "commission-rate"
+	AttributeKeyMoniker           = "moniker"
+	AttributeKeyPower             = "power"
+	AttributeKeyPreviousPower     = "previous-power"
 
 	AttributeValueCategory = ModuleName
 )
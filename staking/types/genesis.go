@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/maticnetwork/heimdall/bor/types"
 	hmTypes "github.com/maticnetwork/heimdall/types"
@@ -38,6 +39,13 @@ type GenesisState struct {
 	Validators       []*hmTypes.Validator `json:"validators" yaml:"validators"`
 	CurrentValSet    hmTypes.ValidatorSet `json:"current_val_set" yaml:"current_val_set"`
 	StakingSequences []string             `json:"staking_sequences" yaml:"staking_sequences"`
+
+	// ArchivedValidators are the validators removed by pruning, their ID map entries are restored with them
+	ArchivedValidators []*hmTypes.Validator `json:"archived_validators,omitempty" yaml:"archived_validators,omitempty"`
+
+	// ValidatorSetAckCount is the ack count at which the current validator set was in sync with
+	// validators, nil if it had pending updates. The ack count is only restored by checkpoint genesis.
+	ValidatorSetAckCount *uint64 `json:"validator_set_ack_count,omitempty" yaml:"validator_set_ack_count,omitempty"`
 }
 
 // NewGenesisState creates a new genesis state.
@@ -68,6 +76,17 @@ func ValidateGenesis(data GenesisState) error {
 			return errors.New("Invalid validator")
 		}
 	}
+	for _, validator := range data.ArchivedValidators {
+		if !validator.ValidateBasic() {
+			return errors.New("Invalid archived validator")
+		}
+	}
+	if err := ValidateTotalVotingPower(data.Validators); err != nil {
+		return err
+	}
+	if err := ValidateTotalVotingPower(data.CurrentValSet.Validators); err != nil {
+		return err
+	}
 	for _, sq := range data.StakingSequences {
 		if sq == "" {
 			return errors.New("Invalid Sequence")
@@ -77,6 +96,24 @@ func ValidateGenesis(data GenesisState) error {
 	return nil
 }
 
+// ValidateTotalVotingPower checks that the voting power of validators sums to at most hmTypes.MaxTotalVotingPower,
+// which leaves headroom below int64 for proposer priority arithmetic
+func ValidateTotalVotingPower(validators []*hmTypes.Validator) error {
+	var totalPower int64
+	for _, validator := range validators {
+		if validator.VotingPower < 0 {
+			return fmt.Errorf("validator %v has negative voting power %v", validator.ID, validator.VotingPower)
+		}
+		// compare before adding, the sum itself may not fit int64
+		if validator.VotingPower > hmTypes.MaxTotalVotingPower-totalPower {
+			return fmt.Errorf("total voting power exceeds maximum %v at validator %v", hmTypes.MaxTotalVotingPower, validator.ID)
+		}
+		totalPower += validator.VotingPower
+	}
+
+	return nil
+}
+
 // GetGenesisStateFromAppState returns staking GenesisState given raw application genesis state
 func GetGenesisStateFromAppState(appState map[string]json.RawMessage) GenesisState {
 	var genesisState GenesisState
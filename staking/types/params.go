@@ -13,24 +13,68 @@ const (
 
 	// DefaultProposerBonusPercent - Proposer Signer Reward Ratio
 	DefaultStakingBufferTime = 600 * time.Second
+
+	DefaultEnableValidatorPruning    = false
+	DefaultValidatorPruneGracePeriod = uint64(256) // checkpoint acks after EndEpoch
+
+	DefaultValidatorSetHistoryRetention = uint64(0) // blocks, historical validator sets are not retained by default
+
+	DefaultMaxPowerSharePercent = uint64(0) // percent of total voting power, 0 disables the cap
+
+	DefaultValidatorGracePeriod = uint64(0) // epochs a validator stays current after EndEpoch
+
+	DefaultPowerDivisor = uint64(0) // stake in power units per unit of voting power, 0 keeps powers set on join
+
+	DefaultMinValidatorPower = uint64(0) // voting power, 0 disables the floor
 )
 
 // Parameter keys
 var (
-	KeyStakingBufferTime = []byte("StakingBufferTime")
+	KeyStakingBufferTime         = []byte("StakingBufferTime")
+	KeyEnableValidatorPruning    = []byte("EnableValidatorPruning")
+	KeyValidatorPruneGracePeriod = []byte("ValidatorPruneGracePeriod")
+
+	KeyValidatorSetHistoryRetention = []byte("ValidatorSetHistoryRetention")
+	KeyMaxPowerSharePercent         = []byte("MaxPowerSharePercent")
+	KeyValidatorGracePeriod         = []byte("ValidatorGracePeriod")
+	KeyPowerDivisor                 = []byte("PowerDivisor")
+	KeyMinValidatorPower            = []byte("MinValidatorPower")
 )
 
 var _ subspace.ParamSet = &Params{}
 
 // Params defines the parameters for the auth module.
 type Params struct {
-	StakingBufferTime time.Duration `json:"staking_buffer_time" yaml:"staking_buffer_time"`
+	StakingBufferTime         time.Duration `json:"staking_buffer_time" yaml:"staking_buffer_time"`
+	EnableValidatorPruning    bool          `json:"enable_validator_pruning" yaml:"enable_validator_pruning"`
+	ValidatorPruneGracePeriod uint64        `json:"validator_prune_grace_period" yaml:"validator_prune_grace_period"`
+
+	ValidatorSetHistoryRetention uint64 `json:"validator_set_history_retention" yaml:"validator_set_history_retention"`
+	MaxPowerSharePercent         uint64 `json:"max_power_share_percent" yaml:"max_power_share_percent"`
+
+	// ValidatorGracePeriod keeps unstaked validators in the current set for this many epochs after EndEpoch.
+	// It decides validator set membership, changing it moves validators in or out of the set at the next end block.
+	ValidatorGracePeriod uint64 `json:"validator_grace_period" yaml:"validator_grace_period"`
+
+	// PowerDivisor scales total stake down to voting power, 0 keeps the power validators get on join.
+	// Changing it to non-zero recomputes the power of every validator with stake at the next end block.
+	PowerDivisor uint64 `json:"power_divisor" yaml:"power_divisor"`
+
+	// MinValidatorPower excludes validators with less voting power from the current validator set, they stay stored.
+	MinValidatorPower uint64 `json:"min_validator_power" yaml:"min_validator_power"`
 }
 
 // NewParams creates a new Params object
-func NewParams(stakingBufferTime time.Duration) Params {
+func NewParams(stakingBufferTime time.Duration, enableValidatorPruning bool, validatorPruneGracePeriod uint64, validatorSetHistoryRetention uint64, maxPowerSharePercent uint64, validatorGracePeriod uint64, powerDivisor uint64, minValidatorPower uint64) Params {
 	return Params{
-		StakingBufferTime: stakingBufferTime,
+		StakingBufferTime:            stakingBufferTime,
+		EnableValidatorPruning:       enableValidatorPruning,
+		ValidatorPruneGracePeriod:    validatorPruneGracePeriod,
+		ValidatorSetHistoryRetention: validatorSetHistoryRetention,
+		MaxPowerSharePercent:         maxPowerSharePercent,
+		ValidatorGracePeriod:         validatorGracePeriod,
+		PowerDivisor:                 powerDivisor,
+		MinValidatorPower:            minValidatorPower,
 	}
 }
 
@@ -45,6 +89,13 @@ func ParamKeyTable() subspace.KeyTable {
 func (p *Params) ParamSetPairs() subspace.ParamSetPairs {
 	return subspace.ParamSetPairs{
 		{KeyStakingBufferTime, &p.StakingBufferTime},
+		{KeyEnableValidatorPruning, &p.EnableValidatorPruning},
+		{KeyValidatorPruneGracePeriod, &p.ValidatorPruneGracePeriod},
+		{KeyValidatorSetHistoryRetention, &p.ValidatorSetHistoryRetention},
+		{KeyMaxPowerSharePercent, &p.MaxPowerSharePercent},
+		{KeyValidatorGracePeriod, &p.ValidatorGracePeriod},
+		{KeyPowerDivisor, &p.PowerDivisor},
+		{KeyMinValidatorPower, &p.MinValidatorPower},
 	}
 }
 
@@ -57,7 +108,16 @@ func (p Params) Equal(p2 Params) bool {
 
 // DefaultParams returns a default set of parameters.
 func DefaultParams() Params {
-	return Params{StakingBufferTime: DefaultStakingBufferTime}
+	return Params{
+		StakingBufferTime:            DefaultStakingBufferTime,
+		EnableValidatorPruning:       DefaultEnableValidatorPruning,
+		ValidatorPruneGracePeriod:    DefaultValidatorPruneGracePeriod,
+		ValidatorSetHistoryRetention: DefaultValidatorSetHistoryRetention,
+		MaxPowerSharePercent:         DefaultMaxPowerSharePercent,
+		ValidatorGracePeriod:         DefaultValidatorGracePeriod,
+		PowerDivisor:                 DefaultPowerDivisor,
+		MinValidatorPower:            DefaultMinValidatorPower,
+	}
 }
 
 // String implements the stringer interface.
@@ -65,6 +125,13 @@ func (p Params) String() string {
 	var sb strings.Builder
 	sb.WriteString("Params: \n")
 	sb.WriteString(fmt.Sprintf("CheckpointBufferTime: %s\n", p.StakingBufferTime))
+	sb.WriteString(fmt.Sprintf("EnableValidatorPruning: %v\n", p.EnableValidatorPruning))
+	sb.WriteString(fmt.Sprintf("ValidatorPruneGracePeriod: %d\n", p.ValidatorPruneGracePeriod))
+	sb.WriteString(fmt.Sprintf("ValidatorSetHistoryRetention: %d\n", p.ValidatorSetHistoryRetention))
+	sb.WriteString(fmt.Sprintf("MaxPowerSharePercent: %d\n", p.MaxPowerSharePercent))
+	sb.WriteString(fmt.Sprintf("ValidatorGracePeriod: %d\n", p.ValidatorGracePeriod))
+	sb.WriteString(fmt.Sprintf("PowerDivisor: %d\n", p.PowerDivisor))
+	sb.WriteString(fmt.Sprintf("MinValidatorPower: %d\n", p.MinValidatorPower))
 	return sb.String()
 }
 
@@ -73,5 +140,9 @@ func (p Params) Validate() error {
 	if p.StakingBufferTime == 0 {
 		return fmt.Errorf("StakingBufferTime, AvgCheckpointLength should be non-zero")
 	}
+
+	if p.MaxPowerSharePercent > 100 {
+		return fmt.Errorf("MaxPowerSharePercent should not exceed 100")
+	}
 	return nil
 }
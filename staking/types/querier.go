@@ -17,6 +17,7 @@ const (
 	QueryStakingSequence      = "staking-sequence"
 	QueryNextStaking          = "staking-next"
 	QueryStakingQueue         = "staking-queue"
+	QueryValidatorSetSnapshot = "validator-set-snapshot"
 )
 
 // QuerySignerParams defines the params for querying by address
@@ -39,6 +40,32 @@ func NewQueryValidatorParams(validatorID types.ValidatorID) QueryValidatorParams
 	return QueryValidatorParams{ValidatorID: validatorID}
 }
 
+// ValidatorLookupReason describes the outcome of a validator lookup by ID
+type ValidatorLookupReason int
+
+const (
+	// ValidatorLookupFound validator ID is mapped and the validator exists
+	ValidatorLookupFound ValidatorLookupReason = iota
+	// ValidatorLookupIDUnmapped validator ID is not mapped to any signer
+	ValidatorLookupIDUnmapped
+	// ValidatorLookupValidatorMissing validator ID is mapped but no validator is stored for the signer
+	ValidatorLookupValidatorMissing
+)
+
+// String returns human readable reason
+func (r ValidatorLookupReason) String() string {
+	switch r {
+	case ValidatorLookupFound:
+		return "validator found"
+	case ValidatorLookupIDUnmapped:
+		return "validator ID not mapped to a signer"
+	case ValidatorLookupValidatorMissing:
+		return "validator not found for mapped signer"
+	default:
+		return "unknown"
+	}
+}
+
 // QueryProposerParams defines the params for querying val status.
 type QueryProposerParams struct {
 	Times uint64 `json:"times"`
@@ -88,3 +115,18 @@ func NewQueryStakingParams(number uint64, rootChain string) QueryStakingParams {
 		RootChain: rootChain,
 	}
 }
+
+// ValidatorSetDiff defines validators added, removed and with changed power between two validator sets
+type ValidatorSetDiff struct {
+	Added        []types.Validator `json:"added"`
+	Removed      []types.Validator `json:"removed"`
+	PowerChanged []types.Validator `json:"power_changed"`
+}
+
+// ValidatorSetSnapshot defines the current validator set along with its proposer, power and epoch
+type ValidatorSetSnapshot struct {
+	ValidatorSet types.ValidatorSet `json:"validator_set"`
+	Proposer     *types.Validator   `json:"proposer"`
+	TotalPower   int64              `json:"total_power"`
+	Epoch        uint64             `json:"epoch"`
+}
@@ -7,7 +7,22 @@ import (
 	hmtypes "github.com/maticnetwork/heimdall/types"
 )
 
+// StakingQueueOrder defines how the next record is picked from the staking queue
+type StakingQueueOrder int
+
+const (
+	// StakingQueueOrderInsertion returns records in insertion order
+	StakingQueueOrderInsertion StakingQueueOrder = iota
+	// StakingQueueOrderLowestNonce returns the lowest-nonce record of a given validator
+	StakingQueueOrderLowestNonce
+)
+
+// StakingRecordTypePowerUpdate is the type of records carrying a validator power change
+const StakingRecordTypePowerUpdate = "powerUpdate"
+
 // StakingRecord struct
+// Records are stored as an amino encoded queue, so fields must stay fixed-order scalars (no maps)
+// to keep the encoding byte-identical across nodes.
 type StakingRecord struct {
 	Type        string               `json:"type"`
 	ValidatorID hmTypes.ValidatorID  `json:"id"`
@@ -15,17 +30,36 @@ type StakingRecord struct {
 	Height      int64                `json:"height"`
 	TxHash      hmtypes.HeimdallHash `json:"tx_hash"`
 	TimeStamp   uint64               `json:"timestamp"`
+	Power       int64                `json:"power"` // new voting power, power update records only
+}
+
+// NewPowerUpdateRecord creates staking record for a validator power change
+func NewPowerUpdateRecord(validatorID hmTypes.ValidatorID, nonce uint64, power int64, height int64, txHash hmtypes.HeimdallHash) StakingRecord {
+	return StakingRecord{
+		Type:        StakingRecordTypePowerUpdate,
+		ValidatorID: validatorID,
+		Nonce:       nonce,
+		Height:      height,
+		TxHash:      txHash,
+		Power:       power,
+	}
+}
+
+// IsPowerUpdate checks if record is a power update
+func (s StakingRecord) IsPowerUpdate() bool {
+	return s.Type == StakingRecordTypePowerUpdate
 }
 
 // String returns human readable string
 func (s StakingRecord) String() string {
 	return fmt.Sprintf(
-		"StakingRecord {%v %v %v %v %v %v}",
+		"StakingRecord {%v %v %v %v %v %v %v}",
 		s.Type,
 		s.ValidatorID,
 		s.Nonce,
 		s.Height,
 		s.TxHash.Hex(),
 		s.TimeStamp,
+		s.Power,
 	)
 }
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/maticnetwork/heimdall/types"
+)
+
+func TestStakingRecordHasNoMapFields(t *testing.T) {
+	t.Parallel()
+
+	recordType := reflect.TypeOf(StakingRecord{})
+	for i := 0; i < recordType.NumField(); i++ {
+		field := recordType.Field(i)
+		require.NotEqual(t, reflect.Map, field.Type.Kind(), "field %s would make queue encoding nondeterministic", field.Name)
+	}
+}
+
+func TestStakingQueueEncodingIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	newQueue := func() []StakingRecord {
+		return []StakingRecord{
+			NewPowerUpdateRecord(types.NewValidatorID(2), 7, 100, 10, types.BytesToHeimdallHash([]byte{0x01})),
+			{
+				Type:        "stakeUpdate",
+				ValidatorID: types.NewValidatorID(1),
+				Nonce:       3,
+				Height:      11,
+				TxHash:      types.BytesToHeimdallHash([]byte{0x02}),
+				TimeStamp:   1600000000,
+			},
+		}
+	}
+
+	first, err := ModuleCdc.MarshalBinaryBare(newQueue())
+	require.NoError(t, err)
+
+	second, err := ModuleCdc.MarshalBinaryBare(newQueue())
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	var decoded []StakingRecord
+	require.NoError(t, ModuleCdc.UnmarshalBinaryBare(first, &decoded))
+	require.Equal(t, newQueue(), decoded)
+}
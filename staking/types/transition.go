@@ -0,0 +1,32 @@
+package types
+
+import (
+	"github.com/maticnetwork/heimdall/types"
+)
+
+// SetTransitionOpType is the kind of change a set transition operation makes
+type SetTransitionOpType string
+
+// Set transition operation types, in the order they are applied
+const (
+	SetTransitionRemove SetTransitionOpType = "remove"
+	SetTransitionUpdate SetTransitionOpType = "update"
+	SetTransitionAdd    SetTransitionOpType = "add"
+)
+
+// SetTransitionOp is a single validator change moving the current validator set towards a target set.
+// Removed validators carry zero power, so the validators of all operations form a change set.
+type SetTransitionOp struct {
+	Type      SetTransitionOpType `json:"type"`
+	Validator types.Validator     `json:"validator"`
+}
+
+// ChangeSet returns validators of the operations, to apply with ValidatorSet.UpdateWithChangeSet
+func ChangeSet(ops []SetTransitionOp) []*types.Validator {
+	changes := make([]*types.Validator, 0, len(ops))
+	for _, op := range ops {
+		changes = append(changes, op.Validator.Copy())
+	}
+
+	return changes
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/maticnetwork/heimdall/types"
+)
+
+// ValidatorJSON is the stable JSON form of a validator for external APIs.
+// Signer is checksummed hex, pubkey is 0x prefixed hex.
+type ValidatorJSON struct {
+	ID               uint64 `json:"id"`
+	StartEpoch       uint64 `json:"startEpoch"`
+	EndEpoch         uint64 `json:"endEpoch"`
+	Nonce            uint64 `json:"nonce"`
+	VotingPower      int64  `json:"votingPower"`
+	ProposerPriority int64  `json:"proposerPriority"`
+	PubKey           string `json:"pubKey"`
+	Signer           string `json:"signer"`
+	LastUpdated      string `json:"lastUpdated"`
+	Jailed           bool   `json:"jailed"`
+	SelfStake        int64  `json:"selfStake"`
+	DelegatedStake   int64  `json:"delegatedStake"`
+	CommissionRate   uint64 `json:"commissionRate"`
+}
+
+// ValidatorSetJSON is the stable JSON form of a validator set for external APIs, eg.
+//
+//	{
+//	  "validators": [{"id": 1, "votingPower": 10, "pubKey": "0x04...", "signer": "0x5A0b...", ...}],
+//	  "proposer": {"id": 1, ...},
+//	  "totalVotingPower": 10
+//	}
+type ValidatorSetJSON struct {
+	Validators       []ValidatorJSON `json:"validators"`
+	Proposer         *ValidatorJSON  `json:"proposer,omitempty"`
+	TotalVotingPower int64           `json:"totalVotingPower"`
+}
+
+// NewValidatorJSON converts a validator to its JSON form
+func NewValidatorJSON(validator types.Validator) ValidatorJSON {
+	return ValidatorJSON{
+		ID:               validator.ID.Uint64(),
+		StartEpoch:       validator.StartEpoch,
+		EndEpoch:         validator.EndEpoch,
+		Nonce:            validator.Nonce,
+		VotingPower:      validator.VotingPower,
+		ProposerPriority: validator.ProposerPriority,
+		PubKey:           validator.PubKey.String(),
+		Signer:           validator.Signer.EthAddress().Hex(),
+		LastUpdated:      validator.LastUpdated,
+		Jailed:           validator.Jailed,
+		SelfStake:        validator.SelfStake,
+		DelegatedStake:   validator.DelegatedStake,
+		CommissionRate:   validator.CommissionRate,
+	}
+}
+
+// ToValidator converts the JSON form back to a validator
+func (v ValidatorJSON) ToValidator() (validator types.Validator, err error) {
+	pubKey, err := hexutil.Decode(v.PubKey)
+	if err != nil {
+		return validator, fmt.Errorf("invalid pubkey of validator %v: %v", v.ID, err)
+	}
+	if len(pubKey) != len(types.PubKey{}) {
+		return validator, fmt.Errorf("invalid pubkey length %v of validator %v", len(pubKey), v.ID)
+	}
+
+	if !common.IsHexAddress(v.Signer) {
+		return validator, fmt.Errorf("invalid signer %v of validator %v", v.Signer, v.ID)
+	}
+
+	return types.Validator{
+		ID:               types.NewValidatorID(v.ID),
+		StartEpoch:       v.StartEpoch,
+		EndEpoch:         v.EndEpoch,
+		Nonce:            v.Nonce,
+		VotingPower:      v.VotingPower,
+		PubKey:           types.NewPubKey(pubKey),
+		Signer:           types.HexToHeimdallAddress(v.Signer),
+		LastUpdated:      v.LastUpdated,
+		Jailed:           v.Jailed,
+		ProposerPriority: v.ProposerPriority,
+		SelfStake:        v.SelfStake,
+		DelegatedStake:   v.DelegatedStake,
+		CommissionRate:   v.CommissionRate,
+	}, nil
+}
+
+// NewValidatorSetJSON converts a validator set to its JSON form
+func NewValidatorSetJSON(validatorSet types.ValidatorSet) ValidatorSetJSON {
+	result := ValidatorSetJSON{
+		Validators: make([]ValidatorJSON, 0, len(validatorSet.Validators)),
+	}
+
+	for _, validator := range validatorSet.Validators {
+		result.Validators = append(result.Validators, NewValidatorJSON(*validator))
+		result.TotalVotingPower += validator.VotingPower
+	}
+
+	if validatorSet.Proposer != nil {
+		proposer := NewValidatorJSON(*validatorSet.Proposer)
+		result.Proposer = &proposer
+	}
+
+	return result
+}
+
+// ToValidatorSet converts the JSON form back to a validator set
+func (vs ValidatorSetJSON) ToValidatorSet() (validatorSet types.ValidatorSet, err error) {
+	validatorSet.Validators = make([]*types.Validator, 0, len(vs.Validators))
+	for _, v := range vs.Validators {
+		validator, err := v.ToValidator()
+		if err != nil {
+			return validatorSet, err
+		}
+		validatorSet.Validators = append(validatorSet.Validators, &validator)
+	}
+
+	if vs.Proposer != nil {
+		proposer, err := vs.Proposer.ToValidator()
+		if err != nil {
+			return validatorSet, err
+		}
+		validatorSet.Proposer = &proposer
+	}
+
+	return validatorSet, nil
+}
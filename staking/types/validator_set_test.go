@@ -0,0 +1,47 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/maticnetwork/heimdall/types"
+)
+
+func TestValidatorSetJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	validators := make([]*types.Validator, 3)
+	for i := range validators {
+		pubKey := types.NewPubKey(secp256k1.GenPrivKey().PubKey().Bytes())
+		validators[i] = types.NewValidator(types.NewValidatorID(uint64(i+1)), 1, 0, 1, int64(10*(i+1)), pubKey, types.BytesToHeimdallAddress(pubKey.Address().Bytes()))
+		validators[i].SelfStake = int64(i)
+	}
+	validatorSet := types.ValidatorSet{Validators: validators, Proposer: validators[1].Copy()}
+
+	bz, err := json.Marshal(NewValidatorSetJSON(validatorSet))
+	require.NoError(t, err)
+
+	var decoded ValidatorSetJSON
+	require.NoError(t, json.Unmarshal(bz, &decoded))
+	require.Equal(t, int64(60), decoded.TotalVotingPower)
+
+	// signer is checksummed hex, pubkey is 0x prefixed hex
+	require.Equal(t, common.BytesToAddress(validators[0].Signer.Bytes()).Hex(), decoded.Validators[0].Signer)
+	require.NotEqual(t, strings.ToLower(decoded.Validators[0].Signer), decoded.Validators[0].Signer)
+	require.Equal(t, validators[0].PubKey.String(), decoded.Validators[0].PubKey)
+
+	result, err := decoded.ToValidatorSet()
+	require.NoError(t, err)
+	require.Equal(t, validatorSet.Validators, result.Validators)
+	require.Equal(t, validatorSet.Proposer, result.Proposer)
+
+	// malformed pubkey is rejected
+	decoded.Validators[0].PubKey = "0x1234"
+	_, err = decoded.ToValidatorSet()
+	require.Error(t, err)
+}
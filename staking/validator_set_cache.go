@@ -0,0 +1,56 @@
+package staking
+
+import (
+	"bytes"
+	"sync"
+
+	hmTypes "github.com/maticnetwork/heimdall/types"
+)
+
+// validatorSetCache memoizes the decoded current validator set by its stored bytes.
+// Keying on content rather than a write counter keeps reads consistent across heights and
+// store branches, e.g. a simulated tx writing a different set at the same point.
+type validatorSetCache struct {
+	mu           sync.Mutex
+	bz           []byte
+	validatorSet hmTypes.ValidatorSet
+}
+
+// get returns a copy of the cached set if it was decoded from bz, callers may mutate it
+func (c *validatorSetCache) get(bz []byte) (hmTypes.ValidatorSet, bool) {
+	if c == nil || len(bz) == 0 {
+		return hmTypes.ValidatorSet{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !bytes.Equal(c.bz, bz) {
+		return hmTypes.ValidatorSet{}, false
+	}
+
+	return copyValidatorSet(c.validatorSet), true
+}
+
+// set caches a copy of the set decoded from bz
+func (c *validatorSetCache) set(bz []byte, validatorSet hmTypes.ValidatorSet) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.bz = append([]byte(nil), bz...)
+	c.validatorSet = copyValidatorSet(validatorSet)
+}
+
+// copyValidatorSet deep copies validators and proposer
+func copyValidatorSet(validatorSet hmTypes.ValidatorSet) hmTypes.ValidatorSet {
+	copied := *validatorSet.Copy()
+	if validatorSet.Proposer != nil {
+		copied.Proposer = validatorSet.Proposer.Copy()
+	}
+
+	return copied
+}
@@ -0,0 +1,40 @@
+package tron
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "tron"
+
+var (
+	// rpcLatency tracks the duration of tron rpc calls, labeled by method
+	rpcLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "rpc",
+		Name:      "latency_seconds",
+		Help:      "Latency of tron rpc calls in seconds.",
+	}, []string{"method"})
+
+	// rpcErrors counts failed tron rpc calls, labeled by method
+	rpcErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "rpc",
+		Name:      "errors_total",
+		Help:      "Number of failed tron rpc calls.",
+	}, []string{"method"})
+)
+
+func init() {
+	prometheus.MustRegister(rpcLatency, rpcErrors)
+}
+
+// recordRPC reports latency and, on failure, an error for the given rpc method.
+// It is meant to be deferred with the call start time and a pointer to the named error result.
+func recordRPC(method string, start time.Time, err *error) {
+	rpcLatency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil && *err != nil {
+		rpcErrors.WithLabelValues(method).Inc()
+	}
+}
@@ -1,19 +1,82 @@
 package tron
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto/secp256k1"
 	"github.com/maticnetwork/heimdall/contracts/rootchain"
 	"github.com/maticnetwork/heimdall/tron/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 )
 
+// confirmationPollInterval is how often WaitForConfirmation polls, about one tron block
+var confirmationPollInterval = 3 * time.Second
+
+// GetNowBlockWithRetry attempts and initial backoff, doubled after every failed attempt
+var (
+	nowBlockAttempts = 3
+	nowBlockBackoff  = 500 * time.Millisecond
+)
+
+// broadcastWorkers bounds concurrent broadcasts of BroadcastTransactions
+var broadcastWorkers = 4
+
+// BroadcastResult is the outcome of broadcasting one transaction of a batch
+type BroadcastResult struct {
+	TxID string // hex sha256 of the raw data, empty if it can't be marshalled
+	Err  error
+}
+
+// ErrAccountNotFound is returned for accounts not activated on chain
+var ErrAccountNotFound = errors.New("tron account not found")
+
+// ErrEventMismatch is returned when decoding a log emitted for a different event
+var ErrEventMismatch = errors.New("log does not match event")
+
+// ErrTransactionNotFound is returned for transactions not yet included in a block
+var ErrTransactionNotFound = errors.New("tron transaction not found")
+
+// TransactionLog is a transaction log decoded with a contract ABI, see GetTransactionLogs
+type TransactionLog struct {
+	Event string                 // event name
+	Args  map[string]interface{} // indexed and non-indexed event arguments by name
+	Raw   types.Log
+}
+
+// AccountResource is the energy and bandwidth of an account
+type AccountResource struct {
+	EnergyLimit  int64
+	EnergyUsed   int64
+	NetLimit     int64 // staked bandwidth
+	NetUsed      int64
+	FreeNetLimit int64 // daily free bandwidth
+	FreeNetUsed  int64
+}
+
+// AvailableEnergy returns energy left for contract calls
+func (r AccountResource) AvailableEnergy() int64 {
+	return r.EnergyLimit - r.EnergyUsed
+}
+
+// AvailableBandwidth returns staked and free bandwidth left
+func (r AccountResource) AvailableBandwidth() int64 {
+	return r.NetLimit - r.NetUsed + r.FreeNetLimit - r.FreeNetUsed
+}
+
 // Client defines typed wrappers for the Tron RPC API.
 type Client struct {
 	client       pb.WalletClient
@@ -43,7 +106,9 @@ func getABI(data string) (abi.ABI, error) {
 	return abi.JSON(strings.NewReader(data))
 }
 
-func (tc *Client) TriggerContract(ownerAddress, contractAddress string, data []byte) (*pb.Transaction, error) {
+func (tc *Client) TriggerContract(ownerAddress, contractAddress string, data []byte) (_ *pb.Transaction, err error) {
+	defer recordRPC("TriggerContract", time.Now(), &err)
+
 	response, err := tc.client.TriggerContract(context.Background(),
 		&pb.TriggerSmartContract{
 			OwnerAddress:    common.FromHex("41" + ownerAddress),
@@ -62,7 +127,9 @@ func (tc *Client) TriggerContract(ownerAddress, contractAddress string, data []b
 	return response.Transaction, nil
 }
 
-func (tc *Client) TriggerConstantContract(contractAddress string, data []byte) ([]byte, error) {
+func (tc *Client) TriggerConstantContract(contractAddress string, data []byte) (_ []byte, err error) {
+	defer recordRPC("TriggerConstantContract", time.Now(), &err)
+
 	response, err := tc.client.TriggerConstantContract(context.Background(),
 		&pb.TriggerSmartContract{
 			OwnerAddress:    nil,
@@ -81,7 +148,9 @@ func (tc *Client) TriggerConstantContract(contractAddress string, data []byte) (
 	return response.ConstantResult[0], nil
 }
 
-func (tc *Client) GetNowBlock(ctx context.Context) (int64, error) {
+func (tc *Client) GetNowBlock(ctx context.Context) (_ int64, err error) {
+	defer recordRPC("GetNowBlock", time.Now(), &err)
+
 	block, err := tc.client.GetNowBlock2(ctx, &pb.EmptyMessage{})
 	if err != nil {
 		return 0, err
@@ -89,6 +158,54 @@ func (tc *Client) GetNowBlock(ctx context.Context) (int64, error) {
 	return block.BlockHeader.RawData.Number, nil
 }
 
+// GetNowBlockWithRetry retries GetNowBlock with backoff, it returns the last error once attempts are exhausted.
+// Context cancellation is not retried.
+func (tc *Client) GetNowBlockWithRetry(ctx context.Context) (int64, error) {
+	backoff := nowBlockBackoff
+
+	var err error
+	for attempt := 1; attempt <= nowBlockAttempts; attempt++ {
+		var number int64
+		if number, err = tc.GetNowBlock(ctx); err == nil {
+			return number, nil
+		}
+
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		if attempt == nowBlockAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return 0, err
+}
+
+// CallView packs a rootchain view call with the given args and returns the raw result, see UnpackInto
+func (tc *Client) CallView(contractAddress string, method string, args ...interface{}) ([]byte, error) {
+	// Pack the input
+	btsPack, err := tc.rootchainABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call
+	return tc.TriggerConstantContract(contractAddress, btsPack)
+}
+
+// UnpackInto unpacks the raw result of a rootchain view call into out
+func (tc *Client) UnpackInto(out interface{}, method string, data []byte) error {
+	return tc.rootchainABI.UnpackIntoInterface(out, method, data)
+}
+
 // CurrentHeaderBlock is a free data retrieval call binding the contract method 0xec7e4855.
 //
 // Solidity: function currentHeaderBlock() view returns(uint256)
@@ -140,7 +257,110 @@ func (tc *Client) GetLastChildBlock(contractAddress string) (uint64, error) {
 	return (*ret0).Uint64(), nil
 }
 
-func (tc *Client) BroadcastTransaction(ctx context.Context, trx *pb.Transaction) error {
+// DecodeNewHeaderBlockLog decodes a rootchain NewHeaderBlock log of a tron transaction info.
+// It returns ErrEventMismatch for logs of any other event.
+func (tc *Client) DecodeNewHeaderBlockLog(log *pb.TransactionInfo_Log) (*rootchain.RootchainNewHeaderBlock, error) {
+	event, ok := tc.rootchainABI.Events["NewHeaderBlock"]
+	if !ok {
+		return nil, errors.New("NewHeaderBlock event not found in rootchain abi")
+	}
+	if log == nil || len(log.Topics) == 0 || !bytes.Equal(log.Topics[0], event.ID.Bytes()) {
+		return nil, ErrEventMismatch
+	}
+
+	result := &rootchain.RootchainNewHeaderBlock{Raw: toEthLog(log)}
+	if err := tc.rootchainABI.UnpackIntoInterface(result, "NewHeaderBlock", log.Data); err != nil {
+		return nil, err
+	}
+
+	if err := abi.ParseTopics(result, indexedArgs(&event), result.Raw.Topics[1:]); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetTransactionLogs fetches the transaction info of txID, in hex, and decodes its logs with contractABI.
+// Logs of events not in contractABI are skipped, so a transaction without matching logs returns none.
+func (tc *Client) GetTransactionLogs(ctx context.Context, txID string, contractABI abi.ABI) ([]TransactionLog, error) {
+	id, err := hex.DecodeString(txID)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := tc.GetTransactionInfoByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// transaction info is empty until the transaction is included
+	if info.BlockNumber == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrTransactionNotFound, txID)
+	}
+
+	var logs []TransactionLog
+	for _, log := range info.Log {
+		if len(log.Topics) == 0 {
+			continue
+		}
+
+		event, err := contractABI.EventByID(common.BytesToHash(log.Topics[0]))
+		if err != nil {
+			continue
+		}
+
+		decoded := TransactionLog{
+			Event: event.Name,
+			Args:  make(map[string]interface{}),
+			Raw:   toEthLog(log),
+		}
+		decoded.Raw.TxHash = common.BytesToHash(id)
+		decoded.Raw.BlockNumber = uint64(info.BlockNumber)
+
+		if len(log.Data) > 0 {
+			if err := contractABI.UnpackIntoMap(decoded.Args, event.Name, log.Data); err != nil {
+				return nil, fmt.Errorf("decode %v log of transaction %v: %w", event.Name, txID, err)
+			}
+		}
+		if err := abi.ParseTopicsIntoMap(decoded.Args, indexedArgs(event), decoded.Raw.Topics[1:]); err != nil {
+			return nil, fmt.Errorf("decode %v log of transaction %v: %w", event.Name, txID, err)
+		}
+
+		logs = append(logs, decoded)
+	}
+
+	return logs, nil
+}
+
+// toEthLog converts a tron transaction info log to an ethereum log
+func toEthLog(log *pb.TransactionInfo_Log) types.Log {
+	topics := make([]common.Hash, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = common.BytesToHash(topic)
+	}
+
+	return types.Log{
+		Address: common.BytesToAddress(log.Address),
+		Topics:  topics,
+		Data:    log.Data,
+	}
+}
+
+// indexedArgs returns the indexed inputs of event
+func indexedArgs(event *abi.Event) abi.Arguments {
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+
+	return indexed
+}
+
+func (tc *Client) BroadcastTransaction(ctx context.Context, trx *pb.Transaction) (err error) {
+	defer recordRPC("BroadcastTransaction", time.Now(), &err)
+
 	result, err := tc.client.BroadcastTransaction(ctx, trx)
 	if err != nil {
 		return err
@@ -150,3 +370,135 @@ func (tc *Client) BroadcastTransaction(ctx context.Context, trx *pb.Transaction)
 	}
 	return nil
 }
+
+// BroadcastTransactions broadcasts transactions concurrently with a bounded number of workers.
+// Results are in input order, a failed broadcast doesn't stop the others.
+func (tc *Client) BroadcastTransactions(ctx context.Context, trxs []*pb.Transaction) []BroadcastResult {
+	results := make([]BroadcastResult, len(trxs))
+
+	workers := broadcastWorkers
+	if workers > len(trxs) {
+		workers = len(trxs)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				rawData, err := proto.Marshal(trxs[i].GetRawData())
+				if err != nil {
+					results[i].Err = err
+					continue
+				}
+				hash := sha256.Sum256(rawData)
+				results[i].TxID = hex.EncodeToString(hash[:])
+				results[i].Err = tc.BroadcastTransaction(ctx, trxs[i])
+			}
+		}()
+	}
+
+	for i := range trxs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// GetAccountResource returns energy and bandwidth of the owner account, in the hex form used by TriggerContract
+func (tc *Client) GetAccountResource(ctx context.Context, ownerAddress string) (_ *AccountResource, err error) {
+	defer recordRPC("GetAccountResource", time.Now(), &err)
+
+	response, err := tc.client.GetAccountResource(ctx, &pb.Account{Address: common.FromHex("41" + ownerAddress)})
+	if err != nil {
+		return nil, err
+	}
+
+	// unknown accounts get an empty message, active ones always have free bandwidth
+	if proto.Size(response) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrAccountNotFound, ownerAddress)
+	}
+
+	return &AccountResource{
+		EnergyLimit:  response.EnergyLimit,
+		EnergyUsed:   response.EnergyUsed,
+		NetLimit:     response.NetLimit,
+		NetUsed:      response.NetUsed,
+		FreeNetLimit: response.FreeNetLimit,
+		FreeNetUsed:  response.FreeNetUsed,
+	}, nil
+}
+
+func (tc *Client) GetTransactionInfoByID(ctx context.Context, txID []byte) (_ *pb.TransactionInfo, err error) {
+	defer recordRPC("GetTransactionInfoById", time.Now(), &err)
+
+	return tc.client.GetTransactionInfoById(ctx, &pb.BytesMessage{Value: txID})
+}
+
+// WaitForConfirmation polls until the transaction is included and buried under the given number
+// of blocks, or ctx is done. The returned info carries the final status in its Result.
+func (tc *Client) WaitForConfirmation(ctx context.Context, txID string, confirmations int64) (*pb.TransactionInfo, error) {
+	id, err := hex.DecodeString(txID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// transaction info is empty until the transaction is included
+		info, err := tc.GetTransactionInfoByID(ctx, id)
+		if err == nil && info.BlockNumber != 0 {
+			number, err := tc.GetNowBlockWithRetry(ctx)
+			if err == nil && number-info.BlockNumber >= confirmations {
+				return info, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// SubmitCheckpoint packs the submitCheckpoint call, triggers the rootchain contract,
+// signs the resulting transaction and broadcasts it. It returns the transaction hash.
+func (tc *Client) SubmitCheckpoint(ctx context.Context, ownerAddress, contractAddress string, checkpointData []byte, sigs [][3]*big.Int, feeLimit int64, privKey []byte) (string, error) {
+	// Pack the input
+	data, err := tc.rootchainABI.Pack("submitCheckpoint", checkpointData, sigs)
+	if err != nil {
+		return "", err
+	}
+
+	// trigger
+	trx, err := tc.TriggerContract(ownerAddress, contractAddress, data)
+	if err != nil {
+		return "", err
+	}
+	trx.RawData.FeeLimit = feeLimit
+
+	// sign
+	rawData, err := proto.Marshal(trx.GetRawData())
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(rawData)
+	signature, err := ethCrypto.Sign(hash[:], privKey)
+	if err != nil {
+		return "", err
+	}
+	trx.Signature = append(trx.GetSignature(), signature)
+
+	// broadcast
+	if err := tc.BroadcastTransaction(ctx, trx); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,484 @@
+package tron
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/maticnetwork/heimdall/contracts/rootchain"
+	"github.com/maticnetwork/heimdall/tron/pb"
+)
+
+// mockWalletClient records calls made through the wallet API
+type mockWalletClient struct {
+	pb.WalletClient
+
+	triggered   *pb.TriggerSmartContract
+	broadcasted *pb.Transaction
+}
+
+func (m *mockWalletClient) TriggerContract(_ context.Context, in *pb.TriggerSmartContract, _ ...grpc.CallOption) (*pb.TransactionExtention, error) {
+	m.triggered = in
+	return &pb.TransactionExtention{
+		Transaction: &pb.Transaction{RawData: &pb.TransactionRaw{Timestamp: 1}},
+		Result:      &pb.Return{Code: pb.Return_SUCCESS},
+	}, nil
+}
+
+func (m *mockWalletClient) BroadcastTransaction(_ context.Context, in *pb.Transaction, _ ...grpc.CallOption) (*pb.Return, error) {
+	m.broadcasted = in
+	return &pb.Return{Code: pb.Return_SUCCESS}, nil
+}
+
+func newTestClient(t *testing.T, wallet pb.WalletClient) *Client {
+	rootchainABI, err := getABI(rootchain.RootchainABI)
+	require.NoError(t, err)
+	return &Client{client: wallet, rootchainABI: rootchainABI}
+}
+
+func TestSubmitCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	wallet := &mockWalletClient{}
+	tc := newTestClient(t, wallet)
+
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	checkpointData := []byte("checkpoint")
+	sigs := [][3]*big.Int{{big.NewInt(1), big.NewInt(2), big.NewInt(3)}}
+	ownerAddress := "1111111111111111111111111111111111111111"
+	contractAddress := "412222222222222222222222222222222222222222"
+
+	txHash, err := tc.SubmitCheckpoint(context.Background(), ownerAddress, contractAddress, checkpointData, sigs, 1000, crypto.FromECDSA(privKey))
+	require.NoError(t, err)
+
+	// trigger received the packed submitCheckpoint call
+	expectedData, err := tc.rootchainABI.Pack("submitCheckpoint", checkpointData, sigs)
+	require.NoError(t, err)
+	require.NotNil(t, wallet.triggered)
+	require.Equal(t, expectedData, wallet.triggered.Data)
+	require.Equal(t, common.FromHex("41"+ownerAddress), wallet.triggered.OwnerAddress)
+	require.Equal(t, common.FromHex(contractAddress), wallet.triggered.ContractAddress)
+
+	// broadcasted transaction is signed with fee limit set
+	require.NotNil(t, wallet.broadcasted)
+	require.Equal(t, int64(1000), wallet.broadcasted.RawData.FeeLimit)
+	require.Len(t, wallet.broadcasted.Signature, 1)
+
+	rawData, err := proto.Marshal(wallet.broadcasted.RawData)
+	require.NoError(t, err)
+	hash := sha256.Sum256(rawData)
+	require.Equal(t, hex.EncodeToString(hash[:]), txHash)
+
+	pubKey, err := crypto.SigToPub(hash[:], wallet.broadcasted.Signature[0])
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(privKey.PublicKey), crypto.PubkeyToAddress(*pubKey))
+}
+
+// blockWalletClient serves GetNowBlock2, failing when err is set
+type blockWalletClient struct {
+	pb.WalletClient
+
+	err error
+}
+
+func (m *blockWalletClient) GetNowBlock2(_ context.Context, _ *pb.EmptyMessage, _ ...grpc.CallOption) (*pb.BlockExtention, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.BlockExtention{BlockHeader: &pb.BlockHeader{RawData: &pb.BlockHeaderRaw{Number: 10}}}, nil
+}
+
+func latencySampleCount(t *testing.T, method string) uint64 {
+	var metric dto.Metric
+	require.NoError(t, rpcLatency.WithLabelValues(method).(prometheus.Metric).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestRPCMetrics(t *testing.T) {
+	wallet := &blockWalletClient{}
+	tc := newTestClient(t, wallet)
+
+	samples := latencySampleCount(t, "GetNowBlock")
+	failures := testutil.ToFloat64(rpcErrors.WithLabelValues("GetNowBlock"))
+
+	// successful call only records latency
+	number, err := tc.GetNowBlock(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(10), number)
+	require.Equal(t, samples+1, latencySampleCount(t, "GetNowBlock"))
+	require.Equal(t, failures, testutil.ToFloat64(rpcErrors.WithLabelValues("GetNowBlock")))
+
+	// failing call records latency and an error
+	wallet.err = fmt.Errorf("unavailable")
+	_, err = tc.GetNowBlock(context.Background())
+	require.Error(t, err)
+	require.Equal(t, samples+2, latencySampleCount(t, "GetNowBlock"))
+	require.Equal(t, failures+1, testutil.ToFloat64(rpcErrors.WithLabelValues("GetNowBlock")))
+}
+
+// confirmationWalletClient includes the transaction at block 100 on the second poll,
+// advancing the current block by one on every call
+type confirmationWalletClient struct {
+	pb.WalletClient
+
+	polls     int
+	nowBlock  int64
+	requested []byte
+}
+
+func (m *confirmationWalletClient) GetTransactionInfoById(_ context.Context, in *pb.BytesMessage, _ ...grpc.CallOption) (*pb.TransactionInfo, error) {
+	m.polls++
+	m.requested = in.Value
+	if m.polls < 2 {
+		return &pb.TransactionInfo{}, nil
+	}
+	return &pb.TransactionInfo{Id: in.Value, BlockNumber: 100, Result: pb.TransactionInfo_SUCESS}, nil
+}
+
+func (m *confirmationWalletClient) GetNowBlock2(_ context.Context, _ *pb.EmptyMessage, _ ...grpc.CallOption) (*pb.BlockExtention, error) {
+	m.nowBlock++
+	return &pb.BlockExtention{BlockHeader: &pb.BlockHeader{RawData: &pb.BlockHeaderRaw{Number: 100 + m.nowBlock}}}, nil
+}
+
+func TestWaitForConfirmation(t *testing.T) {
+	interval := confirmationPollInterval
+	confirmationPollInterval = time.Millisecond
+	defer func() { confirmationPollInterval = interval }()
+
+	wallet := &confirmationWalletClient{}
+	tc := newTestClient(t, wallet)
+
+	// confirmed once buried under 2 blocks
+	info, err := tc.WaitForConfirmation(context.Background(), "abcd", 2)
+	require.NoError(t, err)
+	require.Equal(t, pb.TransactionInfo_SUCESS, info.Result)
+	require.Equal(t, int64(100), info.BlockNumber)
+	require.Equal(t, []byte{0xab, 0xcd}, wallet.requested)
+	require.Equal(t, 3, wallet.polls)
+
+	// context expires before confirmation
+	wallet.polls, wallet.nowBlock = 0, 0
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = tc.WaitForConfirmation(ctx, "abcd", 1000)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// invalid transaction id
+	_, err = tc.WaitForConfirmation(context.Background(), "xyz", 1)
+	require.Error(t, err)
+}
+
+// constantWalletClient serves TriggerConstantContract with a fixed result
+type constantWalletClient struct {
+	pb.WalletClient
+
+	result []byte
+}
+
+func (m *constantWalletClient) TriggerConstantContract(_ context.Context, _ *pb.TriggerSmartContract, _ ...grpc.CallOption) (*pb.TransactionExtention, error) {
+	return &pb.TransactionExtention{
+		Transaction:    &pb.Transaction{Ret: []*pb.Transaction_Result{{Ret: pb.Transaction_Result_SUCESS}}},
+		Result:         &pb.Return{Code: pb.Return_SUCCESS},
+		ConstantResult: [][]byte{m.result},
+	}, nil
+}
+
+func TestCallView(t *testing.T) {
+	t.Parallel()
+
+	wallet := &constantWalletClient{}
+	tc := newTestClient(t, wallet)
+
+	result, err := tc.rootchainABI.Methods["getLastChildBlock"].Outputs.Pack(big.NewInt(1234))
+	require.NoError(t, err)
+	wallet.result = result
+
+	contractAddress := "412222222222222222222222222222222222222222"
+	expected, err := tc.GetLastChildBlock(contractAddress)
+	require.NoError(t, err)
+
+	// generic path matches the typed method
+	data, err := tc.CallView(contractAddress, "getLastChildBlock")
+	require.NoError(t, err)
+	out := new(*big.Int)
+	require.NoError(t, tc.UnpackInto(out, "getLastChildBlock", data))
+	require.Equal(t, expected, (*out).Uint64())
+	require.Equal(t, uint64(1234), expected)
+
+	// unknown method fails to pack
+	_, err = tc.CallView(contractAddress, "unknownView")
+	require.Error(t, err)
+}
+
+// flakyBlockWalletClient fails GetNowBlock2 the given number of times before serving a block
+type flakyBlockWalletClient struct {
+	pb.WalletClient
+
+	failures int
+	calls    int
+}
+
+func (m *flakyBlockWalletClient) GetNowBlock2(_ context.Context, _ *pb.EmptyMessage, _ ...grpc.CallOption) (*pb.BlockExtention, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		return nil, fmt.Errorf("unavailable")
+	}
+	return &pb.BlockExtention{BlockHeader: &pb.BlockHeader{RawData: &pb.BlockHeaderRaw{Number: 10}}}, nil
+}
+
+func TestGetNowBlockWithRetry(t *testing.T) {
+	backoff := nowBlockBackoff
+	nowBlockBackoff = time.Millisecond
+	defer func() { nowBlockBackoff = backoff }()
+
+	// fails twice, then returns the block
+	wallet := &flakyBlockWalletClient{failures: 2}
+	number, err := newTestClient(t, wallet).GetNowBlockWithRetry(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(10), number)
+	require.Equal(t, 3, wallet.calls)
+
+	// last error is returned once attempts are exhausted
+	wallet = &flakyBlockWalletClient{failures: nowBlockAttempts}
+	_, err = newTestClient(t, wallet).GetNowBlockWithRetry(context.Background())
+	require.EqualError(t, err, "unavailable")
+	require.Equal(t, nowBlockAttempts, wallet.calls)
+
+	// cancellation is not retried
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	wallet = &flakyBlockWalletClient{failures: nowBlockAttempts}
+	_, err = newTestClient(t, wallet).GetNowBlockWithRetry(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, wallet.calls)
+}
+
+// batchWalletClient broadcasts concurrently, rejecting transactions with the given timestamps
+type batchWalletClient struct {
+	pb.WalletClient
+
+	mu          sync.Mutex
+	rejected    map[int64]bool
+	broadcasted []int64
+	active      int
+	maxActive   int
+}
+
+func (m *batchWalletClient) BroadcastTransaction(_ context.Context, in *pb.Transaction, _ ...grpc.CallOption) (*pb.Return, error) {
+	m.mu.Lock()
+	m.broadcasted = append(m.broadcasted, in.RawData.Timestamp)
+	m.active++
+	if m.active > m.maxActive {
+		m.maxActive = m.active
+	}
+	m.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	m.mu.Lock()
+	m.active--
+	m.mu.Unlock()
+
+	if m.rejected[in.RawData.Timestamp] {
+		return &pb.Return{Code: pb.Return_CONTRACT_VALIDATE_ERROR, Message: []byte("rejected")}, nil
+	}
+	return &pb.Return{Code: pb.Return_SUCCESS}, nil
+}
+
+func TestBroadcastTransactions(t *testing.T) {
+	wallet := &batchWalletClient{rejected: map[int64]bool{3: true}}
+	tc := newTestClient(t, wallet)
+
+	var trxs []*pb.Transaction
+	for i := int64(1); i <= 10; i++ {
+		trxs = append(trxs, &pb.Transaction{RawData: &pb.TransactionRaw{Timestamp: i}})
+	}
+
+	results := tc.BroadcastTransactions(context.Background(), trxs)
+	require.Len(t, results, len(trxs))
+	require.Len(t, wallet.broadcasted, len(trxs))
+	require.LessOrEqual(t, wallet.maxActive, broadcastWorkers)
+
+	// results follow input order
+	for i, trx := range trxs {
+		rawData, err := proto.Marshal(trx.RawData)
+		require.NoError(t, err)
+		hash := sha256.Sum256(rawData)
+		require.Equal(t, hex.EncodeToString(hash[:]), results[i].TxID)
+
+		if trx.RawData.Timestamp == 3 {
+			require.EqualError(t, results[i].Err, "code:CONTRACT_VALIDATE_ERROR message:rejected")
+		} else {
+			require.NoError(t, results[i].Err)
+		}
+	}
+
+	// empty batch
+	require.Empty(t, tc.BroadcastTransactions(context.Background(), nil))
+}
+
+// resourceWalletClient serves account resources by owner address
+type resourceWalletClient struct {
+	pb.WalletClient
+
+	resources map[string]*pb.AccountResourceMessage
+}
+
+func (m *resourceWalletClient) GetAccountResource(_ context.Context, in *pb.Account, _ ...grpc.CallOption) (*pb.AccountResourceMessage, error) {
+	if resource, ok := m.resources[hex.EncodeToString(in.Address)]; ok {
+		return resource, nil
+	}
+	return &pb.AccountResourceMessage{}, nil
+}
+
+func TestGetAccountResource(t *testing.T) {
+	ownerAddress := "1111111111111111111111111111111111111111"
+	wallet := &resourceWalletClient{resources: map[string]*pb.AccountResourceMessage{
+		"41" + ownerAddress: {
+			EnergyLimit:  1000,
+			EnergyUsed:   400,
+			NetLimit:     300,
+			NetUsed:      100,
+			FreeNetLimit: 1500,
+			FreeNetUsed:  500,
+		},
+	}}
+	tc := newTestClient(t, wallet)
+
+	resource, err := tc.GetAccountResource(context.Background(), ownerAddress)
+	require.NoError(t, err)
+	require.Equal(t, &AccountResource{
+		EnergyLimit:  1000,
+		EnergyUsed:   400,
+		NetLimit:     300,
+		NetUsed:      100,
+		FreeNetLimit: 1500,
+		FreeNetUsed:  500,
+	}, resource)
+	require.Equal(t, int64(600), resource.AvailableEnergy())
+	require.Equal(t, int64(1200), resource.AvailableBandwidth())
+
+	// unknown account
+	_, err = tc.GetAccountResource(context.Background(), "2222222222222222222222222222222222222222")
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestDecodeNewHeaderBlockLog(t *testing.T) {
+	t.Parallel()
+
+	tc := newTestClient(t, &mockWalletClient{})
+
+	// NewHeaderBlock(proposer, headerBlockId 20000, reward 0) emitted for blocks 0-255
+	root := common.HexToHash("0x5f5f1d3b2a2cb8d84bb5a1b8b3d42c8e7a1e9e0a4b0f7c6d3e2a1b0c9d8e7f60")
+	log := &pb.TransactionInfo_Log{
+		Address: common.FromHex("2222222222222222222222222222222222222222"),
+		Topics: [][]byte{
+			common.FromHex("0xba5de06d22af2685c6c7765f60067f7d2b08c2d29f53cdf14d67f6d1c9bfb527"),
+			common.FromHex("0x0000000000000000000000001111111111111111111111111111111111111111"),
+			common.FromHex("0x0000000000000000000000000000000000000000000000000000000000004e20"),
+			common.FromHex("0x0000000000000000000000000000000000000000000000000000000000000000"),
+		},
+		Data: common.FromHex("0x" +
+			"0000000000000000000000000000000000000000000000000000000000000000" +
+			"00000000000000000000000000000000000000000000000000000000000000ff" +
+			root.Hex()[2:]),
+	}
+
+	event, err := tc.DecodeNewHeaderBlockLog(log)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("1111111111111111111111111111111111111111"), event.Proposer)
+	require.Equal(t, uint64(20000), event.HeaderBlockId.Uint64())
+	require.Equal(t, uint64(0), event.Reward.Uint64())
+	require.Equal(t, uint64(0), event.Start.Uint64())
+	require.Equal(t, uint64(255), event.End.Uint64())
+	require.Equal(t, [32]byte(root), event.Root)
+	require.Equal(t, common.HexToAddress("2222222222222222222222222222222222222222"), event.Raw.Address)
+
+	// logs of other events are rejected
+	other := &pb.TransactionInfo_Log{Topics: [][]byte{crypto.Keccak256([]byte("Other(uint256)"))}, Data: log.Data}
+	_, err = tc.DecodeNewHeaderBlockLog(other)
+	require.ErrorIs(t, err, ErrEventMismatch)
+
+	_, err = tc.DecodeNewHeaderBlockLog(&pb.TransactionInfo_Log{})
+	require.ErrorIs(t, err, ErrEventMismatch)
+}
+
+// transactionInfoWalletClient serves GetTransactionInfoById with a fixed info
+type transactionInfoWalletClient struct {
+	pb.WalletClient
+
+	info *pb.TransactionInfo
+}
+
+func (m *transactionInfoWalletClient) GetTransactionInfoById(_ context.Context, _ *pb.BytesMessage, _ ...grpc.CallOption) (*pb.TransactionInfo, error) {
+	return m.info, nil
+}
+
+func TestGetTransactionLogs(t *testing.T) {
+	t.Parallel()
+
+	// NewHeaderBlock(proposer, headerBlockId 20000, reward 0) emitted for blocks 0-255, after a log of another contract
+	root := common.HexToHash("0x5f5f1d3b2a2cb8d84bb5a1b8b3d42c8e7a1e9e0a4b0f7c6d3e2a1b0c9d8e7f60")
+	headerBlockLog := &pb.TransactionInfo_Log{
+		Address: common.FromHex("2222222222222222222222222222222222222222"),
+		Topics: [][]byte{
+			common.FromHex("0xba5de06d22af2685c6c7765f60067f7d2b08c2d29f53cdf14d67f6d1c9bfb527"),
+			common.FromHex("0x0000000000000000000000001111111111111111111111111111111111111111"),
+			common.FromHex("0x0000000000000000000000000000000000000000000000000000000000004e20"),
+			common.FromHex("0x0000000000000000000000000000000000000000000000000000000000000000"),
+		},
+		Data: common.FromHex("0x" +
+			"0000000000000000000000000000000000000000000000000000000000000000" +
+			"00000000000000000000000000000000000000000000000000000000000000ff" +
+			root.Hex()[2:]),
+	}
+	otherLog := &pb.TransactionInfo_Log{Topics: [][]byte{crypto.Keccak256([]byte("Other(uint256)"))}}
+
+	wallet := &transactionInfoWalletClient{
+		info: &pb.TransactionInfo{BlockNumber: 100, Log: []*pb.TransactionInfo_Log{otherLog, headerBlockLog}},
+	}
+	tc := newTestClient(t, wallet)
+
+	logs, err := tc.GetTransactionLogs(context.Background(), "abcd", tc.rootchainABI)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "NewHeaderBlock", logs[0].Event)
+	require.Equal(t, common.HexToAddress("1111111111111111111111111111111111111111"), logs[0].Args["proposer"])
+	require.Equal(t, uint64(20000), logs[0].Args["headerBlockId"].(*big.Int).Uint64())
+	require.Equal(t, uint64(255), logs[0].Args["end"].(*big.Int).Uint64())
+	require.Equal(t, [32]byte(root), logs[0].Args["root"])
+	require.Equal(t, common.HexToAddress("2222222222222222222222222222222222222222"), logs[0].Raw.Address)
+	require.Equal(t, uint64(100), logs[0].Raw.BlockNumber)
+	require.Equal(t, common.BytesToHash([]byte{0xab, 0xcd}), logs[0].Raw.TxHash)
+
+	// no matching logs
+	wallet.info = &pb.TransactionInfo{BlockNumber: 100, Log: []*pb.TransactionInfo_Log{otherLog}}
+	logs, err = tc.GetTransactionLogs(context.Background(), "abcd", tc.rootchainABI)
+	require.NoError(t, err)
+	require.Empty(t, logs)
+
+	// not yet included
+	wallet.info = &pb.TransactionInfo{}
+	_, err = tc.GetTransactionLogs(context.Background(), "abcd", tc.rootchainABI)
+	require.ErrorIs(t, err, ErrTransactionNotFound)
+
+	// invalid transaction id
+	_, err = tc.GetTransactionLogs(context.Background(), "xyz", tc.rootchainABI)
+	require.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -129,6 +130,15 @@ func BytesToHeimdallAddress(b []byte) HeimdallAddress {
 	return HeimdallAddress(common.BytesToAddress(b))
 }
 
+// EthAddressToHeimdallAddress converts an ethereum address to heimdall address,
+// rejecting the zero address.
+func EthAddressToHeimdallAddress(address common.Address) (HeimdallAddress, error) {
+	if address == (common.Address{}) {
+		return ZeroHeimdallAddress, errors.New("invalid zero address")
+	}
+	return HeimdallAddress(address), nil
+}
+
 // HexToHeimdallAddress returns Address with value b.
 func HexToHeimdallAddress(b string) HeimdallAddress {
 	return HeimdallAddress(common.HexToAddress(b))
@@ -0,0 +1,21 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthAddressToHeimdallAddress(t *testing.T) {
+	address := common.HexToAddress("0x6C468CF8C9879006E22EC4029696E005C2319C9D")
+
+	heimdallAddress, err := EthAddressToHeimdallAddress(address)
+	require.NoError(t, err)
+	require.Equal(t, HexToHeimdallAddress("6C468CF8C9879006E22EC4029696E005C2319C9D"), heimdallAddress)
+	require.Equal(t, address, heimdallAddress.EthAddress())
+
+	// zero address is rejected
+	_, err = EthAddressToHeimdallAddress(common.Address{})
+	require.Error(t, err)
+}
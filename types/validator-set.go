@@ -316,6 +316,29 @@ func (vals *ValidatorSet) Hash() []byte {
 	return merkle.SimpleHashFromByteSlices(bzs)
 }
 
+// Equal returns true if both sets have the same validators, in the same order, with
+// identical powers and priorities, and the same proposer.
+func (vals *ValidatorSet) Equal(other *ValidatorSet) bool {
+	if len(vals.Validators) != len(other.Validators) {
+		return false
+	}
+
+	for i, val := range vals.Validators {
+		if !validatorsEqual(val, other.Validators[i]) {
+			return false
+		}
+	}
+
+	return validatorsEqual(vals.Proposer, other.Proposer)
+}
+
+func validatorsEqual(a, b *Validator) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // Iterate will run the given function over the set.
 func (vals *ValidatorSet) Iterate(fn func(index int, val *Validator) bool) {
 	for i, val := range vals.Validators {
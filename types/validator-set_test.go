@@ -46,3 +46,84 @@ func TestUpdateChanges(t *testing.T) {
 		t.Errorf("expected: %v, but got %v", v2.Signer, vset1.GetProposer().Signer)
 	}
 }
+
+func TestValidatorSetEqual(t *testing.T) {
+	v1 := &Validator{
+		ID:          1,
+		VotingPower: 10,
+		PubKey:      StringToPubkey("04b12d8b2f6e3d45a7ace12c4b2158f79b95e4c28ebe5ad54c439be9431d7fc9dc1164210bf6a5c3b8523528b931e772c86a307e8cff4b725e6b4a77d21417bf19"),
+		Signer:      HexToHeimdallAddress("6C468CF8C9879006E22EC4029696E005C2319C9D"),
+	}
+
+	v2 := &Validator{
+		ID:          2,
+		VotingPower: 20,
+		PubKey:      StringToPubkey("04914873c8d5935837ade39cbdabd6efb3d3d4064c5918da11e555bba0ab2c58fee95974a3222830cf73d257bdc18cfcd01765482108a48e68bc0b657618acb40e"),
+		Signer:      HexToHeimdallAddress("9fB29AAc15b9A4B7F17c3385939b007540f4d791"),
+	}
+
+	vset := NewValidatorSet([]*Validator{v1, v2})
+
+	if copied := vset.Copy(); !vset.Equal(copied) {
+		t.Errorf("expected copied set to be equal")
+	}
+
+	// changed power
+	changed := vset.Copy()
+	changed.Validators[0].VotingPower++
+	if vset.Equal(changed) {
+		t.Errorf("expected set with changed power to differ")
+	}
+
+	// changed proposer priority
+	rotated := vset.CopyIncrementProposerPriority(1)
+	if vset.Equal(rotated) {
+		t.Errorf("expected set with rotated priorities to differ")
+	}
+
+	// changed membership
+	if vset.Equal(NewValidatorSet([]*Validator{v1})) {
+		t.Errorf("expected set with different members to differ")
+	}
+}
+
+func TestProposerPriorityTieBreak(t *testing.T) {
+	low := &Validator{
+		ID:          1,
+		VotingPower: 10,
+		PubKey:      StringToPubkey("04914873c8d5935837ade39cbdabd6efb3d3d4064c5918da11e555bba0ab2c58fee95974a3222830cf73d257bdc18cfcd01765482108a48e68bc0b657618acb40e"),
+		Signer:      HexToHeimdallAddress("6C468CF8C9879006E22EC4029696E005C2319C9D"),
+	}
+
+	high := &Validator{
+		ID:          2,
+		VotingPower: 10,
+		PubKey:      StringToPubkey("04b12d8b2f6e3d45a7ace12c4b2158f79b95e4c28ebe5ad54c439be9431d7fc9dc1164210bf6a5c3b8523528b931e772c86a307e8cff4b725e6b4a77d21417bf19"),
+		Signer:      HexToHeimdallAddress("9fB29AAc15b9A4B7F17c3385939b007540f4d791"),
+	}
+
+	// same power and priority, lower signer wins in either order
+	if !low.CompareProposerPriority(high).Signer.Equals(low.Signer) {
+		t.Errorf("expected: %v, but got %v", low.Signer, low.CompareProposerPriority(high).Signer)
+	}
+	if !high.CompareProposerPriority(low).Signer.Equals(low.Signer) {
+		t.Errorf("expected: %v, but got %v", low.Signer, high.CompareProposerPriority(low).Signer)
+	}
+
+	for _, order := range [][]*Validator{{low, high}, {high, low}} {
+		vset := &ValidatorSet{Validators: []*Validator{order[0].Copy(), order[1].Copy()}}
+		if proposer := vset.findProposer(); !proposer.Signer.Equals(low.Signer) {
+			t.Errorf("expected: %v, but got %v", low.Signer, proposer.Signer)
+		}
+
+		// fresh set breaks the initial tie the same way, then rotates
+		vset = NewValidatorSet([]*Validator{order[0].Copy(), order[1].Copy()})
+		if proposer := vset.GetProposer(); !proposer.Signer.Equals(low.Signer) {
+			t.Errorf("expected: %v, but got %v", low.Signer, proposer.Signer)
+		}
+		vset.IncrementProposerPriority(1)
+		if proposer := vset.GetProposer(); !proposer.Signer.Equals(high.Signer) {
+			t.Errorf("expected: %v, but got %v", high.Signer, proposer.Signer)
+		}
+	}
+}
@@ -24,8 +24,16 @@ type Validator struct {
 
 	Jailed           bool  `json:"jailed"`
 	ProposerPriority int64 `json:"accum"`
+
+	SelfStake      int64 `json:"selfStake"`      // validator's own stake, in power units
+	DelegatedStake int64 `json:"delegatedStake"` // stake delegated to validator, in power units, no event delegates yet
+
+	CommissionRate uint64 `json:"commissionRate"` // commission on delegator rewards, in basis points
 }
 
+// MaxCommissionRate is a commission rate of 100%, in basis points
+const MaxCommissionRate = uint64(10000)
+
 // NewValidator func creates a new validator,
 // the HeimdallAddress field is generated using Address i.e. [20]byte
 func NewValidator(
@@ -59,17 +67,52 @@ func SortValidatorByAddress(a []Validator) []Validator {
 
 // IsCurrentValidator checks if validator is in current validator set
 func (v *Validator) IsCurrentValidator(ackCount uint64) bool {
+	return v.IsCurrentValidatorWithGrace(ackCount, 0)
+}
+
+// IsCurrentValidatorWithGrace checks if validator is in current validator set,
+// keeping it current for grace epochs after EndEpoch. All nodes must use the same grace,
+// it decides validator set membership.
+func (v *Validator) IsCurrentValidatorWithGrace(ackCount uint64, grace uint64) bool {
+	validator := *v
+	if validator.EndEpoch != 0 {
+		validator.EndEpoch += grace
+	}
+
 	// current epoch will be ack count + 1
-	currentEpoch := ackCount + 1
+	return validator.IsActiveAtEpoch(ackCount + 1)
+}
 
+// IsActiveAtEpoch checks if validator is active at the given epoch, unset end epoch means active indefinitely
+func (v *Validator) IsActiveAtEpoch(epoch uint64) bool {
 	// validator hasnt initialised unstake
-	if !v.Jailed && v.StartEpoch <= currentEpoch && (v.EndEpoch == 0 || v.EndEpoch > currentEpoch) && v.VotingPower > 0 {
+	if !v.Jailed && v.StartEpoch <= epoch && (v.EndEpoch == 0 || v.EndEpoch > epoch) && v.VotingPower > 0 {
 		return true
 	}
 
 	return false
 }
 
+// GetSelfStake returns validator's own stake
+func (v *Validator) GetSelfStake() int64 {
+	return v.SelfStake
+}
+
+// GetDelegatedStake returns stake delegated to validator
+func (v *Validator) GetDelegatedStake() int64 {
+	return v.DelegatedStake
+}
+
+// GetCommissionRate returns validator's commission rate, in basis points
+func (v *Validator) GetCommissionRate() uint64 {
+	return v.CommissionRate
+}
+
+// GetTotalStake returns total stake, i.e. self stake plus delegated stake
+func (v *Validator) GetTotalStake() int64 {
+	return v.SelfStake + v.DelegatedStake
+}
+
 // Validates validator
 func (v *Validator) ValidateBasic() bool {
 	if bytes.Equal(v.PubKey.Bytes(), ZeroPubKey.Bytes()) {
@@ -109,6 +152,8 @@ func (v *Validator) Copy() *Validator {
 }
 
 // Returns the one with higher ProposerPriority.
+// Ties are broken by the lower signer address, so every node picks the same proposer
+// regardless of validator order.
 func (v *Validator) CompareProposerPriority(other *Validator) *Validator {
 	if v == nil {
 		return other
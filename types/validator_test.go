@@ -87,6 +87,14 @@ func TestSortValidatorByAddress(t *testing.T) {
 	}
 }
 
+func TestValidatorStake(t *testing.T) {
+	validator := Validator{SelfStake: 100, DelegatedStake: 40}
+
+	assert.Equal(t, int64(100), validator.GetSelfStake())
+	assert.Equal(t, int64(40), validator.GetDelegatedStake())
+	assert.Equal(t, validator.GetSelfStake()+validator.GetDelegatedStake(), validator.GetTotalStake())
+}
+
 func TestValidateBasic(t *testing.T) {
 	neg1, uNeg1 := uint64(1), uint64(0)
 	uNeg1 = uNeg1 - neg1